@@ -177,8 +177,11 @@ func (MemberRole) EnumDescriptor() ([]byte, []int) {
 }
 
 type ConnectRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	LastEventId   *string                `protobuf:"bytes,1,opt,name=last_event_id,json=lastEventId,proto3,oneof" json:"last_event_id,omitempty"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Resumes the stream after this event. Only a non-empty ConnectResponse
+	// id is valid here: those are the events stored in the log. Typing
+	// indicators and notifications that are not stored carry an empty id.
+	LastEventId   *string `protobuf:"bytes,1,opt,name=last_event_id,json=lastEventId,proto3,oneof" json:"last_event_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -222,7 +225,8 @@ func (x *ConnectRequest) GetLastEventId() string {
 
 type ConnectResponse struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
-	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Empty for events that are not stored and cannot be resumed from.
+	Id string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	// Types that are valid to be assigned to Payload:
 	//
 	//	*ConnectResponse_MessageNew
@@ -231,6 +235,9 @@ type ConnectResponse struct {
 	//	*ConnectResponse_Typing
 	//	*ConnectResponse_ReadReceipt
 	//	*ConnectResponse_System
+	//	*ConnectResponse_Snapshot
+	//	*ConnectResponse_ReactionUpdated
+	//	*ConnectResponse_ThreadUpdated
 	Payload       isConnectResponse_Payload `protobuf_oneof:"payload"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
@@ -334,6 +341,33 @@ func (x *ConnectResponse) GetSystem() *SystemNotification {
 	return nil
 }
 
+func (x *ConnectResponse) GetSnapshot() *Snapshot {
+	if x != nil {
+		if x, ok := x.Payload.(*ConnectResponse_Snapshot); ok {
+			return x.Snapshot
+		}
+	}
+	return nil
+}
+
+func (x *ConnectResponse) GetReactionUpdated() *ReactionUpdated {
+	if x != nil {
+		if x, ok := x.Payload.(*ConnectResponse_ReactionUpdated); ok {
+			return x.ReactionUpdated
+		}
+	}
+	return nil
+}
+
+func (x *ConnectResponse) GetThreadUpdated() *ThreadUpdated {
+	if x != nil {
+		if x, ok := x.Payload.(*ConnectResponse_ThreadUpdated); ok {
+			return x.ThreadUpdated
+		}
+	}
+	return nil
+}
+
 type isConnectResponse_Payload interface {
 	isConnectResponse_Payload()
 }
@@ -362,6 +396,18 @@ type ConnectResponse_System struct {
 	System *SystemNotification `protobuf:"bytes,8,opt,name=system,proto3,oneof"`
 }
 
+type ConnectResponse_Snapshot struct {
+	Snapshot *Snapshot `protobuf:"bytes,9,opt,name=snapshot,proto3,oneof"`
+}
+
+type ConnectResponse_ReactionUpdated struct {
+	ReactionUpdated *ReactionUpdated `protobuf:"bytes,10,opt,name=reaction_updated,json=reactionUpdated,proto3,oneof"`
+}
+
+type ConnectResponse_ThreadUpdated struct {
+	ThreadUpdated *ThreadUpdated `protobuf:"bytes,11,opt,name=thread_updated,json=threadUpdated,proto3,oneof"`
+}
+
 func (*ConnectResponse_MessageNew) isConnectResponse_Payload() {}
 
 func (*ConnectResponse_MessageUpdated) isConnectResponse_Payload() {}
@@ -374,29 +420,35 @@ func (*ConnectResponse_ReadReceipt) isConnectResponse_Payload() {}
 
 func (*ConnectResponse_System) isConnectResponse_Payload() {}
 
-type SendMessageRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	ChatId         string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	IdempotencyKey string                 `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
-	Content        *MessageContent        `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (*ConnectResponse_Snapshot) isConnectResponse_Payload() {}
+
+func (*ConnectResponse_ReactionUpdated) isConnectResponse_Payload() {}
+
+func (*ConnectResponse_ThreadUpdated) isConnectResponse_Payload() {}
+
+// Snapshot replaces the client's state when the events it missed are not
+// replayed. Its id is the event it was taken at; the events after it follow.
+type Snapshot struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chats         []*ChatSnapshot        `protobuf:"bytes,1,rep,name=chats,proto3" json:"chats,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendMessageRequest) Reset() {
-	*x = SendMessageRequest{}
+func (x *Snapshot) Reset() {
+	*x = Snapshot{}
 	mi := &file_chat_v1_chat_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendMessageRequest) String() string {
+func (x *Snapshot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendMessageRequest) ProtoMessage() {}
+func (*Snapshot) ProtoMessage() {}
 
-func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
+func (x *Snapshot) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -408,54 +460,47 @@ func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
-func (*SendMessageRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Snapshot.ProtoReflect.Descriptor instead.
+func (*Snapshot) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *SendMessageRequest) GetChatId() string {
-	if x != nil {
-		return x.ChatId
-	}
-	return ""
-}
-
-func (x *SendMessageRequest) GetIdempotencyKey() string {
-	if x != nil {
-		return x.IdempotencyKey
-	}
-	return ""
-}
-
-func (x *SendMessageRequest) GetContent() *MessageContent {
+func (x *Snapshot) GetChats() []*ChatSnapshot {
 	if x != nil {
-		return x.Content
+		return x.Chats
 	}
 	return nil
 }
 
-type SendMessageResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+type ChatSnapshot struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The chat with its members.
+	Chat *Chat `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	// Empty while the chat has no messages.
+	LastMessageId string `protobuf:"bytes,2,opt,name=last_message_id,json=lastMessageId,proto3" json:"last_message_id,omitempty"`
+	UnreadCount   int32  `protobuf:"varint,3,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	// Read cursors of the members that have read a message.
+	ReadStates []*MemberReadState `protobuf:"bytes,4,rep,name=read_states,json=readStates,proto3" json:"read_states,omitempty"`
+	// Time of the last activity in the chat.
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SendMessageResponse) Reset() {
-	*x = SendMessageResponse{}
+func (x *ChatSnapshot) Reset() {
+	*x = ChatSnapshot{}
 	mi := &file_chat_v1_chat_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SendMessageResponse) String() string {
+func (x *ChatSnapshot) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SendMessageResponse) ProtoMessage() {}
+func (*ChatSnapshot) ProtoMessage() {}
 
-func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
+func (x *ChatSnapshot) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -467,48 +512,72 @@ func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
-func (*SendMessageResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ChatSnapshot.ProtoReflect.Descriptor instead.
+func (*ChatSnapshot) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *SendMessageResponse) GetMessageId() string {
+func (x *ChatSnapshot) GetChat() *Chat {
 	if x != nil {
-		return x.MessageId
+		return x.Chat
+	}
+	return nil
+}
+
+func (x *ChatSnapshot) GetLastMessageId() string {
+	if x != nil {
+		return x.LastMessageId
 	}
 	return ""
 }
 
-func (x *SendMessageResponse) GetCreatedAt() *timestamppb.Timestamp {
+func (x *ChatSnapshot) GetUnreadCount() int32 {
 	if x != nil {
-		return x.CreatedAt
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *ChatSnapshot) GetReadStates() []*MemberReadState {
+	if x != nil {
+		return x.ReadStates
 	}
 	return nil
 }
 
-type GetHistoryRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	Cursor        string                 `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+func (x *ChatSnapshot) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type SendMessageRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ChatId         string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	IdempotencyKey string                 `protobuf:"bytes,2,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	Content        *MessageContent        `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	// Posts the message as a reply in the thread of this message of the same
+	// chat. Replies cannot start threads of their own.
+	ThreadRootId  *string `protobuf:"bytes,4,opt,name=thread_root_id,json=threadRootId,proto3,oneof" json:"thread_root_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetHistoryRequest) Reset() {
-	*x = GetHistoryRequest{}
+func (x *SendMessageRequest) Reset() {
+	*x = SendMessageRequest{}
 	mi := &file_chat_v1_chat_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetHistoryRequest) String() string {
+func (x *SendMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetHistoryRequest) ProtoMessage() {}
+func (*SendMessageRequest) ProtoMessage() {}
 
-func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+func (x *SendMessageRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -520,54 +589,61 @@ func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
-func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendMessageRequest.ProtoReflect.Descriptor instead.
+func (*SendMessageRequest) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *GetHistoryRequest) GetChatId() string {
+func (x *SendMessageRequest) GetChatId() string {
 	if x != nil {
 		return x.ChatId
 	}
 	return ""
 }
 
-func (x *GetHistoryRequest) GetPageSize() int32 {
+func (x *SendMessageRequest) GetIdempotencyKey() string {
 	if x != nil {
-		return x.PageSize
+		return x.IdempotencyKey
 	}
-	return 0
+	return ""
 }
 
-func (x *GetHistoryRequest) GetCursor() string {
+func (x *SendMessageRequest) GetContent() *MessageContent {
 	if x != nil {
-		return x.Cursor
+		return x.Content
+	}
+	return nil
+}
+
+func (x *SendMessageRequest) GetThreadRootId() string {
+	if x != nil && x.ThreadRootId != nil {
+		return *x.ThreadRootId
 	}
 	return ""
 }
 
-type GetHistoryResponse struct {
+type SendMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Messages      []*Message             `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
-	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetHistoryResponse) Reset() {
-	*x = GetHistoryResponse{}
+func (x *SendMessageResponse) Reset() {
+	*x = SendMessageResponse{}
 	mi := &file_chat_v1_chat_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetHistoryResponse) String() string {
+func (x *SendMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetHistoryResponse) ProtoMessage() {}
+func (*SendMessageResponse) ProtoMessage() {}
 
-func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+func (x *SendMessageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -579,48 +655,49 @@ func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
-func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SendMessageResponse.ProtoReflect.Descriptor instead.
+func (*SendMessageResponse) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetHistoryResponse) GetMessages() []*Message {
+func (x *SendMessageResponse) GetMessageId() string {
 	if x != nil {
-		return x.Messages
+		return x.MessageId
 	}
-	return nil
+	return ""
 }
 
-func (x *GetHistoryResponse) GetNextCursor() string {
+func (x *SendMessageResponse) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.NextCursor
+		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-type CreateChatRequest struct {
+// Only the sender edits a message, and only within the edit window after
+// sending it.
+type EditMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
-	Type          ChatType               `protobuf:"varint,2,opt,name=type,proto3,enum=chat.v1.ChatType" json:"type,omitempty"`
-	MemberIds     []string               `protobuf:"bytes,3,rep,name=member_ids,json=memberIds,proto3" json:"member_ids,omitempty"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Content       *MessageContent        `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatRequest) Reset() {
-	*x = CreateChatRequest{}
+func (x *EditMessageRequest) Reset() {
+	*x = EditMessageRequest{}
 	mi := &file_chat_v1_chat_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatRequest) String() string {
+func (x *EditMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatRequest) ProtoMessage() {}
+func (*EditMessageRequest) ProtoMessage() {}
 
-func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
+func (x *EditMessageRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -632,53 +709,46 @@ func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatRequest.ProtoReflect.Descriptor instead.
-func (*CreateChatRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use EditMessageRequest.ProtoReflect.Descriptor instead.
+func (*EditMessageRequest) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *CreateChatRequest) GetName() string {
+func (x *EditMessageRequest) GetMessageId() string {
 	if x != nil {
-		return x.Name
+		return x.MessageId
 	}
 	return ""
 }
 
-func (x *CreateChatRequest) GetType() ChatType {
-	if x != nil {
-		return x.Type
-	}
-	return ChatType_CHAT_TYPE_UNSPECIFIED
-}
-
-func (x *CreateChatRequest) GetMemberIds() []string {
+func (x *EditMessageRequest) GetContent() *MessageContent {
 	if x != nil {
-		return x.MemberIds
+		return x.Content
 	}
 	return nil
 }
 
-type CreateChatResponse struct {
+type EditMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Chat          *Chat                  `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	Message       *Message               `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateChatResponse) Reset() {
-	*x = CreateChatResponse{}
+func (x *EditMessageResponse) Reset() {
+	*x = EditMessageResponse{}
 	mi := &file_chat_v1_chat_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateChatResponse) String() string {
+func (x *EditMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateChatResponse) ProtoMessage() {}
+func (*EditMessageResponse) ProtoMessage() {}
 
-func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
+func (x *EditMessageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -690,39 +760,41 @@ func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateChatResponse.ProtoReflect.Descriptor instead.
-func (*CreateChatResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use EditMessageResponse.ProtoReflect.Descriptor instead.
+func (*EditMessageResponse) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *CreateChatResponse) GetChat() *Chat {
+func (x *EditMessageResponse) GetMessage() *Message {
 	if x != nil {
-		return x.Chat
+		return x.Message
 	}
 	return nil
 }
 
-type GetChatRequest struct {
+// The sender or a chat admin deletes a message. The message stays in the
+// history as a tombstone without content.
+type DeleteMessageRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetChatRequest) Reset() {
-	*x = GetChatRequest{}
+func (x *DeleteMessageRequest) Reset() {
+	*x = DeleteMessageRequest{}
 	mi := &file_chat_v1_chat_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetChatRequest) String() string {
+func (x *DeleteMessageRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetChatRequest) ProtoMessage() {}
+func (*DeleteMessageRequest) ProtoMessage() {}
 
-func (x *GetChatRequest) ProtoReflect() protoreflect.Message {
+func (x *DeleteMessageRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -734,39 +806,38 @@ func (x *GetChatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetChatRequest.ProtoReflect.Descriptor instead.
-func (*GetChatRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteMessageRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMessageRequest) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *GetChatRequest) GetChatId() string {
+func (x *DeleteMessageRequest) GetMessageId() string {
 	if x != nil {
-		return x.ChatId
+		return x.MessageId
 	}
 	return ""
 }
 
-type GetChatResponse struct {
+type DeleteMessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Chat          *Chat                  `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetChatResponse) Reset() {
-	*x = GetChatResponse{}
+func (x *DeleteMessageResponse) Reset() {
+	*x = DeleteMessageResponse{}
 	mi := &file_chat_v1_chat_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetChatResponse) String() string {
+func (x *DeleteMessageResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetChatResponse) ProtoMessage() {}
+func (*DeleteMessageResponse) ProtoMessage() {}
 
-func (x *GetChatResponse) ProtoReflect() protoreflect.Message {
+func (x *DeleteMessageResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -778,40 +849,36 @@ func (x *GetChatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetChatResponse.ProtoReflect.Descriptor instead.
-func (*GetChatResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use DeleteMessageResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMessageResponse) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *GetChatResponse) GetChat() *Chat {
-	if x != nil {
-		return x.Chat
-	}
-	return nil
-}
-
-type ListChatsRequest struct {
+// Typing events reach only the members that are online and are not kept in
+// the event log. Clients repeat is_typing = true while the user types; the
+// server reports is_typing = false after a few seconds without a call.
+type SetTypingRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PageSize      int32                  `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	Cursor        string                 `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	IsTyping      bool                   `protobuf:"varint,2,opt,name=is_typing,json=isTyping,proto3" json:"is_typing,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListChatsRequest) Reset() {
-	*x = ListChatsRequest{}
+func (x *SetTypingRequest) Reset() {
+	*x = SetTypingRequest{}
 	mi := &file_chat_v1_chat_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListChatsRequest) String() string {
+func (x *SetTypingRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListChatsRequest) ProtoMessage() {}
+func (*SetTypingRequest) ProtoMessage() {}
 
-func (x *ListChatsRequest) ProtoReflect() protoreflect.Message {
+func (x *SetTypingRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -823,47 +890,45 @@ func (x *ListChatsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListChatsRequest.ProtoReflect.Descriptor instead.
-func (*ListChatsRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetTypingRequest.ProtoReflect.Descriptor instead.
+func (*SetTypingRequest) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{10}
 }
 
-func (x *ListChatsRequest) GetPageSize() int32 {
+func (x *SetTypingRequest) GetChatId() string {
 	if x != nil {
-		return x.PageSize
+		return x.ChatId
 	}
-	return 0
+	return ""
 }
 
-func (x *ListChatsRequest) GetCursor() string {
+func (x *SetTypingRequest) GetIsTyping() bool {
 	if x != nil {
-		return x.Cursor
+		return x.IsTyping
 	}
-	return ""
+	return false
 }
 
-type ListChatsResponse struct {
+type SetTypingResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Chats         []*ChatPreview         `protobuf:"bytes,1,rep,name=chats,proto3" json:"chats,omitempty"`
-	NextCursor    string                 `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListChatsResponse) Reset() {
-	*x = ListChatsResponse{}
+func (x *SetTypingResponse) Reset() {
+	*x = SetTypingResponse{}
 	mi := &file_chat_v1_chat_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListChatsResponse) String() string {
+func (x *SetTypingResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListChatsResponse) ProtoMessage() {}
+func (*SetTypingResponse) ProtoMessage() {}
 
-func (x *ListChatsResponse) ProtoReflect() protoreflect.Message {
+func (x *SetTypingResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_chat_v1_chat_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -875,52 +940,1816 @@ func (x *ListChatsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListChatsResponse.ProtoReflect.Descriptor instead.
-func (*ListChatsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use SetTypingResponse.ProtoReflect.Descriptor instead.
+func (*SetTypingResponse) Descriptor() ([]byte, []int) {
 	return file_chat_v1_chat_proto_rawDescGZIP(), []int{11}
 }
 
-func (x *ListChatsResponse) GetChats() []*ChatPreview {
+// The read cursor of a member only moves forward; marking an older message
+// leaves it where it is.
+type MarkReadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkReadRequest) Reset() {
+	*x = MarkReadRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkReadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkReadRequest) ProtoMessage() {}
+
+func (x *MarkReadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[12]
 	if x != nil {
-		return x.Chats
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkReadRequest.ProtoReflect.Descriptor instead.
+func (*MarkReadRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *MarkReadRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *MarkReadRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type MarkReadResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The caller's read cursor after the call.
+	State         *MemberReadState `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MarkReadResponse) Reset() {
+	*x = MarkReadResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MarkReadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MarkReadResponse) ProtoMessage() {}
+
+func (x *MarkReadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MarkReadResponse.ProtoReflect.Descriptor instead.
+func (*MarkReadResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *MarkReadResponse) GetState() *MemberReadState {
+	if x != nil {
+		return x.State
 	}
 	return nil
 }
 
-func (x *ListChatsResponse) GetNextCursor() string {
+type GetReadStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReadStateRequest) Reset() {
+	*x = GetReadStateRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReadStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReadStateRequest) ProtoMessage() {}
+
+func (x *GetReadStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[14]
 	if x != nil {
-		return x.NextCursor
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReadStateRequest.ProtoReflect.Descriptor instead.
+func (*GetReadStateRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *GetReadStateRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type GetReadStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Members       []*MemberReadState     `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetReadStateResponse) Reset() {
+	*x = GetReadStateResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetReadStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetReadStateResponse) ProtoMessage() {}
+
+func (x *GetReadStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetReadStateResponse.ProtoReflect.Descriptor instead.
+func (*GetReadStateResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetReadStateResponse) GetMembers() []*MemberReadState {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type MemberReadState struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Empty until the member reads a message.
+	LastReadMessageId string                 `protobuf:"bytes,2,opt,name=last_read_message_id,json=lastReadMessageId,proto3" json:"last_read_message_id,omitempty"`
+	ReadAt            *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=read_at,json=readAt,proto3" json:"read_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *MemberReadState) Reset() {
+	*x = MemberReadState{}
+	mi := &file_chat_v1_chat_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemberReadState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemberReadState) ProtoMessage() {}
+
+func (x *MemberReadState) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemberReadState.ProtoReflect.Descriptor instead.
+func (*MemberReadState) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *MemberReadState) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *MemberReadState) GetLastReadMessageId() string {
+	if x != nil {
+		return x.LastReadMessageId
+	}
+	return ""
+}
+
+func (x *MemberReadState) GetReadAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReadAt
+	}
+	return nil
+}
+
+// A user reacts to a message at most once with each emoji; adding a
+// reaction twice or removing a missing one changes nothing. A message
+// carries a limited number of distinct emoji.
+type AddReactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Emoji         string                 `protobuf:"bytes,2,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddReactionRequest) Reset() {
+	*x = AddReactionRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddReactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddReactionRequest) ProtoMessage() {}
+
+func (x *AddReactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddReactionRequest.ProtoReflect.Descriptor instead.
+func (*AddReactionRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AddReactionRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *AddReactionRequest) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+type AddReactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reaction      *ReactionSummary       `protobuf:"bytes,1,opt,name=reaction,proto3" json:"reaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddReactionResponse) Reset() {
+	*x = AddReactionResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddReactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddReactionResponse) ProtoMessage() {}
+
+func (x *AddReactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddReactionResponse.ProtoReflect.Descriptor instead.
+func (*AddReactionResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AddReactionResponse) GetReaction() *ReactionSummary {
+	if x != nil {
+		return x.Reaction
+	}
+	return nil
+}
+
+type RemoveReactionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	Emoji         string                 `protobuf:"bytes,2,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveReactionRequest) Reset() {
+	*x = RemoveReactionRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveReactionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveReactionRequest) ProtoMessage() {}
+
+func (x *RemoveReactionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveReactionRequest.ProtoReflect.Descriptor instead.
+func (*RemoveReactionRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *RemoveReactionRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *RemoveReactionRequest) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+type RemoveReactionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reaction      *ReactionSummary       `protobuf:"bytes,1,opt,name=reaction,proto3" json:"reaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveReactionResponse) Reset() {
+	*x = RemoveReactionResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveReactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveReactionResponse) ProtoMessage() {}
+
+func (x *RemoveReactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveReactionResponse.ProtoReflect.Descriptor instead.
+func (*RemoveReactionResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RemoveReactionResponse) GetReaction() *ReactionSummary {
+	if x != nil {
+		return x.Reaction
+	}
+	return nil
+}
+
+type ReactionSummary struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Emoji string                 `protobuf:"bytes,1,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	// The number of users that reacted with the emoji.
+	Count int32 `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	// Whether the caller is one of them.
+	ReactedByMe   bool `protobuf:"varint,3,opt,name=reacted_by_me,json=reactedByMe,proto3" json:"reacted_by_me,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReactionSummary) Reset() {
+	*x = ReactionSummary{}
+	mi := &file_chat_v1_chat_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionSummary) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionSummary) ProtoMessage() {}
+
+func (x *ReactionSummary) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionSummary.ProtoReflect.Descriptor instead.
+func (*ReactionSummary) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReactionSummary) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ReactionSummary) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReactionSummary) GetReactedByMe() bool {
+	if x != nil {
+		return x.ReactedByMe
+	}
+	return false
+}
+
+type GetHistoryRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ChatId string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	// Defaults to 20 when unset and is capped at 100.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque next_cursor of the previous page; empty for the newest messages.
+	Cursor        string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryRequest) Reset() {
+	*x = GetHistoryRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryRequest) ProtoMessage() {}
+
+func (x *GetHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *GetHistoryRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *GetHistoryRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetHistoryRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetHistoryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Newest message first. Thread replies are not part of the history; their
+	// roots carry reply_count and last_reply_at instead.
+	Messages []*Message `protobuf:"bytes,1,rep,name=messages,proto3" json:"messages,omitempty"`
+	// Empty when the page reaches the first message of the chat.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetHistoryResponse) Reset() {
+	*x = GetHistoryResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetHistoryResponse) ProtoMessage() {}
+
+func (x *GetHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *GetHistoryResponse) GetMessages() []*Message {
+	if x != nil {
+		return x.Messages
+	}
+	return nil
+}
+
+func (x *GetHistoryResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type GetThreadRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RootMessageId string                 `protobuf:"bytes,1,opt,name=root_message_id,json=rootMessageId,proto3" json:"root_message_id,omitempty"`
+	// Defaults to 20 when unset and is capped at 100.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque next_cursor of the previous page; empty for the newest replies.
+	Cursor        string `protobuf:"bytes,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetThreadRequest) Reset() {
+	*x = GetThreadRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetThreadRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetThreadRequest) ProtoMessage() {}
+
+func (x *GetThreadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetThreadRequest.ProtoReflect.Descriptor instead.
+func (*GetThreadRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *GetThreadRequest) GetRootMessageId() string {
+	if x != nil {
+		return x.RootMessageId
+	}
+	return ""
+}
+
+func (x *GetThreadRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *GetThreadRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type GetThreadResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Root  *Message               `protobuf:"bytes,1,opt,name=root,proto3" json:"root,omitempty"`
+	// Newest reply first.
+	Replies []*Message `protobuf:"bytes,2,rep,name=replies,proto3" json:"replies,omitempty"`
+	// Empty when the page reaches the first reply.
+	NextCursor    string `protobuf:"bytes,3,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetThreadResponse) Reset() {
+	*x = GetThreadResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetThreadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetThreadResponse) ProtoMessage() {}
+
+func (x *GetThreadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetThreadResponse.ProtoReflect.Descriptor instead.
+func (*GetThreadResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *GetThreadResponse) GetRoot() *Message {
+	if x != nil {
+		return x.Root
+	}
+	return nil
+}
+
+func (x *GetThreadResponse) GetReplies() []*Message {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+func (x *GetThreadResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type CreateChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Type          ChatType               `protobuf:"varint,2,opt,name=type,proto3,enum=chat.v1.ChatType" json:"type,omitempty"`
+	MemberIds     []string               `protobuf:"bytes,3,rep,name=member_ids,json=memberIds,proto3" json:"member_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatRequest) Reset() {
+	*x = CreateChatRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatRequest) ProtoMessage() {}
+
+func (x *CreateChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatRequest.ProtoReflect.Descriptor instead.
+func (*CreateChatRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *CreateChatRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateChatRequest) GetType() ChatType {
+	if x != nil {
+		return x.Type
+	}
+	return ChatType_CHAT_TYPE_UNSPECIFIED
+}
+
+func (x *CreateChatRequest) GetMemberIds() []string {
+	if x != nil {
+		return x.MemberIds
+	}
+	return nil
+}
+
+type CreateChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chat          *Chat                  `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateChatResponse) Reset() {
+	*x = CreateChatResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateChatResponse) ProtoMessage() {}
+
+func (x *CreateChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateChatResponse.ProtoReflect.Descriptor instead.
+func (*CreateChatResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateChatResponse) GetChat() *Chat {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+type GetChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatRequest) Reset() {
+	*x = GetChatRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatRequest) ProtoMessage() {}
+
+func (x *GetChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatRequest.ProtoReflect.Descriptor instead.
+func (*GetChatRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *GetChatRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type GetChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Chat          *Chat                  `protobuf:"bytes,1,opt,name=chat,proto3" json:"chat,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChatResponse) Reset() {
+	*x = GetChatResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChatResponse) ProtoMessage() {}
+
+func (x *GetChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChatResponse.ProtoReflect.Descriptor instead.
+func (*GetChatResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *GetChatResponse) GetChat() *Chat {
+	if x != nil {
+		return x.Chat
+	}
+	return nil
+}
+
+type ListChatsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Defaults to 20 when unset and is capped at 100.
+	PageSize int32 `protobuf:"varint,1,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Opaque next_cursor of the previous page; empty for the most recent chats.
+	Cursor        string `protobuf:"bytes,2,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChatsRequest) Reset() {
+	*x = ListChatsRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChatsRequest) ProtoMessage() {}
+
+func (x *ListChatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChatsRequest.ProtoReflect.Descriptor instead.
+func (*ListChatsRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListChatsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListChatsRequest) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+type ListChatsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Most recently active chat first, except for the chats listed again,
+	// which follow the chats listed before them.
+	Chats []*ChatPreview `protobuf:"bytes,1,rep,name=chats,proto3" json:"chats,omitempty"`
+	// Empty on the last page.
+	NextCursor    string `protobuf:"bytes,2,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListChatsResponse) Reset() {
+	*x = ListChatsResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListChatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListChatsResponse) ProtoMessage() {}
+
+func (x *ListChatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListChatsResponse.ProtoReflect.Descriptor instead.
+func (*ListChatsResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ListChatsResponse) GetChats() []*ChatPreview {
+	if x != nil {
+		return x.Chats
+	}
+	return nil
+}
+
+func (x *ListChatsResponse) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type ChatPreview struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Type  ChatType               `protobuf:"varint,3,opt,name=type,proto3,enum=chat.v1.ChatType" json:"type,omitempty"`
+	// Unset while the chat has no messages.
+	LastMessage *Message `protobuf:"bytes,4,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
+	// Messages from other members after the caller's read cursor.
+	UnreadCount int32 `protobuf:"varint,5,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
+	// Time of the last activity in the chat.
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatPreview) Reset() {
+	*x = ChatPreview{}
+	mi := &file_chat_v1_chat_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatPreview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatPreview) ProtoMessage() {}
+
+func (x *ChatPreview) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatPreview.ProtoReflect.Descriptor instead.
+func (*ChatPreview) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ChatPreview) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ChatPreview) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChatPreview) GetType() ChatType {
+	if x != nil {
+		return x.Type
+	}
+	return ChatType_CHAT_TYPE_UNSPECIFIED
+}
+
+func (x *ChatPreview) GetLastMessage() *Message {
+	if x != nil {
+		return x.LastMessage
+	}
+	return nil
+}
+
+func (x *ChatPreview) GetUnreadCount() int32 {
+	if x != nil {
+		return x.UnreadCount
+	}
+	return 0
+}
+
+func (x *ChatPreview) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type AddMembersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserIds       []string               `protobuf:"bytes,2,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddMembersRequest) Reset() {
+	*x = AddMembersRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddMembersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddMembersRequest) ProtoMessage() {}
+
+func (x *AddMembersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddMembersRequest.ProtoReflect.Descriptor instead.
+func (*AddMembersRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *AddMembersRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *AddMembersRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type AddMembersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The members that were added; users already in the chat are skipped.
+	Members       []*ChatMember `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddMembersResponse) Reset() {
+	*x = AddMembersResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddMembersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddMembersResponse) ProtoMessage() {}
+
+func (x *AddMembersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddMembersResponse.ProtoReflect.Descriptor instead.
+func (*AddMembersResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *AddMembersResponse) GetMembers() []*ChatMember {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type RemoveMemberRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberRequest) Reset() {
+	*x = RemoveMemberRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberRequest) ProtoMessage() {}
+
+func (x *RemoveMemberRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberRequest.ProtoReflect.Descriptor instead.
+func (*RemoveMemberRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *RemoveMemberRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *RemoveMemberRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type RemoveMemberResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveMemberResponse) Reset() {
+	*x = RemoveMemberResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveMemberResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveMemberResponse) ProtoMessage() {}
+
+func (x *RemoveMemberResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveMemberResponse.ProtoReflect.Descriptor instead.
+func (*RemoveMemberResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{36}
+}
+
+// The owner can leave only as the last member; otherwise ownership has to
+// be transferred first.
+type LeaveChatRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveChatRequest) Reset() {
+	*x = LeaveChatRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveChatRequest) ProtoMessage() {}
+
+func (x *LeaveChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveChatRequest.ProtoReflect.Descriptor instead.
+func (*LeaveChatRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *LeaveChatRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+type LeaveChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LeaveChatResponse) Reset() {
+	*x = LeaveChatResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LeaveChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LeaveChatResponse) ProtoMessage() {}
+
+func (x *LeaveChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LeaveChatResponse.ProtoReflect.Descriptor instead.
+func (*LeaveChatResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{38}
+}
+
+type UpdateMemberRoleRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	ChatId string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// MEMBER or ADMIN; use TransferOwnership to change the owner.
+	Role          MemberRole `protobuf:"varint,3,opt,name=role,proto3,enum=chat.v1.MemberRole" json:"role,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMemberRoleRequest) Reset() {
+	*x = UpdateMemberRoleRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMemberRoleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMemberRoleRequest) ProtoMessage() {}
+
+func (x *UpdateMemberRoleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMemberRoleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMemberRoleRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *UpdateMemberRoleRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *UpdateMemberRoleRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateMemberRoleRequest) GetRole() MemberRole {
+	if x != nil {
+		return x.Role
+	}
+	return MemberRole_MEMBER_ROLE_UNSPECIFIED
+}
+
+type UpdateMemberRoleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Member        *ChatMember            `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMemberRoleResponse) Reset() {
+	*x = UpdateMemberRoleResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMemberRoleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMemberRoleResponse) ProtoMessage() {}
+
+func (x *UpdateMemberRoleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMemberRoleResponse.ProtoReflect.Descriptor instead.
+func (*UpdateMemberRoleResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *UpdateMemberRoleResponse) GetMember() *ChatMember {
+	if x != nil {
+		return x.Member
+	}
+	return nil
+}
+
+// The previous owner stays in the chat as an admin.
+type TransferOwnershipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferOwnershipRequest) Reset() {
+	*x = TransferOwnershipRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferOwnershipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferOwnershipRequest) ProtoMessage() {}
+
+func (x *TransferOwnershipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferOwnershipRequest.ProtoReflect.Descriptor instead.
+func (*TransferOwnershipRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *TransferOwnershipRequest) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *TransferOwnershipRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type TransferOwnershipResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferOwnershipResponse) Reset() {
+	*x = TransferOwnershipResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferOwnershipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferOwnershipResponse) ProtoMessage() {}
+
+func (x *TransferOwnershipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferOwnershipResponse.ProtoReflect.Descriptor instead.
+func (*TransferOwnershipResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{42}
+}
+
+// Attachments are encrypted by the client before the upload; the server
+// stores the bytes without reading them. The first request of an upload
+// carries the info, the rest carry chunks that add up to exactly info.size
+// bytes. An attachment is sent by referencing it in a message content of
+// the same chat.
+type UploadAttachmentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*UploadAttachmentRequest_Info
+	//	*UploadAttachmentRequest_Chunk
+	Payload       isUploadAttachmentRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentRequest) Reset() {
+	*x = UploadAttachmentRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentRequest) ProtoMessage() {}
+
+func (x *UploadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *UploadAttachmentRequest) GetPayload() isUploadAttachmentRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *UploadAttachmentRequest) GetInfo() *AttachmentInfo {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadAttachmentRequest_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *UploadAttachmentRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadAttachmentRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadAttachmentRequest_Payload interface {
+	isUploadAttachmentRequest_Payload()
+}
+
+type UploadAttachmentRequest_Info struct {
+	Info *AttachmentInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type UploadAttachmentRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadAttachmentRequest_Info) isUploadAttachmentRequest_Payload() {}
+
+func (*UploadAttachmentRequest_Chunk) isUploadAttachmentRequest_Payload() {}
+
+type AttachmentInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Size          int64                  `protobuf:"varint,2,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AttachmentInfo) Reset() {
+	*x = AttachmentInfo{}
+	mi := &file_chat_v1_chat_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AttachmentInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AttachmentInfo) ProtoMessage() {}
+
+func (x *AttachmentInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AttachmentInfo.ProtoReflect.Descriptor instead.
+func (*AttachmentInfo) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AttachmentInfo) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *AttachmentInfo) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type UploadAttachmentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Attachment    *Attachment            `protobuf:"bytes,1,opt,name=attachment,proto3" json:"attachment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadAttachmentResponse) Reset() {
+	*x = UploadAttachmentResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadAttachmentResponse) ProtoMessage() {}
+
+func (x *UploadAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*UploadAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *UploadAttachmentResponse) GetAttachment() *Attachment {
+	if x != nil {
+		return x.Attachment
 	}
-	return ""
+	return nil
 }
 
-type ChatPreview struct {
+// DownloadAttachment streams length bytes of the attachment from offset;
+// length 0 reads to the end. Only members of the attachment's chat can
+// download it.
+type DownloadAttachmentRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Type          ChatType               `protobuf:"varint,3,opt,name=type,proto3,enum=chat.v1.ChatType" json:"type,omitempty"`
-	LastMessage   *Message               `protobuf:"bytes,4,opt,name=last_message,json=lastMessage,proto3" json:"last_message,omitempty"`
-	UnreadCount   int32                  `protobuf:"varint,5,opt,name=unread_count,json=unreadCount,proto3" json:"unread_count,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	AttachmentId  string                 `protobuf:"bytes,1,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	Offset        int64                  `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	Length        int64                  `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatPreview) Reset() {
-	*x = ChatPreview{}
-	mi := &file_chat_v1_chat_proto_msgTypes[12]
+func (x *DownloadAttachmentRequest) Reset() {
+	*x = DownloadAttachmentRequest{}
+	mi := &file_chat_v1_chat_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatPreview) String() string {
+func (x *DownloadAttachmentRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatPreview) ProtoMessage() {}
+func (*DownloadAttachmentRequest) ProtoMessage() {}
 
-func (x *ChatPreview) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[12]
+func (x *DownloadAttachmentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -931,51 +2760,92 @@ func (x *ChatPreview) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatPreview.ProtoReflect.Descriptor instead.
-func (*ChatPreview) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use DownloadAttachmentRequest.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentRequest) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{46}
 }
 
-func (x *ChatPreview) GetId() string {
+func (x *DownloadAttachmentRequest) GetAttachmentId() string {
 	if x != nil {
-		return x.Id
+		return x.AttachmentId
 	}
 	return ""
 }
 
-func (x *ChatPreview) GetName() string {
+func (x *DownloadAttachmentRequest) GetOffset() int64 {
 	if x != nil {
-		return x.Name
+		return x.Offset
 	}
-	return ""
+	return 0
 }
 
-func (x *ChatPreview) GetType() ChatType {
+func (x *DownloadAttachmentRequest) GetLength() int64 {
 	if x != nil {
-		return x.Type
+		return x.Length
 	}
-	return ChatType_CHAT_TYPE_UNSPECIFIED
+	return 0
 }
 
-func (x *ChatPreview) GetLastMessage() *Message {
+type DownloadAttachmentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Chunk []byte                 `protobuf:"bytes,1,opt,name=chunk,proto3" json:"chunk,omitempty"`
+	// Position of the chunk in the attachment.
+	Offset int64 `protobuf:"varint,2,opt,name=offset,proto3" json:"offset,omitempty"`
+	// Size of the whole attachment.
+	Size          int64 `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadAttachmentResponse) Reset() {
+	*x = DownloadAttachmentResponse{}
+	mi := &file_chat_v1_chat_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadAttachmentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadAttachmentResponse) ProtoMessage() {}
+
+func (x *DownloadAttachmentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[47]
 	if x != nil {
-		return x.LastMessage
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadAttachmentResponse.ProtoReflect.Descriptor instead.
+func (*DownloadAttachmentResponse) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *DownloadAttachmentResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
 	}
 	return nil
 }
 
-func (x *ChatPreview) GetUnreadCount() int32 {
+func (x *DownloadAttachmentResponse) GetOffset() int64 {
 	if x != nil {
-		return x.UnreadCount
+		return x.Offset
 	}
 	return 0
 }
 
-func (x *ChatPreview) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *DownloadAttachmentResponse) GetSize() int64 {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Size
 	}
-	return nil
+	return 0
 }
 
 type MessageNew struct {
@@ -987,7 +2857,7 @@ type MessageNew struct {
 
 func (x *MessageNew) Reset() {
 	*x = MessageNew{}
-	mi := &file_chat_v1_chat_proto_msgTypes[13]
+	mi := &file_chat_v1_chat_proto_msgTypes[48]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -999,7 +2869,7 @@ func (x *MessageNew) String() string {
 func (*MessageNew) ProtoMessage() {}
 
 func (x *MessageNew) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[13]
+	mi := &file_chat_v1_chat_proto_msgTypes[48]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1012,7 +2882,7 @@ func (x *MessageNew) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageNew.ProtoReflect.Descriptor instead.
 func (*MessageNew) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{13}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{48}
 }
 
 func (x *MessageNew) GetMessage() *Message {
@@ -1034,7 +2904,7 @@ type MessageUpdated struct {
 
 func (x *MessageUpdated) Reset() {
 	*x = MessageUpdated{}
-	mi := &file_chat_v1_chat_proto_msgTypes[14]
+	mi := &file_chat_v1_chat_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1046,7 +2916,7 @@ func (x *MessageUpdated) String() string {
 func (*MessageUpdated) ProtoMessage() {}
 
 func (x *MessageUpdated) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[14]
+	mi := &file_chat_v1_chat_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1059,7 +2929,7 @@ func (x *MessageUpdated) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageUpdated.ProtoReflect.Descriptor instead.
 func (*MessageUpdated) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{14}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{49}
 }
 
 func (x *MessageUpdated) GetMessageId() string {
@@ -1094,13 +2964,14 @@ type MessageDeleted struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
 	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MessageDeleted) Reset() {
 	*x = MessageDeleted{}
-	mi := &file_chat_v1_chat_proto_msgTypes[15]
+	mi := &file_chat_v1_chat_proto_msgTypes[50]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1112,7 +2983,7 @@ func (x *MessageDeleted) String() string {
 func (*MessageDeleted) ProtoMessage() {}
 
 func (x *MessageDeleted) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[15]
+	mi := &file_chat_v1_chat_proto_msgTypes[50]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1125,7 +2996,7 @@ func (x *MessageDeleted) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageDeleted.ProtoReflect.Descriptor instead.
 func (*MessageDeleted) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{15}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{50}
 }
 
 func (x *MessageDeleted) GetMessageId() string {
@@ -1142,6 +3013,13 @@ func (x *MessageDeleted) GetChatId() string {
 	return ""
 }
 
+func (x *MessageDeleted) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
 type TypingIndicator struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
@@ -1153,7 +3031,7 @@ type TypingIndicator struct {
 
 func (x *TypingIndicator) Reset() {
 	*x = TypingIndicator{}
-	mi := &file_chat_v1_chat_proto_msgTypes[16]
+	mi := &file_chat_v1_chat_proto_msgTypes[51]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1165,7 +3043,7 @@ func (x *TypingIndicator) String() string {
 func (*TypingIndicator) ProtoMessage() {}
 
 func (x *TypingIndicator) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[16]
+	mi := &file_chat_v1_chat_proto_msgTypes[51]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1178,7 +3056,7 @@ func (x *TypingIndicator) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TypingIndicator.ProtoReflect.Descriptor instead.
 func (*TypingIndicator) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{16}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{51}
 }
 
 func (x *TypingIndicator) GetChatId() string {
@@ -1207,13 +3085,14 @@ type ReadReceipt struct {
 	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
 	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	MessageId     string                 `protobuf:"bytes,3,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ReadAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=read_at,json=readAt,proto3" json:"read_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ReadReceipt) Reset() {
 	*x = ReadReceipt{}
-	mi := &file_chat_v1_chat_proto_msgTypes[17]
+	mi := &file_chat_v1_chat_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1225,7 +3104,7 @@ func (x *ReadReceipt) String() string {
 func (*ReadReceipt) ProtoMessage() {}
 
 func (x *ReadReceipt) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[17]
+	mi := &file_chat_v1_chat_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1238,7 +3117,7 @@ func (x *ReadReceipt) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ReadReceipt.ProtoReflect.Descriptor instead.
 func (*ReadReceipt) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{17}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{52}
 }
 
 func (x *ReadReceipt) GetChatId() string {
@@ -1248,43 +3127,287 @@ func (x *ReadReceipt) GetChatId() string {
 	return ""
 }
 
-func (x *ReadReceipt) GetUserId() string {
+func (x *ReadReceipt) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReadReceipt) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ReadReceipt) GetReadAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReadAt
+	}
+	return nil
+}
+
+// ReactionUpdated reports that user_id added or removed a reaction; count
+// is the number of users with that emoji on the message afterwards.
+type ReactionUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Emoji         string                 `protobuf:"bytes,4,opt,name=emoji,proto3" json:"emoji,omitempty"`
+	Added         bool                   `protobuf:"varint,5,opt,name=added,proto3" json:"added,omitempty"`
+	Count         int32                  `protobuf:"varint,6,opt,name=count,proto3" json:"count,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReactionUpdated) Reset() {
+	*x = ReactionUpdated{}
+	mi := &file_chat_v1_chat_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReactionUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReactionUpdated) ProtoMessage() {}
+
+func (x *ReactionUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReactionUpdated.ProtoReflect.Descriptor instead.
+func (*ReactionUpdated) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *ReactionUpdated) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *ReactionUpdated) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ReactionUpdated) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ReactionUpdated) GetEmoji() string {
+	if x != nil {
+		return x.Emoji
+	}
+	return ""
+}
+
+func (x *ReactionUpdated) GetAdded() bool {
+	if x != nil {
+		return x.Added
+	}
+	return false
+}
+
+func (x *ReactionUpdated) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ReactionUpdated) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+// ThreadUpdated reports a new reply in a thread, so that clients can show
+// the reply count without loading the thread.
+type ThreadUpdated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ChatId        string                 `protobuf:"bytes,1,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	RootMessageId string                 `protobuf:"bytes,2,opt,name=root_message_id,json=rootMessageId,proto3" json:"root_message_id,omitempty"`
+	ReplyCount    int32                  `protobuf:"varint,3,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	LastReplyId   string                 `protobuf:"bytes,4,opt,name=last_reply_id,json=lastReplyId,proto3" json:"last_reply_id,omitempty"`
+	LastReplyAt   *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=last_reply_at,json=lastReplyAt,proto3" json:"last_reply_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ThreadUpdated) Reset() {
+	*x = ThreadUpdated{}
+	mi := &file_chat_v1_chat_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ThreadUpdated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ThreadUpdated) ProtoMessage() {}
+
+func (x *ThreadUpdated) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ThreadUpdated.ProtoReflect.Descriptor instead.
+func (*ThreadUpdated) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *ThreadUpdated) GetChatId() string {
+	if x != nil {
+		return x.ChatId
+	}
+	return ""
+}
+
+func (x *ThreadUpdated) GetRootMessageId() string {
+	if x != nil {
+		return x.RootMessageId
+	}
+	return ""
+}
+
+func (x *ThreadUpdated) GetReplyCount() int32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+func (x *ThreadUpdated) GetLastReplyId() string {
+	if x != nil {
+		return x.LastReplyId
+	}
+	return ""
+}
+
+func (x *ThreadUpdated) GetLastReplyAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastReplyAt
+	}
+	return nil
+}
+
+type SystemNotification struct {
+	state protoimpl.MessageState  `protogen:"open.v1"`
+	Text  string                  `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Level SystemNotificationLevel `protobuf:"varint,2,opt,name=level,proto3,enum=chat.v1.SystemNotificationLevel" json:"level,omitempty"`
+	// resync_required tells the client that the stream skipped the events it
+	// missed; it reloads its state with ListChats and GetHistory.
+	ResyncRequired bool `protobuf:"varint,3,opt,name=resync_required,json=resyncRequired,proto3" json:"resync_required,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SystemNotification) Reset() {
+	*x = SystemNotification{}
+	mi := &file_chat_v1_chat_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemNotification) ProtoMessage() {}
+
+func (x *SystemNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemNotification.ProtoReflect.Descriptor instead.
+func (*SystemNotification) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *SystemNotification) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *SystemNotification) GetLevel() SystemNotificationLevel {
 	if x != nil {
-		return x.UserId
+		return x.Level
 	}
-	return ""
+	return SystemNotificationLevel_SYSTEM_NOTIFICATION_LEVEL_UNSPECIFIED
 }
 
-func (x *ReadReceipt) GetMessageId() string {
+func (x *SystemNotification) GetResyncRequired() bool {
 	if x != nil {
-		return x.MessageId
+		return x.ResyncRequired
 	}
-	return ""
+	return false
 }
 
-type SystemNotification struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
-	Text          string                  `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
-	Level         SystemNotificationLevel `protobuf:"varint,2,opt,name=level,proto3,enum=chat.v1.SystemNotificationLevel" json:"level,omitempty"`
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	UploaderId    string                 `protobuf:"bytes,3,opt,name=uploader_id,json=uploaderId,proto3" json:"uploader_id,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SystemNotification) Reset() {
-	*x = SystemNotification{}
-	mi := &file_chat_v1_chat_proto_msgTypes[18]
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_chat_v1_chat_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SystemNotification) String() string {
+func (x *Attachment) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SystemNotification) ProtoMessage() {}
+func (*Attachment) ProtoMessage() {}
 
-func (x *SystemNotification) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[18]
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1295,23 +3418,44 @@ func (x *SystemNotification) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SystemNotification.ProtoReflect.Descriptor instead.
-func (*SystemNotification) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{18}
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *SystemNotification) GetText() string {
+func (x *Attachment) GetId() string {
 	if x != nil {
-		return x.Text
+		return x.Id
 	}
 	return ""
 }
 
-func (x *SystemNotification) GetLevel() SystemNotificationLevel {
+func (x *Attachment) GetChatId() string {
 	if x != nil {
-		return x.Level
+		return x.ChatId
 	}
-	return SystemNotificationLevel_SYSTEM_NOTIFICATION_LEVEL_UNSPECIFIED
+	return ""
+}
+
+func (x *Attachment) GetUploaderId() string {
+	if x != nil {
+		return x.UploaderId
+	}
+	return ""
+}
+
+func (x *Attachment) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Attachment) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
 }
 
 type Chat struct {
@@ -1328,7 +3472,7 @@ type Chat struct {
 
 func (x *Chat) Reset() {
 	*x = Chat{}
-	mi := &file_chat_v1_chat_proto_msgTypes[19]
+	mi := &file_chat_v1_chat_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1340,7 +3484,7 @@ func (x *Chat) String() string {
 func (*Chat) ProtoMessage() {}
 
 func (x *Chat) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[19]
+	mi := &file_chat_v1_chat_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1353,7 +3497,7 @@ func (x *Chat) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Chat.ProtoReflect.Descriptor instead.
 func (*Chat) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{19}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{57}
 }
 
 func (x *Chat) GetId() string {
@@ -1399,20 +3543,31 @@ func (x *Chat) GetUpdatedAt() *timestamppb.Timestamp {
 }
 
 type Message struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	ChatId        string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
-	Sender        *User                  `protobuf:"bytes,3,opt,name=sender,proto3" json:"sender,omitempty"`
-	Content       *MessageContent        `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ChatId    string                 `protobuf:"bytes,2,opt,name=chat_id,json=chatId,proto3" json:"chat_id,omitempty"`
+	Sender    *User                  `protobuf:"bytes,3,opt,name=sender,proto3" json:"sender,omitempty"`
+	Content   *MessageContent        `protobuf:"bytes,4,opt,name=content,proto3" json:"content,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Set once the message is deleted; the content is empty then.
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Reactions by emoji, in the order they were first added. Only GetHistory
+	// fills them in.
+	Reactions []*ReactionSummary `protobuf:"bytes,8,rep,name=reactions,proto3" json:"reactions,omitempty"`
+	// Set on a thread reply.
+	ThreadRootId *string `protobuf:"bytes,9,opt,name=thread_root_id,json=threadRootId,proto3,oneof" json:"thread_root_id,omitempty"`
+	// Replies in the thread of the message, deleted ones included.
+	ReplyCount int32 `protobuf:"varint,10,opt,name=reply_count,json=replyCount,proto3" json:"reply_count,omitempty"`
+	// Unset until the message gets a reply.
+	LastReplyAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=last_reply_at,json=lastReplyAt,proto3" json:"last_reply_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Message) Reset() {
 	*x = Message{}
-	mi := &file_chat_v1_chat_proto_msgTypes[20]
+	mi := &file_chat_v1_chat_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1424,7 +3579,7 @@ func (x *Message) String() string {
 func (*Message) ProtoMessage() {}
 
 func (x *Message) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[20]
+	mi := &file_chat_v1_chat_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1437,7 +3592,7 @@ func (x *Message) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Message.ProtoReflect.Descriptor instead.
 func (*Message) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{20}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{58}
 }
 
 func (x *Message) GetId() string {
@@ -1482,11 +3637,49 @@ func (x *Message) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Message) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Message) GetReactions() []*ReactionSummary {
+	if x != nil {
+		return x.Reactions
+	}
+	return nil
+}
+
+func (x *Message) GetThreadRootId() string {
+	if x != nil && x.ThreadRootId != nil {
+		return *x.ThreadRootId
+	}
+	return ""
+}
+
+func (x *Message) GetReplyCount() int32 {
+	if x != nil {
+		return x.ReplyCount
+	}
+	return 0
+}
+
+func (x *Message) GetLastReplyAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastReplyAt
+	}
+	return nil
+}
+
 type MessageContent struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// Types that are valid to be assigned to Type:
 	//
 	//	*MessageContent_Text
+	//	*MessageContent_Image
+	//	*MessageContent_File
+	//	*MessageContent_Voice
 	Type isMessageContent_Type `protobuf_oneof:"type"`
 	// Reply ортогонален типу контента — может быть у любого типа
 	ReplyToMessageId *string `protobuf:"bytes,5,opt,name=reply_to_message_id,json=replyToMessageId,proto3,oneof" json:"reply_to_message_id,omitempty"`
@@ -1496,7 +3689,7 @@ type MessageContent struct {
 
 func (x *MessageContent) Reset() {
 	*x = MessageContent{}
-	mi := &file_chat_v1_chat_proto_msgTypes[21]
+	mi := &file_chat_v1_chat_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1508,7 +3701,7 @@ func (x *MessageContent) String() string {
 func (*MessageContent) ProtoMessage() {}
 
 func (x *MessageContent) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[21]
+	mi := &file_chat_v1_chat_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1521,7 +3714,7 @@ func (x *MessageContent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageContent.ProtoReflect.Descriptor instead.
 func (*MessageContent) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{21}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{59}
 }
 
 func (x *MessageContent) GetType() isMessageContent_Type {
@@ -1540,6 +3733,33 @@ func (x *MessageContent) GetText() *TextContent {
 	return nil
 }
 
+func (x *MessageContent) GetImage() *ImageContent {
+	if x != nil {
+		if x, ok := x.Type.(*MessageContent_Image); ok {
+			return x.Image
+		}
+	}
+	return nil
+}
+
+func (x *MessageContent) GetFile() *FileContent {
+	if x != nil {
+		if x, ok := x.Type.(*MessageContent_File); ok {
+			return x.File
+		}
+	}
+	return nil
+}
+
+func (x *MessageContent) GetVoice() *VoiceContent {
+	if x != nil {
+		if x, ok := x.Type.(*MessageContent_Voice); ok {
+			return x.Voice
+		}
+	}
+	return nil
+}
+
 func (x *MessageContent) GetReplyToMessageId() string {
 	if x != nil && x.ReplyToMessageId != nil {
 		return *x.ReplyToMessageId
@@ -1555,8 +3775,26 @@ type MessageContent_Text struct {
 	Text *TextContent `protobuf:"bytes,1,opt,name=text,proto3,oneof"`
 }
 
+type MessageContent_Image struct {
+	Image *ImageContent `protobuf:"bytes,2,opt,name=image,proto3,oneof"`
+}
+
+type MessageContent_File struct {
+	File *FileContent `protobuf:"bytes,3,opt,name=file,proto3,oneof"`
+}
+
+type MessageContent_Voice struct {
+	Voice *VoiceContent `protobuf:"bytes,4,opt,name=voice,proto3,oneof"`
+}
+
 func (*MessageContent_Text) isMessageContent_Type() {}
 
+func (*MessageContent_Image) isMessageContent_Type() {}
+
+func (*MessageContent_File) isMessageContent_Type() {}
+
+func (*MessageContent_Voice) isMessageContent_Type() {}
+
 type TextContent struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ciphertext    []byte                 `protobuf:"bytes,1,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
@@ -1566,7 +3804,7 @@ type TextContent struct {
 
 func (x *TextContent) Reset() {
 	*x = TextContent{}
-	mi := &file_chat_v1_chat_proto_msgTypes[22]
+	mi := &file_chat_v1_chat_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1578,7 +3816,7 @@ func (x *TextContent) String() string {
 func (*TextContent) ProtoMessage() {}
 
 func (x *TextContent) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[22]
+	mi := &file_chat_v1_chat_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1591,7 +3829,7 @@ func (x *TextContent) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TextContent.ProtoReflect.Descriptor instead.
 func (*TextContent) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{22}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{60}
 }
 
 func (x *TextContent) GetCiphertext() []byte {
@@ -1601,6 +3839,165 @@ func (x *TextContent) GetCiphertext() []byte {
 	return nil
 }
 
+// The attachment contents reference an attachment uploaded by the sender to
+// the same chat. Their ciphertext carries what the members need to show
+// and decrypt it, such as its key, name and type.
+type ImageContent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AttachmentId  string                 `protobuf:"bytes,1,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	Ciphertext    []byte                 `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImageContent) Reset() {
+	*x = ImageContent{}
+	mi := &file_chat_v1_chat_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImageContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImageContent) ProtoMessage() {}
+
+func (x *ImageContent) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImageContent.ProtoReflect.Descriptor instead.
+func (*ImageContent) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *ImageContent) GetAttachmentId() string {
+	if x != nil {
+		return x.AttachmentId
+	}
+	return ""
+}
+
+func (x *ImageContent) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+type FileContent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AttachmentId  string                 `protobuf:"bytes,1,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	Ciphertext    []byte                 `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileContent) Reset() {
+	*x = FileContent{}
+	mi := &file_chat_v1_chat_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileContent) ProtoMessage() {}
+
+func (x *FileContent) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileContent.ProtoReflect.Descriptor instead.
+func (*FileContent) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *FileContent) GetAttachmentId() string {
+	if x != nil {
+		return x.AttachmentId
+	}
+	return ""
+}
+
+func (x *FileContent) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+type VoiceContent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AttachmentId  string                 `protobuf:"bytes,1,opt,name=attachment_id,json=attachmentId,proto3" json:"attachment_id,omitempty"`
+	Ciphertext    []byte                 `protobuf:"bytes,2,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VoiceContent) Reset() {
+	*x = VoiceContent{}
+	mi := &file_chat_v1_chat_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VoiceContent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VoiceContent) ProtoMessage() {}
+
+func (x *VoiceContent) ProtoReflect() protoreflect.Message {
+	mi := &file_chat_v1_chat_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VoiceContent.ProtoReflect.Descriptor instead.
+func (*VoiceContent) Descriptor() ([]byte, []int) {
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *VoiceContent) GetAttachmentId() string {
+	if x != nil {
+		return x.AttachmentId
+	}
+	return ""
+}
+
+func (x *VoiceContent) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
 type ChatMember struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -1613,7 +4010,7 @@ type ChatMember struct {
 
 func (x *ChatMember) Reset() {
 	*x = ChatMember{}
-	mi := &file_chat_v1_chat_proto_msgTypes[23]
+	mi := &file_chat_v1_chat_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1625,7 +4022,7 @@ func (x *ChatMember) String() string {
 func (*ChatMember) ProtoMessage() {}
 
 func (x *ChatMember) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[23]
+	mi := &file_chat_v1_chat_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1638,7 +4035,7 @@ func (x *ChatMember) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ChatMember.ProtoReflect.Descriptor instead.
 func (*ChatMember) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{23}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{64}
 }
 
 func (x *ChatMember) GetUserId() string {
@@ -1679,7 +4076,7 @@ type User struct {
 
 func (x *User) Reset() {
 	*x = User{}
-	mi := &file_chat_v1_chat_proto_msgTypes[24]
+	mi := &file_chat_v1_chat_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1691,7 +4088,7 @@ func (x *User) String() string {
 func (*User) ProtoMessage() {}
 
 func (x *User) ProtoReflect() protoreflect.Message {
-	mi := &file_chat_v1_chat_proto_msgTypes[24]
+	mi := &file_chat_v1_chat_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1704,7 +4101,7 @@ func (x *User) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use User.ProtoReflect.Descriptor instead.
 func (*User) Descriptor() ([]byte, []int) {
-	return file_chat_v1_chat_proto_rawDescGZIP(), []int{24}
+	return file_chat_v1_chat_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *User) GetId() string {
@@ -1728,7 +4125,7 @@ const file_chat_v1_chat_proto_rawDesc = "" +
 	"\x12chat/v1/chat.proto\x12\achat.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"K\n" +
 	"\x0eConnectRequest\x12'\n" +
 	"\rlast_event_id\x18\x01 \x01(\tH\x00R\vlastEventId\x88\x01\x01B\x10\n" +
-	"\x0e_last_event_id\"\x92\x03\n" +
+	"\x0e_last_event_id\"\xcb\x04\n" +
 	"\x0fConnectResponse\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x126\n" +
 	"\vmessage_new\x18\x03 \x01(\v2\x13.chat.v1.MessageNewH\x00R\n" +
@@ -1737,17 +4134,77 @@ const file_chat_v1_chat_proto_rawDesc = "" +
 	"\x0fmessage_deleted\x18\x05 \x01(\v2\x17.chat.v1.MessageDeletedH\x00R\x0emessageDeleted\x122\n" +
 	"\x06typing\x18\x06 \x01(\v2\x18.chat.v1.TypingIndicatorH\x00R\x06typing\x129\n" +
 	"\fread_receipt\x18\a \x01(\v2\x14.chat.v1.ReadReceiptH\x00R\vreadReceipt\x125\n" +
-	"\x06system\x18\b \x01(\v2\x1b.chat.v1.SystemNotificationH\x00R\x06systemB\t\n" +
-	"\apayload\"\x89\x01\n" +
+	"\x06system\x18\b \x01(\v2\x1b.chat.v1.SystemNotificationH\x00R\x06system\x12/\n" +
+	"\bsnapshot\x18\t \x01(\v2\x11.chat.v1.SnapshotH\x00R\bsnapshot\x12E\n" +
+	"\x10reaction_updated\x18\n" +
+	" \x01(\v2\x18.chat.v1.ReactionUpdatedH\x00R\x0freactionUpdated\x12?\n" +
+	"\x0ethread_updated\x18\v \x01(\v2\x16.chat.v1.ThreadUpdatedH\x00R\rthreadUpdatedB\t\n" +
+	"\apayload\"7\n" +
+	"\bSnapshot\x12+\n" +
+	"\x05chats\x18\x01 \x03(\v2\x15.chat.v1.ChatSnapshotR\x05chats\"\xf2\x01\n" +
+	"\fChatSnapshot\x12!\n" +
+	"\x04chat\x18\x01 \x01(\v2\r.chat.v1.ChatR\x04chat\x12&\n" +
+	"\x0flast_message_id\x18\x02 \x01(\tR\rlastMessageId\x12!\n" +
+	"\funread_count\x18\x03 \x01(\x05R\vunreadCount\x129\n" +
+	"\vread_states\x18\x04 \x03(\v2\x18.chat.v1.MemberReadStateR\n" +
+	"readStates\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xc7\x01\n" +
 	"\x12SendMessageRequest\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12'\n" +
 	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\x121\n" +
-	"\acontent\x18\x03 \x01(\v2\x17.chat.v1.MessageContentR\acontent\"o\n" +
+	"\acontent\x18\x03 \x01(\v2\x17.chat.v1.MessageContentR\acontent\x12)\n" +
+	"\x0ethread_root_id\x18\x04 \x01(\tH\x00R\fthreadRootId\x88\x01\x01B\x11\n" +
+	"\x0f_thread_root_id\"o\n" +
 	"\x13SendMessageResponse\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x129\n" +
 	"\n" +
-	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"a\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"f\n" +
+	"\x12EditMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x121\n" +
+	"\acontent\x18\x02 \x01(\v2\x17.chat.v1.MessageContentR\acontent\"A\n" +
+	"\x13EditMessageResponse\x12*\n" +
+	"\amessage\x18\x01 \x01(\v2\x10.chat.v1.MessageR\amessage\"5\n" +
+	"\x14DeleteMessageRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\"\x17\n" +
+	"\x15DeleteMessageResponse\"H\n" +
+	"\x10SetTypingRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x1b\n" +
+	"\tis_typing\x18\x02 \x01(\bR\bisTyping\"\x13\n" +
+	"\x11SetTypingResponse\"I\n" +
+	"\x0fMarkReadRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"B\n" +
+	"\x10MarkReadResponse\x12.\n" +
+	"\x05state\x18\x01 \x01(\v2\x18.chat.v1.MemberReadStateR\x05state\".\n" +
+	"\x13GetReadStateRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"J\n" +
+	"\x14GetReadStateResponse\x122\n" +
+	"\amembers\x18\x01 \x03(\v2\x18.chat.v1.MemberReadStateR\amembers\"\x90\x01\n" +
+	"\x0fMemberReadState\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12/\n" +
+	"\x14last_read_message_id\x18\x02 \x01(\tR\x11lastReadMessageId\x123\n" +
+	"\aread_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x06readAt\"I\n" +
+	"\x12AddReactionRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x14\n" +
+	"\x05emoji\x18\x02 \x01(\tR\x05emoji\"K\n" +
+	"\x13AddReactionResponse\x124\n" +
+	"\breaction\x18\x01 \x01(\v2\x18.chat.v1.ReactionSummaryR\breaction\"L\n" +
+	"\x15RemoveReactionRequest\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x14\n" +
+	"\x05emoji\x18\x02 \x01(\tR\x05emoji\"N\n" +
+	"\x16RemoveReactionResponse\x124\n" +
+	"\breaction\x18\x01 \x01(\v2\x18.chat.v1.ReactionSummaryR\breaction\"a\n" +
+	"\x0fReactionSummary\x12\x14\n" +
+	"\x05emoji\x18\x01 \x01(\tR\x05emoji\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x05R\x05count\x12\"\n" +
+	"\rreacted_by_me\x18\x03 \x01(\bR\vreactedByMe\"a\n" +
 	"\x11GetHistoryRequest\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x1b\n" +
 	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x16\n" +
@@ -1755,6 +4212,15 @@ const file_chat_v1_chat_proto_rawDesc = "" +
 	"\x12GetHistoryResponse\x12,\n" +
 	"\bmessages\x18\x01 \x03(\v2\x10.chat.v1.MessageR\bmessages\x12\x1f\n" +
 	"\vnext_cursor\x18\x02 \x01(\tR\n" +
+	"nextCursor\"o\n" +
+	"\x10GetThreadRequest\x12&\n" +
+	"\x0froot_message_id\x18\x01 \x01(\tR\rrootMessageId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x16\n" +
+	"\x06cursor\x18\x03 \x01(\tR\x06cursor\"\x86\x01\n" +
+	"\x11GetThreadResponse\x12$\n" +
+	"\x04root\x18\x01 \x01(\v2\x10.chat.v1.MessageR\x04root\x12*\n" +
+	"\areplies\x18\x02 \x03(\v2\x10.chat.v1.MessageR\areplies\x12\x1f\n" +
+	"\vnext_cursor\x18\x03 \x01(\tR\n" +
 	"nextCursor\"m\n" +
 	"\x11CreateChatRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12%\n" +
@@ -1781,7 +4247,48 @@ const file_chat_v1_chat_proto_rawDesc = "" +
 	"\flast_message\x18\x04 \x01(\v2\x10.chat.v1.MessageR\vlastMessage\x12!\n" +
 	"\funread_count\x18\x05 \x01(\x05R\vunreadCount\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"8\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"G\n" +
+	"\x11AddMembersRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x19\n" +
+	"\buser_ids\x18\x02 \x03(\tR\auserIds\"C\n" +
+	"\x12AddMembersResponse\x12-\n" +
+	"\amembers\x18\x01 \x03(\v2\x13.chat.v1.ChatMemberR\amembers\"G\n" +
+	"\x13RemoveMemberRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x16\n" +
+	"\x14RemoveMemberResponse\"+\n" +
+	"\x10LeaveChatRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\"\x13\n" +
+	"\x11LeaveChatResponse\"t\n" +
+	"\x17UpdateMemberRoleRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12'\n" +
+	"\x04role\x18\x03 \x01(\x0e2\x13.chat.v1.MemberRoleR\x04role\"G\n" +
+	"\x18UpdateMemberRoleResponse\x12+\n" +
+	"\x06member\x18\x01 \x01(\v2\x13.chat.v1.ChatMemberR\x06member\"L\n" +
+	"\x18TransferOwnershipRequest\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"\x1b\n" +
+	"\x19TransferOwnershipResponse\"k\n" +
+	"\x17UploadAttachmentRequest\x12-\n" +
+	"\x04info\x18\x01 \x01(\v2\x17.chat.v1.AttachmentInfoH\x00R\x04info\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"=\n" +
+	"\x0eAttachmentInfo\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x12\n" +
+	"\x04size\x18\x02 \x01(\x03R\x04size\"O\n" +
+	"\x18UploadAttachmentResponse\x123\n" +
+	"\n" +
+	"attachment\x18\x01 \x01(\v2\x13.chat.v1.AttachmentR\n" +
+	"attachment\"p\n" +
+	"\x19DownloadAttachmentRequest\x12#\n" +
+	"\rattachment_id\x18\x01 \x01(\tR\fattachmentId\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x16\n" +
+	"\x06length\x18\x03 \x01(\x03R\x06length\"^\n" +
+	"\x1aDownloadAttachmentResponse\x12\x14\n" +
+	"\x05chunk\x18\x01 \x01(\fR\x05chunk\x12\x16\n" +
+	"\x06offset\x18\x02 \x01(\x03R\x06offset\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x03R\x04size\"8\n" +
 	"\n" +
 	"MessageNew\x12*\n" +
 	"\amessage\x18\x01 \x01(\v2\x10.chat.v1.MessageR\amessage\"\xbd\x01\n" +
@@ -1792,23 +4299,53 @@ const file_chat_v1_chat_proto_rawDesc = "" +
 	"\vnew_content\x18\x03 \x01(\v2\x17.chat.v1.MessageContentR\n" +
 	"newContent\x129\n" +
 	"\n" +
-	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"H\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x83\x01\n" +
 	"\x0eMessageDeleted\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
-	"\achat_id\x18\x02 \x01(\tR\x06chatId\"j\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x129\n" +
+	"\n" +
+	"deleted_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"j\n" +
 	"\x0fTypingIndicator\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12!\n" +
 	"\x04user\x18\x02 \x01(\v2\r.chat.v1.UserR\x04user\x12\x1b\n" +
-	"\tis_typing\x18\x03 \x01(\bR\bisTyping\"^\n" +
+	"\tis_typing\x18\x03 \x01(\bR\bisTyping\"\x93\x01\n" +
 	"\vReadReceipt\x12\x17\n" +
 	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1d\n" +
 	"\n" +
-	"message_id\x18\x03 \x01(\tR\tmessageId\"`\n" +
+	"message_id\x18\x03 \x01(\tR\tmessageId\x123\n" +
+	"\aread_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06readAt\"\xdf\x01\n" +
+	"\x0fReactionUpdated\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05emoji\x18\x04 \x01(\tR\x05emoji\x12\x14\n" +
+	"\x05added\x18\x05 \x01(\bR\x05added\x12\x14\n" +
+	"\x05count\x18\x06 \x01(\x05R\x05count\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xd5\x01\n" +
+	"\rThreadUpdated\x12\x17\n" +
+	"\achat_id\x18\x01 \x01(\tR\x06chatId\x12&\n" +
+	"\x0froot_message_id\x18\x02 \x01(\tR\rrootMessageId\x12\x1f\n" +
+	"\vreply_count\x18\x03 \x01(\x05R\n" +
+	"replyCount\x12\"\n" +
+	"\rlast_reply_id\x18\x04 \x01(\tR\vlastReplyId\x12>\n" +
+	"\rlast_reply_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\vlastReplyAt\"\x89\x01\n" +
 	"\x12SystemNotification\x12\x12\n" +
 	"\x04text\x18\x01 \x01(\tR\x04text\x126\n" +
-	"\x05level\x18\x02 \x01(\x0e2 .chat.v1.SystemNotificationLevelR\x05level\"\xf6\x01\n" +
+	"\x05level\x18\x02 \x01(\x0e2 .chat.v1.SystemNotificationLevelR\x05level\x12'\n" +
+	"\x0fresync_required\x18\x03 \x01(\bR\x0eresyncRequired\"\xa5\x01\n" +
+	"\n" +
+	"Attachment\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12\x1f\n" +
+	"\vuploader_id\x18\x03 \x01(\tR\n" +
+	"uploaderId\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xf6\x01\n" +
 	"\x04Chat\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12%\n" +
@@ -1817,7 +4354,7 @@ const file_chat_v1_chat_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x82\x02\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x94\x04\n" +
 	"\aMessage\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\achat_id\x18\x02 \x01(\tR\x06chatId\x12%\n" +
@@ -1826,15 +4363,42 @@ const file_chat_v1_chat_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x90\x01\n" +
+	"updated_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x129\n" +
+	"\n" +
+	"deleted_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x126\n" +
+	"\treactions\x18\b \x03(\v2\x18.chat.v1.ReactionSummaryR\treactions\x12)\n" +
+	"\x0ethread_root_id\x18\t \x01(\tH\x00R\fthreadRootId\x88\x01\x01\x12\x1f\n" +
+	"\vreply_count\x18\n" +
+	" \x01(\x05R\n" +
+	"replyCount\x12>\n" +
+	"\rlast_reply_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vlastReplyAtB\x11\n" +
+	"\x0f_thread_root_id\"\x9a\x02\n" +
 	"\x0eMessageContent\x12*\n" +
-	"\x04text\x18\x01 \x01(\v2\x14.chat.v1.TextContentH\x00R\x04text\x122\n" +
+	"\x04text\x18\x01 \x01(\v2\x14.chat.v1.TextContentH\x00R\x04text\x12-\n" +
+	"\x05image\x18\x02 \x01(\v2\x15.chat.v1.ImageContentH\x00R\x05image\x12*\n" +
+	"\x04file\x18\x03 \x01(\v2\x14.chat.v1.FileContentH\x00R\x04file\x12-\n" +
+	"\x05voice\x18\x04 \x01(\v2\x15.chat.v1.VoiceContentH\x00R\x05voice\x122\n" +
 	"\x13reply_to_message_id\x18\x05 \x01(\tH\x01R\x10replyToMessageId\x88\x01\x01B\x06\n" +
 	"\x04typeB\x16\n" +
 	"\x14_reply_to_message_id\"-\n" +
 	"\vTextContent\x12\x1e\n" +
 	"\n" +
 	"ciphertext\x18\x01 \x01(\fR\n" +
+	"ciphertext\"S\n" +
+	"\fImageContent\x12#\n" +
+	"\rattachment_id\x18\x01 \x01(\tR\fattachmentId\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\"R\n" +
+	"\vFileContent\x12#\n" +
+	"\rattachment_id\x18\x01 \x01(\tR\fattachmentId\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
+	"ciphertext\"S\n" +
+	"\fVoiceContent\x12#\n" +
+	"\rattachment_id\x18\x01 \x01(\tR\fattachmentId\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x02 \x01(\fR\n" +
 	"ciphertext\"\xa0\x01\n" +
 	"\n" +
 	"ChatMember\x12\x17\n" +
@@ -1859,16 +4423,32 @@ const file_chat_v1_chat_proto_rawDesc = "" +
 	"\x17MEMBER_ROLE_UNSPECIFIED\x10\x00\x12\x16\n" +
 	"\x12MEMBER_ROLE_MEMBER\x10\x01\x12\x15\n" +
 	"\x11MEMBER_ROLE_ADMIN\x10\x02\x12\x15\n" +
-	"\x11MEMBER_ROLE_OWNER\x10\x032\xa7\x03\n" +
+	"\x11MEMBER_ROLE_OWNER\x10\x032\xbd\f\n" +
 	"\vChatService\x12>\n" +
 	"\aConnect\x12\x17.chat.v1.ConnectRequest\x1a\x18.chat.v1.ConnectResponse0\x01\x12H\n" +
-	"\vSendMessage\x12\x1b.chat.v1.SendMessageRequest\x1a\x1c.chat.v1.SendMessageResponse\x12E\n" +
+	"\vSendMessage\x12\x1b.chat.v1.SendMessageRequest\x1a\x1c.chat.v1.SendMessageResponse\x12H\n" +
+	"\vEditMessage\x12\x1b.chat.v1.EditMessageRequest\x1a\x1c.chat.v1.EditMessageResponse\x12N\n" +
+	"\rDeleteMessage\x12\x1d.chat.v1.DeleteMessageRequest\x1a\x1e.chat.v1.DeleteMessageResponse\x12B\n" +
+	"\tSetTyping\x12\x19.chat.v1.SetTypingRequest\x1a\x1a.chat.v1.SetTypingResponse\x12?\n" +
+	"\bMarkRead\x12\x18.chat.v1.MarkReadRequest\x1a\x19.chat.v1.MarkReadResponse\x12K\n" +
+	"\fGetReadState\x12\x1c.chat.v1.GetReadStateRequest\x1a\x1d.chat.v1.GetReadStateResponse\x12H\n" +
+	"\vAddReaction\x12\x1b.chat.v1.AddReactionRequest\x1a\x1c.chat.v1.AddReactionResponse\x12Q\n" +
+	"\x0eRemoveReaction\x12\x1e.chat.v1.RemoveReactionRequest\x1a\x1f.chat.v1.RemoveReactionResponse\x12E\n" +
 	"\n" +
-	"GetHistory\x12\x1a.chat.v1.GetHistoryRequest\x1a\x1b.chat.v1.GetHistoryResponse\x12E\n" +
+	"GetHistory\x12\x1a.chat.v1.GetHistoryRequest\x1a\x1b.chat.v1.GetHistoryResponse\x12B\n" +
+	"\tGetThread\x12\x19.chat.v1.GetThreadRequest\x1a\x1a.chat.v1.GetThreadResponse\x12Y\n" +
+	"\x10UploadAttachment\x12 .chat.v1.UploadAttachmentRequest\x1a!.chat.v1.UploadAttachmentResponse(\x01\x12_\n" +
+	"\x12DownloadAttachment\x12\".chat.v1.DownloadAttachmentRequest\x1a#.chat.v1.DownloadAttachmentResponse0\x01\x12E\n" +
 	"\n" +
 	"CreateChat\x12\x1a.chat.v1.CreateChatRequest\x1a\x1b.chat.v1.CreateChatResponse\x12<\n" +
 	"\aGetChat\x12\x17.chat.v1.GetChatRequest\x1a\x18.chat.v1.GetChatResponse\x12B\n" +
-	"\tListChats\x12\x19.chat.v1.ListChatsRequest\x1a\x1a.chat.v1.ListChatsResponseB6Z4github.com/BeInBloom/grpc-chat/gen/go/chat/v1;chatv1b\x06proto3"
+	"\tListChats\x12\x19.chat.v1.ListChatsRequest\x1a\x1a.chat.v1.ListChatsResponse\x12E\n" +
+	"\n" +
+	"AddMembers\x12\x1a.chat.v1.AddMembersRequest\x1a\x1b.chat.v1.AddMembersResponse\x12K\n" +
+	"\fRemoveMember\x12\x1c.chat.v1.RemoveMemberRequest\x1a\x1d.chat.v1.RemoveMemberResponse\x12B\n" +
+	"\tLeaveChat\x12\x19.chat.v1.LeaveChatRequest\x1a\x1a.chat.v1.LeaveChatResponse\x12W\n" +
+	"\x10UpdateMemberRole\x12 .chat.v1.UpdateMemberRoleRequest\x1a!.chat.v1.UpdateMemberRoleResponse\x12Z\n" +
+	"\x11TransferOwnership\x12!.chat.v1.TransferOwnershipRequest\x1a\".chat.v1.TransferOwnershipResponseB6Z4github.com/BeInBloom/grpc-chat/gen/go/chat/v1;chatv1b\x06proto3"
 
 var (
 	file_chat_v1_chat_proto_rawDescOnce sync.Once
@@ -1883,88 +4463,191 @@ func file_chat_v1_chat_proto_rawDescGZIP() []byte {
 }
 
 var file_chat_v1_chat_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_chat_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_chat_v1_chat_proto_msgTypes = make([]protoimpl.MessageInfo, 66)
 var file_chat_v1_chat_proto_goTypes = []any{
-	(ChatType)(0),                 // 0: chat.v1.ChatType
-	(SystemNotificationLevel)(0),  // 1: chat.v1.SystemNotificationLevel
-	(MemberRole)(0),               // 2: chat.v1.MemberRole
-	(*ConnectRequest)(nil),        // 3: chat.v1.ConnectRequest
-	(*ConnectResponse)(nil),       // 4: chat.v1.ConnectResponse
-	(*SendMessageRequest)(nil),    // 5: chat.v1.SendMessageRequest
-	(*SendMessageResponse)(nil),   // 6: chat.v1.SendMessageResponse
-	(*GetHistoryRequest)(nil),     // 7: chat.v1.GetHistoryRequest
-	(*GetHistoryResponse)(nil),    // 8: chat.v1.GetHistoryResponse
-	(*CreateChatRequest)(nil),     // 9: chat.v1.CreateChatRequest
-	(*CreateChatResponse)(nil),    // 10: chat.v1.CreateChatResponse
-	(*GetChatRequest)(nil),        // 11: chat.v1.GetChatRequest
-	(*GetChatResponse)(nil),       // 12: chat.v1.GetChatResponse
-	(*ListChatsRequest)(nil),      // 13: chat.v1.ListChatsRequest
-	(*ListChatsResponse)(nil),     // 14: chat.v1.ListChatsResponse
-	(*ChatPreview)(nil),           // 15: chat.v1.ChatPreview
-	(*MessageNew)(nil),            // 16: chat.v1.MessageNew
-	(*MessageUpdated)(nil),        // 17: chat.v1.MessageUpdated
-	(*MessageDeleted)(nil),        // 18: chat.v1.MessageDeleted
-	(*TypingIndicator)(nil),       // 19: chat.v1.TypingIndicator
-	(*ReadReceipt)(nil),           // 20: chat.v1.ReadReceipt
-	(*SystemNotification)(nil),    // 21: chat.v1.SystemNotification
-	(*Chat)(nil),                  // 22: chat.v1.Chat
-	(*Message)(nil),               // 23: chat.v1.Message
-	(*MessageContent)(nil),        // 24: chat.v1.MessageContent
-	(*TextContent)(nil),           // 25: chat.v1.TextContent
-	(*ChatMember)(nil),            // 26: chat.v1.ChatMember
-	(*User)(nil),                  // 27: chat.v1.User
-	(*timestamppb.Timestamp)(nil), // 28: google.protobuf.Timestamp
+	(ChatType)(0),                      // 0: chat.v1.ChatType
+	(SystemNotificationLevel)(0),       // 1: chat.v1.SystemNotificationLevel
+	(MemberRole)(0),                    // 2: chat.v1.MemberRole
+	(*ConnectRequest)(nil),             // 3: chat.v1.ConnectRequest
+	(*ConnectResponse)(nil),            // 4: chat.v1.ConnectResponse
+	(*Snapshot)(nil),                   // 5: chat.v1.Snapshot
+	(*ChatSnapshot)(nil),               // 6: chat.v1.ChatSnapshot
+	(*SendMessageRequest)(nil),         // 7: chat.v1.SendMessageRequest
+	(*SendMessageResponse)(nil),        // 8: chat.v1.SendMessageResponse
+	(*EditMessageRequest)(nil),         // 9: chat.v1.EditMessageRequest
+	(*EditMessageResponse)(nil),        // 10: chat.v1.EditMessageResponse
+	(*DeleteMessageRequest)(nil),       // 11: chat.v1.DeleteMessageRequest
+	(*DeleteMessageResponse)(nil),      // 12: chat.v1.DeleteMessageResponse
+	(*SetTypingRequest)(nil),           // 13: chat.v1.SetTypingRequest
+	(*SetTypingResponse)(nil),          // 14: chat.v1.SetTypingResponse
+	(*MarkReadRequest)(nil),            // 15: chat.v1.MarkReadRequest
+	(*MarkReadResponse)(nil),           // 16: chat.v1.MarkReadResponse
+	(*GetReadStateRequest)(nil),        // 17: chat.v1.GetReadStateRequest
+	(*GetReadStateResponse)(nil),       // 18: chat.v1.GetReadStateResponse
+	(*MemberReadState)(nil),            // 19: chat.v1.MemberReadState
+	(*AddReactionRequest)(nil),         // 20: chat.v1.AddReactionRequest
+	(*AddReactionResponse)(nil),        // 21: chat.v1.AddReactionResponse
+	(*RemoveReactionRequest)(nil),      // 22: chat.v1.RemoveReactionRequest
+	(*RemoveReactionResponse)(nil),     // 23: chat.v1.RemoveReactionResponse
+	(*ReactionSummary)(nil),            // 24: chat.v1.ReactionSummary
+	(*GetHistoryRequest)(nil),          // 25: chat.v1.GetHistoryRequest
+	(*GetHistoryResponse)(nil),         // 26: chat.v1.GetHistoryResponse
+	(*GetThreadRequest)(nil),           // 27: chat.v1.GetThreadRequest
+	(*GetThreadResponse)(nil),          // 28: chat.v1.GetThreadResponse
+	(*CreateChatRequest)(nil),          // 29: chat.v1.CreateChatRequest
+	(*CreateChatResponse)(nil),         // 30: chat.v1.CreateChatResponse
+	(*GetChatRequest)(nil),             // 31: chat.v1.GetChatRequest
+	(*GetChatResponse)(nil),            // 32: chat.v1.GetChatResponse
+	(*ListChatsRequest)(nil),           // 33: chat.v1.ListChatsRequest
+	(*ListChatsResponse)(nil),          // 34: chat.v1.ListChatsResponse
+	(*ChatPreview)(nil),                // 35: chat.v1.ChatPreview
+	(*AddMembersRequest)(nil),          // 36: chat.v1.AddMembersRequest
+	(*AddMembersResponse)(nil),         // 37: chat.v1.AddMembersResponse
+	(*RemoveMemberRequest)(nil),        // 38: chat.v1.RemoveMemberRequest
+	(*RemoveMemberResponse)(nil),       // 39: chat.v1.RemoveMemberResponse
+	(*LeaveChatRequest)(nil),           // 40: chat.v1.LeaveChatRequest
+	(*LeaveChatResponse)(nil),          // 41: chat.v1.LeaveChatResponse
+	(*UpdateMemberRoleRequest)(nil),    // 42: chat.v1.UpdateMemberRoleRequest
+	(*UpdateMemberRoleResponse)(nil),   // 43: chat.v1.UpdateMemberRoleResponse
+	(*TransferOwnershipRequest)(nil),   // 44: chat.v1.TransferOwnershipRequest
+	(*TransferOwnershipResponse)(nil),  // 45: chat.v1.TransferOwnershipResponse
+	(*UploadAttachmentRequest)(nil),    // 46: chat.v1.UploadAttachmentRequest
+	(*AttachmentInfo)(nil),             // 47: chat.v1.AttachmentInfo
+	(*UploadAttachmentResponse)(nil),   // 48: chat.v1.UploadAttachmentResponse
+	(*DownloadAttachmentRequest)(nil),  // 49: chat.v1.DownloadAttachmentRequest
+	(*DownloadAttachmentResponse)(nil), // 50: chat.v1.DownloadAttachmentResponse
+	(*MessageNew)(nil),                 // 51: chat.v1.MessageNew
+	(*MessageUpdated)(nil),             // 52: chat.v1.MessageUpdated
+	(*MessageDeleted)(nil),             // 53: chat.v1.MessageDeleted
+	(*TypingIndicator)(nil),            // 54: chat.v1.TypingIndicator
+	(*ReadReceipt)(nil),                // 55: chat.v1.ReadReceipt
+	(*ReactionUpdated)(nil),            // 56: chat.v1.ReactionUpdated
+	(*ThreadUpdated)(nil),              // 57: chat.v1.ThreadUpdated
+	(*SystemNotification)(nil),         // 58: chat.v1.SystemNotification
+	(*Attachment)(nil),                 // 59: chat.v1.Attachment
+	(*Chat)(nil),                       // 60: chat.v1.Chat
+	(*Message)(nil),                    // 61: chat.v1.Message
+	(*MessageContent)(nil),             // 62: chat.v1.MessageContent
+	(*TextContent)(nil),                // 63: chat.v1.TextContent
+	(*ImageContent)(nil),               // 64: chat.v1.ImageContent
+	(*FileContent)(nil),                // 65: chat.v1.FileContent
+	(*VoiceContent)(nil),               // 66: chat.v1.VoiceContent
+	(*ChatMember)(nil),                 // 67: chat.v1.ChatMember
+	(*User)(nil),                       // 68: chat.v1.User
+	(*timestamppb.Timestamp)(nil),      // 69: google.protobuf.Timestamp
 }
 var file_chat_v1_chat_proto_depIdxs = []int32{
-	16, // 0: chat.v1.ConnectResponse.message_new:type_name -> chat.v1.MessageNew
-	17, // 1: chat.v1.ConnectResponse.message_updated:type_name -> chat.v1.MessageUpdated
-	18, // 2: chat.v1.ConnectResponse.message_deleted:type_name -> chat.v1.MessageDeleted
-	19, // 3: chat.v1.ConnectResponse.typing:type_name -> chat.v1.TypingIndicator
-	20, // 4: chat.v1.ConnectResponse.read_receipt:type_name -> chat.v1.ReadReceipt
-	21, // 5: chat.v1.ConnectResponse.system:type_name -> chat.v1.SystemNotification
-	24, // 6: chat.v1.SendMessageRequest.content:type_name -> chat.v1.MessageContent
-	28, // 7: chat.v1.SendMessageResponse.created_at:type_name -> google.protobuf.Timestamp
-	23, // 8: chat.v1.GetHistoryResponse.messages:type_name -> chat.v1.Message
-	0,  // 9: chat.v1.CreateChatRequest.type:type_name -> chat.v1.ChatType
-	22, // 10: chat.v1.CreateChatResponse.chat:type_name -> chat.v1.Chat
-	22, // 11: chat.v1.GetChatResponse.chat:type_name -> chat.v1.Chat
-	15, // 12: chat.v1.ListChatsResponse.chats:type_name -> chat.v1.ChatPreview
-	0,  // 13: chat.v1.ChatPreview.type:type_name -> chat.v1.ChatType
-	23, // 14: chat.v1.ChatPreview.last_message:type_name -> chat.v1.Message
-	28, // 15: chat.v1.ChatPreview.updated_at:type_name -> google.protobuf.Timestamp
-	23, // 16: chat.v1.MessageNew.message:type_name -> chat.v1.Message
-	24, // 17: chat.v1.MessageUpdated.new_content:type_name -> chat.v1.MessageContent
-	28, // 18: chat.v1.MessageUpdated.updated_at:type_name -> google.protobuf.Timestamp
-	27, // 19: chat.v1.TypingIndicator.user:type_name -> chat.v1.User
-	1,  // 20: chat.v1.SystemNotification.level:type_name -> chat.v1.SystemNotificationLevel
-	0,  // 21: chat.v1.Chat.type:type_name -> chat.v1.ChatType
-	26, // 22: chat.v1.Chat.members:type_name -> chat.v1.ChatMember
-	28, // 23: chat.v1.Chat.created_at:type_name -> google.protobuf.Timestamp
-	28, // 24: chat.v1.Chat.updated_at:type_name -> google.protobuf.Timestamp
-	27, // 25: chat.v1.Message.sender:type_name -> chat.v1.User
-	24, // 26: chat.v1.Message.content:type_name -> chat.v1.MessageContent
-	28, // 27: chat.v1.Message.created_at:type_name -> google.protobuf.Timestamp
-	28, // 28: chat.v1.Message.updated_at:type_name -> google.protobuf.Timestamp
-	25, // 29: chat.v1.MessageContent.text:type_name -> chat.v1.TextContent
-	2,  // 30: chat.v1.ChatMember.role:type_name -> chat.v1.MemberRole
-	28, // 31: chat.v1.ChatMember.joined_at:type_name -> google.protobuf.Timestamp
-	3,  // 32: chat.v1.ChatService.Connect:input_type -> chat.v1.ConnectRequest
-	5,  // 33: chat.v1.ChatService.SendMessage:input_type -> chat.v1.SendMessageRequest
-	7,  // 34: chat.v1.ChatService.GetHistory:input_type -> chat.v1.GetHistoryRequest
-	9,  // 35: chat.v1.ChatService.CreateChat:input_type -> chat.v1.CreateChatRequest
-	11, // 36: chat.v1.ChatService.GetChat:input_type -> chat.v1.GetChatRequest
-	13, // 37: chat.v1.ChatService.ListChats:input_type -> chat.v1.ListChatsRequest
-	4,  // 38: chat.v1.ChatService.Connect:output_type -> chat.v1.ConnectResponse
-	6,  // 39: chat.v1.ChatService.SendMessage:output_type -> chat.v1.SendMessageResponse
-	8,  // 40: chat.v1.ChatService.GetHistory:output_type -> chat.v1.GetHistoryResponse
-	10, // 41: chat.v1.ChatService.CreateChat:output_type -> chat.v1.CreateChatResponse
-	12, // 42: chat.v1.ChatService.GetChat:output_type -> chat.v1.GetChatResponse
-	14, // 43: chat.v1.ChatService.ListChats:output_type -> chat.v1.ListChatsResponse
-	38, // [38:44] is the sub-list for method output_type
-	32, // [32:38] is the sub-list for method input_type
-	32, // [32:32] is the sub-list for extension type_name
-	32, // [32:32] is the sub-list for extension extendee
-	0,  // [0:32] is the sub-list for field type_name
+	51, // 0: chat.v1.ConnectResponse.message_new:type_name -> chat.v1.MessageNew
+	52, // 1: chat.v1.ConnectResponse.message_updated:type_name -> chat.v1.MessageUpdated
+	53, // 2: chat.v1.ConnectResponse.message_deleted:type_name -> chat.v1.MessageDeleted
+	54, // 3: chat.v1.ConnectResponse.typing:type_name -> chat.v1.TypingIndicator
+	55, // 4: chat.v1.ConnectResponse.read_receipt:type_name -> chat.v1.ReadReceipt
+	58, // 5: chat.v1.ConnectResponse.system:type_name -> chat.v1.SystemNotification
+	5,  // 6: chat.v1.ConnectResponse.snapshot:type_name -> chat.v1.Snapshot
+	56, // 7: chat.v1.ConnectResponse.reaction_updated:type_name -> chat.v1.ReactionUpdated
+	57, // 8: chat.v1.ConnectResponse.thread_updated:type_name -> chat.v1.ThreadUpdated
+	6,  // 9: chat.v1.Snapshot.chats:type_name -> chat.v1.ChatSnapshot
+	60, // 10: chat.v1.ChatSnapshot.chat:type_name -> chat.v1.Chat
+	19, // 11: chat.v1.ChatSnapshot.read_states:type_name -> chat.v1.MemberReadState
+	69, // 12: chat.v1.ChatSnapshot.updated_at:type_name -> google.protobuf.Timestamp
+	62, // 13: chat.v1.SendMessageRequest.content:type_name -> chat.v1.MessageContent
+	69, // 14: chat.v1.SendMessageResponse.created_at:type_name -> google.protobuf.Timestamp
+	62, // 15: chat.v1.EditMessageRequest.content:type_name -> chat.v1.MessageContent
+	61, // 16: chat.v1.EditMessageResponse.message:type_name -> chat.v1.Message
+	19, // 17: chat.v1.MarkReadResponse.state:type_name -> chat.v1.MemberReadState
+	19, // 18: chat.v1.GetReadStateResponse.members:type_name -> chat.v1.MemberReadState
+	69, // 19: chat.v1.MemberReadState.read_at:type_name -> google.protobuf.Timestamp
+	24, // 20: chat.v1.AddReactionResponse.reaction:type_name -> chat.v1.ReactionSummary
+	24, // 21: chat.v1.RemoveReactionResponse.reaction:type_name -> chat.v1.ReactionSummary
+	61, // 22: chat.v1.GetHistoryResponse.messages:type_name -> chat.v1.Message
+	61, // 23: chat.v1.GetThreadResponse.root:type_name -> chat.v1.Message
+	61, // 24: chat.v1.GetThreadResponse.replies:type_name -> chat.v1.Message
+	0,  // 25: chat.v1.CreateChatRequest.type:type_name -> chat.v1.ChatType
+	60, // 26: chat.v1.CreateChatResponse.chat:type_name -> chat.v1.Chat
+	60, // 27: chat.v1.GetChatResponse.chat:type_name -> chat.v1.Chat
+	35, // 28: chat.v1.ListChatsResponse.chats:type_name -> chat.v1.ChatPreview
+	0,  // 29: chat.v1.ChatPreview.type:type_name -> chat.v1.ChatType
+	61, // 30: chat.v1.ChatPreview.last_message:type_name -> chat.v1.Message
+	69, // 31: chat.v1.ChatPreview.updated_at:type_name -> google.protobuf.Timestamp
+	67, // 32: chat.v1.AddMembersResponse.members:type_name -> chat.v1.ChatMember
+	2,  // 33: chat.v1.UpdateMemberRoleRequest.role:type_name -> chat.v1.MemberRole
+	67, // 34: chat.v1.UpdateMemberRoleResponse.member:type_name -> chat.v1.ChatMember
+	47, // 35: chat.v1.UploadAttachmentRequest.info:type_name -> chat.v1.AttachmentInfo
+	59, // 36: chat.v1.UploadAttachmentResponse.attachment:type_name -> chat.v1.Attachment
+	61, // 37: chat.v1.MessageNew.message:type_name -> chat.v1.Message
+	62, // 38: chat.v1.MessageUpdated.new_content:type_name -> chat.v1.MessageContent
+	69, // 39: chat.v1.MessageUpdated.updated_at:type_name -> google.protobuf.Timestamp
+	69, // 40: chat.v1.MessageDeleted.deleted_at:type_name -> google.protobuf.Timestamp
+	68, // 41: chat.v1.TypingIndicator.user:type_name -> chat.v1.User
+	69, // 42: chat.v1.ReadReceipt.read_at:type_name -> google.protobuf.Timestamp
+	69, // 43: chat.v1.ReactionUpdated.updated_at:type_name -> google.protobuf.Timestamp
+	69, // 44: chat.v1.ThreadUpdated.last_reply_at:type_name -> google.protobuf.Timestamp
+	1,  // 45: chat.v1.SystemNotification.level:type_name -> chat.v1.SystemNotificationLevel
+	69, // 46: chat.v1.Attachment.created_at:type_name -> google.protobuf.Timestamp
+	0,  // 47: chat.v1.Chat.type:type_name -> chat.v1.ChatType
+	67, // 48: chat.v1.Chat.members:type_name -> chat.v1.ChatMember
+	69, // 49: chat.v1.Chat.created_at:type_name -> google.protobuf.Timestamp
+	69, // 50: chat.v1.Chat.updated_at:type_name -> google.protobuf.Timestamp
+	68, // 51: chat.v1.Message.sender:type_name -> chat.v1.User
+	62, // 52: chat.v1.Message.content:type_name -> chat.v1.MessageContent
+	69, // 53: chat.v1.Message.created_at:type_name -> google.protobuf.Timestamp
+	69, // 54: chat.v1.Message.updated_at:type_name -> google.protobuf.Timestamp
+	69, // 55: chat.v1.Message.deleted_at:type_name -> google.protobuf.Timestamp
+	24, // 56: chat.v1.Message.reactions:type_name -> chat.v1.ReactionSummary
+	69, // 57: chat.v1.Message.last_reply_at:type_name -> google.protobuf.Timestamp
+	63, // 58: chat.v1.MessageContent.text:type_name -> chat.v1.TextContent
+	64, // 59: chat.v1.MessageContent.image:type_name -> chat.v1.ImageContent
+	65, // 60: chat.v1.MessageContent.file:type_name -> chat.v1.FileContent
+	66, // 61: chat.v1.MessageContent.voice:type_name -> chat.v1.VoiceContent
+	2,  // 62: chat.v1.ChatMember.role:type_name -> chat.v1.MemberRole
+	69, // 63: chat.v1.ChatMember.joined_at:type_name -> google.protobuf.Timestamp
+	3,  // 64: chat.v1.ChatService.Connect:input_type -> chat.v1.ConnectRequest
+	7,  // 65: chat.v1.ChatService.SendMessage:input_type -> chat.v1.SendMessageRequest
+	9,  // 66: chat.v1.ChatService.EditMessage:input_type -> chat.v1.EditMessageRequest
+	11, // 67: chat.v1.ChatService.DeleteMessage:input_type -> chat.v1.DeleteMessageRequest
+	13, // 68: chat.v1.ChatService.SetTyping:input_type -> chat.v1.SetTypingRequest
+	15, // 69: chat.v1.ChatService.MarkRead:input_type -> chat.v1.MarkReadRequest
+	17, // 70: chat.v1.ChatService.GetReadState:input_type -> chat.v1.GetReadStateRequest
+	20, // 71: chat.v1.ChatService.AddReaction:input_type -> chat.v1.AddReactionRequest
+	22, // 72: chat.v1.ChatService.RemoveReaction:input_type -> chat.v1.RemoveReactionRequest
+	25, // 73: chat.v1.ChatService.GetHistory:input_type -> chat.v1.GetHistoryRequest
+	27, // 74: chat.v1.ChatService.GetThread:input_type -> chat.v1.GetThreadRequest
+	46, // 75: chat.v1.ChatService.UploadAttachment:input_type -> chat.v1.UploadAttachmentRequest
+	49, // 76: chat.v1.ChatService.DownloadAttachment:input_type -> chat.v1.DownloadAttachmentRequest
+	29, // 77: chat.v1.ChatService.CreateChat:input_type -> chat.v1.CreateChatRequest
+	31, // 78: chat.v1.ChatService.GetChat:input_type -> chat.v1.GetChatRequest
+	33, // 79: chat.v1.ChatService.ListChats:input_type -> chat.v1.ListChatsRequest
+	36, // 80: chat.v1.ChatService.AddMembers:input_type -> chat.v1.AddMembersRequest
+	38, // 81: chat.v1.ChatService.RemoveMember:input_type -> chat.v1.RemoveMemberRequest
+	40, // 82: chat.v1.ChatService.LeaveChat:input_type -> chat.v1.LeaveChatRequest
+	42, // 83: chat.v1.ChatService.UpdateMemberRole:input_type -> chat.v1.UpdateMemberRoleRequest
+	44, // 84: chat.v1.ChatService.TransferOwnership:input_type -> chat.v1.TransferOwnershipRequest
+	4,  // 85: chat.v1.ChatService.Connect:output_type -> chat.v1.ConnectResponse
+	8,  // 86: chat.v1.ChatService.SendMessage:output_type -> chat.v1.SendMessageResponse
+	10, // 87: chat.v1.ChatService.EditMessage:output_type -> chat.v1.EditMessageResponse
+	12, // 88: chat.v1.ChatService.DeleteMessage:output_type -> chat.v1.DeleteMessageResponse
+	14, // 89: chat.v1.ChatService.SetTyping:output_type -> chat.v1.SetTypingResponse
+	16, // 90: chat.v1.ChatService.MarkRead:output_type -> chat.v1.MarkReadResponse
+	18, // 91: chat.v1.ChatService.GetReadState:output_type -> chat.v1.GetReadStateResponse
+	21, // 92: chat.v1.ChatService.AddReaction:output_type -> chat.v1.AddReactionResponse
+	23, // 93: chat.v1.ChatService.RemoveReaction:output_type -> chat.v1.RemoveReactionResponse
+	26, // 94: chat.v1.ChatService.GetHistory:output_type -> chat.v1.GetHistoryResponse
+	28, // 95: chat.v1.ChatService.GetThread:output_type -> chat.v1.GetThreadResponse
+	48, // 96: chat.v1.ChatService.UploadAttachment:output_type -> chat.v1.UploadAttachmentResponse
+	50, // 97: chat.v1.ChatService.DownloadAttachment:output_type -> chat.v1.DownloadAttachmentResponse
+	30, // 98: chat.v1.ChatService.CreateChat:output_type -> chat.v1.CreateChatResponse
+	32, // 99: chat.v1.ChatService.GetChat:output_type -> chat.v1.GetChatResponse
+	34, // 100: chat.v1.ChatService.ListChats:output_type -> chat.v1.ListChatsResponse
+	37, // 101: chat.v1.ChatService.AddMembers:output_type -> chat.v1.AddMembersResponse
+	39, // 102: chat.v1.ChatService.RemoveMember:output_type -> chat.v1.RemoveMemberResponse
+	41, // 103: chat.v1.ChatService.LeaveChat:output_type -> chat.v1.LeaveChatResponse
+	43, // 104: chat.v1.ChatService.UpdateMemberRole:output_type -> chat.v1.UpdateMemberRoleResponse
+	45, // 105: chat.v1.ChatService.TransferOwnership:output_type -> chat.v1.TransferOwnershipResponse
+	85, // [85:106] is the sub-list for method output_type
+	64, // [64:85] is the sub-list for method input_type
+	64, // [64:64] is the sub-list for extension type_name
+	64, // [64:64] is the sub-list for extension extendee
+	0,  // [0:64] is the sub-list for field type_name
 }
 
 func init() { file_chat_v1_chat_proto_init() }
@@ -1980,9 +4663,21 @@ func file_chat_v1_chat_proto_init() {
 		(*ConnectResponse_Typing)(nil),
 		(*ConnectResponse_ReadReceipt)(nil),
 		(*ConnectResponse_System)(nil),
+		(*ConnectResponse_Snapshot)(nil),
+		(*ConnectResponse_ReactionUpdated)(nil),
+		(*ConnectResponse_ThreadUpdated)(nil),
+	}
+	file_chat_v1_chat_proto_msgTypes[4].OneofWrappers = []any{}
+	file_chat_v1_chat_proto_msgTypes[43].OneofWrappers = []any{
+		(*UploadAttachmentRequest_Info)(nil),
+		(*UploadAttachmentRequest_Chunk)(nil),
 	}
-	file_chat_v1_chat_proto_msgTypes[21].OneofWrappers = []any{
+	file_chat_v1_chat_proto_msgTypes[58].OneofWrappers = []any{}
+	file_chat_v1_chat_proto_msgTypes[59].OneofWrappers = []any{
 		(*MessageContent_Text)(nil),
+		(*MessageContent_Image)(nil),
+		(*MessageContent_File)(nil),
+		(*MessageContent_Voice)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
@@ -1990,7 +4685,7 @@ func file_chat_v1_chat_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_chat_v1_chat_proto_rawDesc), len(file_chat_v1_chat_proto_rawDesc)),
 			NumEnums:      3,
-			NumMessages:   25,
+			NumMessages:   66,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,126 @@
+package authtoken
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const Algorithm = "EdDSA"
+
+var ErrInvalidKey = errors.New("invalid ed25519 key")
+
+type Claims struct {
+	jwt.RegisteredClaims
+	Role      int32  `json:"role"`
+	SessionID string `json:"sid"`
+}
+
+type Signer struct {
+	key    ed25519.PrivateKey
+	keyID  string
+	issuer string
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewSigner(key ed25519.PrivateKey, issuer string, ttl time.Duration) *Signer {
+	return &Signer{
+		key:    key,
+		keyID:  KeyID(key.Public().(ed25519.PublicKey)),
+		issuer: issuer,
+		ttl:    ttl,
+		now:    time.Now,
+	}
+}
+
+func (s *Signer) Sign(userID uuid.UUID, role int32, sessionID uuid.UUID) (string, time.Time, error) {
+	now := s.now()
+	expiresAt := now.Add(s.ttl)
+
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    s.issuer,
+			Subject:   userID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+		Role:      role,
+		SessionID: sessionID.String(),
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims)
+	token.Header["kid"] = s.keyID
+
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("sign access token: %w", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+func (s *Signer) PublicKey() ed25519.PublicKey {
+	return s.key.Public().(ed25519.PublicKey)
+}
+
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// KeyID derives a stable key identifier from the public key so that
+// verifiers can pick the right key during rotation.
+func KeyID(key ed25519.PublicKey) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ParsePublicKey decodes a base64-encoded ed25519 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: unexpected public key length %d", ErrInvalidKey, len(raw))
+	}
+
+	return ed25519.PublicKey(raw), nil
+}
+
+// ParsePrivateKey decodes a base64-encoded ed25519 seed or full private key.
+func ParsePrivateKey(encoded string) (ed25519.PrivateKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("%w: unexpected private key length %d", ErrInvalidKey, len(raw))
+	}
+}
+
+func GeneratePrivateKey() (ed25519.PrivateKey, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generate ed25519 key: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,102 @@
+package authtoken
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSigner(t *testing.T, ttl time.Duration) *Signer {
+	t.Helper()
+
+	key, err := GeneratePrivateKey()
+	require.NoError(t, err)
+
+	return NewSigner(key, "test-issuer", ttl)
+}
+
+func TestVerifier_RoundTrip(t *testing.T) {
+	signer := newTestSigner(t, time.Minute)
+	verifier := NewStaticVerifier("test-issuer", signer.PublicKey())
+
+	userID, sessionID := uuid.New(), uuid.New()
+	token, _, err := signer.Sign(userID, 2, sessionID)
+	require.NoError(t, err)
+
+	identity, err := verifier.Verify(context.Background(), token)
+
+	require.NoError(t, err)
+	assert.Equal(t, Identity{UserID: userID, Role: 2, SessionID: sessionID}, identity)
+}
+
+func TestVerifier_Rejects(t *testing.T) {
+	signer := newTestSigner(t, time.Minute)
+	other := newTestSigner(t, time.Minute)
+	expired := NewSigner(signer.key, "test-issuer", -time.Minute)
+	wrongIssuer := NewSigner(signer.key, "other-issuer", time.Minute)
+
+	verifier := NewStaticVerifier("test-issuer", signer.PublicKey())
+
+	tests := map[string]*Signer{
+		"unknown key":  other,
+		"expired":      expired,
+		"wrong issuer": wrongIssuer,
+	}
+
+	for name, s := range tests {
+		t.Run(name, func(t *testing.T) {
+			token, _, err := s.Sign(uuid.New(), 1, uuid.New())
+			require.NoError(t, err)
+
+			_, err = verifier.Verify(context.Background(), token)
+			assert.ErrorIs(t, err, ErrInvalidToken)
+		})
+	}
+
+	_, err := verifier.Verify(context.Background(), "not-a-token")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestVerifier_RemoteRefetchesOnUnknownKey(t *testing.T) {
+	first := newTestSigner(t, time.Minute)
+	rotated := newTestSigner(t, time.Minute)
+
+	current := []ed25519.PublicKey{first.PublicKey()}
+	fetches := 0
+	verifier := NewRemoteVerifier("test-issuer", func(context.Context) ([]ed25519.PublicKey, error) {
+		fetches++
+		return current, nil
+	}, 0)
+
+	token, _, err := first.Sign(uuid.New(), 1, uuid.New())
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), token)
+	require.NoError(t, err)
+
+	current = []ed25519.PublicKey{rotated.PublicKey()}
+	token, _, err = rotated.Sign(uuid.New(), 1, uuid.New())
+	require.NoError(t, err)
+	_, err = verifier.Verify(context.Background(), token)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, fetches)
+}
+
+func TestVerifier_RemoteFetchError(t *testing.T) {
+	signer := newTestSigner(t, time.Minute)
+	verifier := NewRemoteVerifier("test-issuer", func(context.Context) ([]ed25519.PublicKey, error) {
+		return nil, errors.New("unavailable")
+	}, time.Minute)
+
+	token, _, err := signer.Sign(uuid.New(), 1, uuid.New())
+	require.NoError(t, err)
+
+	_, err = verifier.Verify(context.Background(), token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
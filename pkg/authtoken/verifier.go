@@ -0,0 +1,141 @@
+package authtoken
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid access token")
+	ErrUnknownKey   = errors.New("unknown signing key")
+)
+
+// KeyFetcher loads the current set of signing keys, e.g. from the auth service.
+type KeyFetcher func(ctx context.Context) ([]ed25519.PublicKey, error)
+
+type Identity struct {
+	UserID    uuid.UUID
+	Role      int32
+	SessionID uuid.UUID
+}
+
+type Verifier struct {
+	issuer     string
+	fetch      KeyFetcher
+	minRefresh time.Duration
+
+	mu        sync.RWMutex
+	keys      map[string]ed25519.PublicKey
+	fetchedAt time.Time
+}
+
+// NewStaticVerifier verifies tokens against a fixed set of keys.
+func NewStaticVerifier(issuer string, keys ...ed25519.PublicKey) *Verifier {
+	v := &Verifier{issuer: issuer}
+	v.setKeys(keys)
+
+	return v
+}
+
+// NewRemoteVerifier fetches keys lazily and refetches them when a token
+// references an unknown key ID, at most once per minRefresh.
+func NewRemoteVerifier(issuer string, fetch KeyFetcher, minRefresh time.Duration) *Verifier {
+	return &Verifier{
+		issuer:     issuer,
+		fetch:      fetch,
+		minRefresh: minRefresh,
+		keys:       make(map[string]ed25519.PublicKey),
+	}
+}
+
+func (v *Verifier) Verify(ctx context.Context, token string) (Identity, error) {
+	var claims Claims
+
+	_, err := jwt.ParseWithClaims(token, &claims,
+		func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return v.key(ctx, kid)
+		},
+		jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %s", ErrInvalidToken, err)
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: invalid subject: %s", ErrInvalidToken, err)
+	}
+
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: invalid session id: %s", ErrInvalidToken, err)
+	}
+
+	return Identity{
+		UserID:    userID,
+		Role:      claims.Role,
+		SessionID: sessionID,
+	}, nil
+}
+
+func (v *Verifier) key(ctx context.Context, kid string) (ed25519.PublicKey, error) {
+	v.mu.RLock()
+	key, ok := v.keys[kid]
+	v.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	if key, ok := v.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+}
+
+func (v *Verifier) refresh(ctx context.Context) error {
+	if v.fetch == nil {
+		return nil
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !v.fetchedAt.IsZero() && time.Since(v.fetchedAt) < v.minRefresh {
+		return nil
+	}
+
+	keys, err := v.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("fetch signing keys: %w", err)
+	}
+
+	v.fetchedAt = time.Now()
+	v.setKeys(keys)
+
+	return nil
+}
+
+func (v *Verifier) setKeys(keys []ed25519.PublicKey) {
+	v.keys = make(map[string]ed25519.PublicKey, len(keys))
+	for _, key := range keys {
+		v.keys[KeyID(key)] = key
+	}
+}
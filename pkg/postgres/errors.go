@@ -0,0 +1,20 @@
+package postgres
+
+import (
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const uniqueViolation = "23505"
+
+// IsUniqueViolation reports whether err is a unique constraint violation,
+// optionally restricted to the given constraint name.
+func IsUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+		return false
+	}
+
+	return constraint == "" || pgErr.ConstraintName == constraint
+}
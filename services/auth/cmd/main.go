@@ -17,6 +17,7 @@ func main() {
 
 	config := config.New()
 	c := container.New(config)
+	defer c.Close()
 	log := c.Logger()
 
 	log.Info("starting auth app...")
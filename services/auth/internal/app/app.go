@@ -13,22 +13,25 @@ import (
 )
 
 type App struct {
-	handlers authv1.UserAPIServiceServer
-	logger   *slog.Logger
-	addr     string
+	handlers     authv1.UserAPIServiceServer
+	authHandlers authv1.AuthServiceServer
+	logger       *slog.Logger
+	addr         string
 }
 
 func New(
 	addr string,
 	logger *slog.Logger,
 	handlers authv1.UserAPIServiceServer,
+	authHandlers authv1.AuthServiceServer,
 ) *App {
 	logger = logger.With("layer", "auth app")
 
 	return &App{
-		logger:   logger,
-		handlers: handlers,
-		addr:     addr,
+		logger:       logger,
+		handlers:     handlers,
+		authHandlers: authHandlers,
+		addr:         addr,
 	}
 }
 
@@ -40,6 +43,7 @@ func (a *App) Run(ctx context.Context) error {
 
 	grpcServer := grpc.NewServer()
 	authv1.RegisterUserAPIServiceServer(grpcServer, a.handlers)
+	authv1.RegisterAuthServiceServer(grpcServer, a.authHandlers)
 	reflection.Register(grpcServer)
 
 	a.logger.Info("auth service listening", slog.String("addr", a.addr))
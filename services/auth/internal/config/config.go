@@ -3,15 +3,45 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 
 	"github.com/BeInBloom/grpc-chat/pkg/logger"
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
 )
 
 type Config struct {
-	Addr   string        `yaml:"addr" env:"addr" env-default:"localhost:50051"`
-	Logger logger.Config `yaml:"logger"`
+	Addr     string         `yaml:"addr" env:"addr" env-default:"localhost:50051"`
+	Logger   logger.Config  `yaml:"logger"`
+	Token    TokenConfig    `yaml:"token"`
+	Password PasswordConfig `yaml:"password"`
+	Storage  StorageConfig  `yaml:"storage"`
+}
+
+type StorageConfig struct {
+	// Driver selects the user store: memory or postgres.
+	Driver   string          `yaml:"driver" env:"STORAGE_DRIVER" env-default:"memory"`
+	Postgres postgres.Config `yaml:"postgres"`
+}
+
+type TokenConfig struct {
+	Issuer string `yaml:"issuer" env:"TOKEN_ISSUER" env-default:"grpc-chat-auth"`
+	// SigningKey is a base64-encoded ed25519 seed; an ephemeral key is
+	// generated when it is empty.
+	SigningKey string        `yaml:"signing_key" env:"TOKEN_SIGNING_KEY"`
+	AccessTTL  time.Duration `yaml:"access_ttl" env:"TOKEN_ACCESS_TTL" env-default:"15m"`
+	RefreshTTL time.Duration `yaml:"refresh_ttl" env:"TOKEN_REFRESH_TTL" env-default:"720h"`
+}
+
+type PasswordConfig struct {
+	// Algorithm used for new hashes: argon2id or bcrypt. Hashes produced by
+	// the other one are still accepted and upgraded on the next login.
+	Algorithm     string `yaml:"algorithm" env:"PASSWORD_ALGORITHM" env-default:"argon2id"`
+	Argon2Memory  uint32 `yaml:"argon2_memory" env:"PASSWORD_ARGON2_MEMORY" env-default:"65536"`
+	Argon2Time    uint32 `yaml:"argon2_time" env:"PASSWORD_ARGON2_TIME" env-default:"3"`
+	Argon2Threads uint8  `yaml:"argon2_threads" env:"PASSWORD_ARGON2_THREADS" env-default:"2"`
+	BcryptCost    int    `yaml:"bcrypt_cost" env:"PASSWORD_BCRYPT_COST" env-default:"12"`
 }
 
 func New() Config {
@@ -1,23 +1,58 @@
 package container
 
 import (
+	"context"
+	"crypto/ed25519"
+	"log"
 	"log/slog"
+	"time"
 
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/authtoken"
 	"github.com/BeInBloom/grpc-chat/pkg/logger"
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/app"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/config"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/handler"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/password"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/repository"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/services"
 )
 
+const startupTimeout = 30 * time.Second
+
+type (
+	userRepository interface {
+		Create(ctx context.Context, user models.User) (uuid.UUID, error)
+		Get(ctx context.Context, id uuid.UUID) (models.User, error)
+		GetByEmail(ctx context.Context, email string) (models.User, error)
+		Update(ctx context.Context, user models.User) error
+		Delete(ctx context.Context, id uuid.UUID) error
+	}
+
+	tokenRepository interface {
+		Create(ctx context.Context, token models.RefreshToken) error
+		Use(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+		RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	}
+)
+
 type container struct {
-	config      config.Config
-	userService *services.UserService
-	userRepo    *repository.UserRepository
-	logger      *slog.Logger
-	handlers    *handler.UserHandler
-	app         *app.App
+	config       config.Config
+	userService  *services.UserService
+	authService  *services.AuthService
+	userRepo     userRepository
+	tokenRepo    tokenRepository
+	pool         *pgxpool.Pool
+	signer       *authtoken.Signer
+	hasher       *password.Hasher
+	logger       *slog.Logger
+	handlers     *handler.UserHandler
+	authHandlers *handler.AuthHandler
+	app          *app.App
 }
 
 func New(cfg config.Config) *container {
@@ -26,7 +61,7 @@ func New(cfg config.Config) *container {
 
 func (c *container) App() *app.App {
 	if c.app == nil {
-		c.app = app.New(c.config.Addr, c.Logger(), c.Handler())
+		c.app = app.New(c.config.Addr, c.Logger(), c.Handler(), c.AuthHandler())
 	}
 
 	return c.app
@@ -40,14 +75,88 @@ func (c *container) Handler() *handler.UserHandler {
 	return c.handlers
 }
 
+func (c *container) AuthHandler() *handler.AuthHandler {
+	if c.authHandlers == nil {
+		c.authHandlers = handler.NewAuthHandler(c.AuthService())
+	}
+
+	return c.authHandlers
+}
+
 func (c *container) UserService() *services.UserService {
 	if c.userService == nil {
-		c.userService = services.New(c.UserRepo())
+		c.userService = services.New(c.UserRepo(), c.Hasher())
 	}
 
 	return c.userService
 }
 
+func (c *container) AuthService() *services.AuthService {
+	if c.authService == nil {
+		c.authService = services.NewAuthService(
+			c.UserRepo(),
+			c.TokenRepo(),
+			c.Signer(),
+			c.Hasher(),
+			c.config.Token.RefreshTTL,
+		)
+	}
+
+	return c.authService
+}
+
+func (c *container) Hasher() *password.Hasher {
+	if c.hasher == nil {
+		cfg := c.config.Password
+		argon2id := password.NewArgon2id(password.Argon2idParams{
+			Memory:      cfg.Argon2Memory,
+			Iterations:  cfg.Argon2Time,
+			Parallelism: cfg.Argon2Threads,
+			SaltLength:  password.DefaultArgon2idParams.SaltLength,
+			KeyLength:   password.DefaultArgon2idParams.KeyLength,
+		})
+		bcrypt := password.NewBcrypt(cfg.BcryptCost)
+
+		switch cfg.Algorithm {
+		case "argon2id":
+			c.hasher = password.New(argon2id, bcrypt)
+		case "bcrypt":
+			c.hasher = password.New(bcrypt, argon2id)
+		default:
+			log.Fatalf("unsupported password algorithm: %s", cfg.Algorithm)
+		}
+	}
+
+	return c.hasher
+}
+
+func (c *container) Signer() *authtoken.Signer {
+	if c.signer == nil {
+		c.signer = authtoken.NewSigner(c.signingKey(), c.config.Token.Issuer, c.config.Token.AccessTTL)
+	}
+
+	return c.signer
+}
+
+func (c *container) signingKey() ed25519.PrivateKey {
+	if c.config.Token.SigningKey == "" {
+		key, err := authtoken.GeneratePrivateKey()
+		if err != nil {
+			log.Fatalf("cannot generate signing key: %s", err)
+		}
+		c.Logger().Warn("token signing key is not configured, using an ephemeral key")
+
+		return key
+	}
+
+	key, err := authtoken.ParsePrivateKey(c.config.Token.SigningKey)
+	if err != nil {
+		log.Fatalf("cannot parse signing key: %s", err)
+	}
+
+	return key
+}
+
 func (c *container) Logger() *slog.Logger {
 	if c.logger == nil {
 		c.logger = logger.New(c.config.Logger)
@@ -56,14 +165,63 @@ func (c *container) Logger() *slog.Logger {
 	return c.logger
 }
 
-func (c *container) UserRepo() *repository.UserRepository {
+func (c *container) UserRepo() userRepository {
 	if c.userRepo == nil {
-		c.userRepo = repository.New()
+		switch c.config.Storage.Driver {
+		case "memory":
+			c.userRepo = repository.New()
+		case "postgres":
+			c.userRepo = repository.NewPostgres(c.Pool())
+		default:
+			log.Fatalf("unsupported storage driver: %s", c.config.Storage.Driver)
+		}
 	}
 
 	return c.userRepo
 }
 
+func (c *container) TokenRepo() tokenRepository {
+	if c.tokenRepo == nil {
+		switch c.config.Storage.Driver {
+		case "memory":
+			c.tokenRepo = repository.NewTokenRepository()
+		case "postgres":
+			c.tokenRepo = repository.NewPostgresTokenRepository(c.Pool())
+		default:
+			log.Fatalf("unsupported storage driver: %s", c.config.Storage.Driver)
+		}
+	}
+
+	return c.tokenRepo
+}
+
+func (c *container) Pool() *pgxpool.Pool {
+	if c.pool == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+		defer cancel()
+
+		pool, err := postgres.NewPool(ctx, c.config.Storage.Postgres)
+		if err != nil {
+			log.Fatalf("cannot connect to postgres: %s", err)
+		}
+
+		if err := postgres.Migrate(ctx, pool, repository.Migrations, repository.MigrationsDir); err != nil {
+			pool.Close()
+			log.Fatalf("cannot apply migrations: %s", err)
+		}
+
+		c.pool = pool
+	}
+
+	return c.pool
+}
+
+func (c *container) Close() {
+	if c.pool != nil {
+		c.pool.Close()
+	}
+}
+
 func (c *container) Config() config.Config {
 	return c.config
 }
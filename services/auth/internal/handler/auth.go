@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	authv1 "github.com/BeInBloom/grpc-chat/gen/go/auth/v1"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+)
+
+//go:generate mockgen -source=auth.go -destination=mocks/mock_auth_service.go -package=mocks
+
+type authService interface {
+	Login(ctx context.Context, email, password string) (models.TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error)
+	PublicKeys(ctx context.Context) []models.PublicKey
+}
+
+type AuthHandler struct {
+	authv1.UnimplementedAuthServiceServer
+	service authService
+}
+
+func NewAuthHandler(service authService) *AuthHandler {
+	return &AuthHandler{service: service}
+}
+
+func (h *AuthHandler) Login(ctx context.Context, req *authv1.LoginRequest) (*authv1.LoginResponse, error) {
+	if req.GetEmail() == "" || req.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "email and password are required")
+	}
+
+	pair, err := h.service.Login(ctx, req.GetEmail(), req.GetPassword())
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoLoginResponse(pair), nil
+}
+
+func (h *AuthHandler) RefreshToken(
+	ctx context.Context,
+	req *authv1.RefreshTokenRequest,
+) (*authv1.RefreshTokenResponse, error) {
+	if req.GetRefreshToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
+
+	pair, err := h.service.Refresh(ctx, req.GetRefreshToken())
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoRefreshTokenResponse(pair), nil
+}
+
+func (h *AuthHandler) GetPublicKeys(
+	ctx context.Context,
+	_ *authv1.GetPublicKeysRequest,
+) (*authv1.GetPublicKeysResponse, error) {
+	return toProtoPublicKeysResponse(h.service.PublicKeys(ctx)), nil
+}
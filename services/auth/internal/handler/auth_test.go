@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	authv1 "github.com/BeInBloom/grpc-chat/gen/go/auth/v1"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/handler/mocks"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/services"
+)
+
+func TestAuthHandler_Login(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockauthService(ctrl)
+	handler := NewAuthHandler(mockService)
+
+	ctx := context.Background()
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockService.EXPECT().
+		Login(ctx, "test@example.com", "secret123").
+		Return(models.TokenPair{
+			AccessToken:      "access",
+			RefreshToken:     "refresh",
+			RefreshExpiresAt: expiresAt,
+		}, nil)
+
+	resp, err := handler.Login(ctx, &authv1.LoginRequest{
+		Email:    "test@example.com",
+		Password: "secret123",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "access", resp.GetAccessToken())
+	assert.Equal(t, "refresh", resp.GetRefreshToken())
+	assert.Equal(t, expiresAt, resp.GetExpiresAt().AsTime())
+}
+
+func TestAuthHandler_LoginMissingCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := NewAuthHandler(mocks.NewMockauthService(ctrl))
+
+	resp, err := handler.Login(context.Background(), &authv1.LoginRequest{Email: "test@example.com"})
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+}
+
+func TestAuthHandler_LoginInvalidCredentials(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockauthService(ctrl)
+	handler := NewAuthHandler(mockService)
+
+	ctx := context.Background()
+
+	mockService.EXPECT().
+		Login(ctx, gomock.Any(), gomock.Any()).
+		Return(models.TokenPair{}, services.ErrInvalidCredentials)
+
+	resp, err := handler.Login(ctx, &authv1.LoginRequest{
+		Email:    "test@example.com",
+		Password: "wrong",
+	})
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Unauthenticated, st.Code())
+}
+
+func TestAuthHandler_RefreshTokenReused(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockauthService(ctrl)
+	handler := NewAuthHandler(mockService)
+
+	ctx := context.Background()
+
+	mockService.EXPECT().
+		Refresh(ctx, "refresh").
+		Return(models.TokenPair{}, services.ErrRefreshTokenReused)
+
+	resp, err := handler.RefreshToken(ctx, &authv1.RefreshTokenRequest{RefreshToken: "refresh"})
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Unauthenticated, st.Code())
+}
+
+func TestAuthHandler_GetPublicKeys(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockauthService(ctrl)
+	handler := NewAuthHandler(mockService)
+
+	ctx := context.Background()
+
+	mockService.EXPECT().
+		PublicKeys(ctx).
+		Return([]models.PublicKey{{KeyID: "kid", Algorithm: "EdDSA", Key: []byte("key")}})
+
+	resp, err := handler.GetPublicKeys(ctx, &authv1.GetPublicKeysRequest{})
+
+	require.NoError(t, err)
+	require.Len(t, resp.GetKeys(), 1)
+	assert.Equal(t, "kid", resp.GetKeys()[0].GetKeyId())
+	assert.Equal(t, "EdDSA", resp.GetKeys()[0].GetAlgorithm())
+	assert.Equal(t, []byte("key"), resp.GetKeys()[0].GetKey())
+}
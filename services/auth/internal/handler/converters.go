@@ -51,3 +51,35 @@ func toProtoGetResponse(user models.User) *authv1.GetResponse {
 		UpdatedAt: timestamppb.New(user.UpdatedAt),
 	}
 }
+
+func toProtoLoginResponse(pair models.TokenPair) *authv1.LoginResponse {
+	return &authv1.LoginResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    timestamppb.New(pair.RefreshExpiresAt),
+	}
+}
+
+func toProtoRefreshTokenResponse(pair models.TokenPair) *authv1.RefreshTokenResponse {
+	return &authv1.RefreshTokenResponse{
+		AccessToken:  pair.AccessToken,
+		RefreshToken: pair.RefreshToken,
+		ExpiresAt:    timestamppb.New(pair.RefreshExpiresAt),
+	}
+}
+
+func toProtoPublicKeysResponse(keys []models.PublicKey) *authv1.GetPublicKeysResponse {
+	resp := &authv1.GetPublicKeysResponse{
+		Keys: make([]*authv1.PublicKey, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, &authv1.PublicKey{
+			KeyId:     key.KeyID,
+			Algorithm: key.Algorithm,
+			Key:       key.Key,
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auth.go
+//
+// Generated by this command:
+//
+//	mockgen -source=auth.go -destination=mocks/mock_auth_service.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockauthService is a mock of authService interface.
+type MockauthService struct {
+	ctrl     *gomock.Controller
+	recorder *MockauthServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockauthServiceMockRecorder is the mock recorder for MockauthService.
+type MockauthServiceMockRecorder struct {
+	mock *MockauthService
+}
+
+// NewMockauthService creates a new mock instance.
+func NewMockauthService(ctrl *gomock.Controller) *MockauthService {
+	mock := &MockauthService{ctrl: ctrl}
+	mock.recorder = &MockauthServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockauthService) EXPECT() *MockauthServiceMockRecorder {
+	return m.recorder
+}
+
+// Login mocks base method.
+func (m *MockauthService) Login(ctx context.Context, email, password string) (models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Login", ctx, email, password)
+	ret0, _ := ret[0].(models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Login indicates an expected call of Login.
+func (mr *MockauthServiceMockRecorder) Login(ctx, email, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockauthService)(nil).Login), ctx, email, password)
+}
+
+// PublicKeys mocks base method.
+func (m *MockauthService) PublicKeys(ctx context.Context) []models.PublicKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKeys", ctx)
+	ret0, _ := ret[0].([]models.PublicKey)
+	return ret0
+}
+
+// PublicKeys indicates an expected call of PublicKeys.
+func (mr *MockauthServiceMockRecorder) PublicKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKeys", reflect.TypeOf((*MockauthService)(nil).PublicKeys), ctx)
+}
+
+// Refresh mocks base method.
+func (m *MockauthService) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", ctx, refreshToken)
+	ret0, _ := ret[0].(models.TokenPair)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockauthServiceMockRecorder) Refresh(ctx, refreshToken any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockauthService)(nil).Refresh), ctx, refreshToken)
+}
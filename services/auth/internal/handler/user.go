@@ -11,6 +11,7 @@ import (
 	authv1 "github.com/BeInBloom/grpc-chat/gen/go/auth/v1"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/repository"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/services"
 )
 
 //go:generate mockgen -source=user.go -destination=mocks/mock_service.go -package=mocks
@@ -19,6 +20,7 @@ type userService interface {
 	Create(ctx context.Context, user models.User) (uuid.UUID, error)
 	Get(ctx context.Context, id uuid.UUID) (models.User, error)
 	Update(ctx context.Context, user models.User) error
+	UpdatePassword(ctx context.Context, id uuid.UUID, oldPassword, newPassword string) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
@@ -67,6 +69,26 @@ func (h *UserHandler) Update(ctx context.Context, req *authv1.UpdateRequest) (*a
 	return &authv1.UpdateResponse{}, nil
 }
 
+func (h *UserHandler) UpdatePassword(
+	ctx context.Context,
+	req *authv1.UpdatePasswordRequest,
+) (*authv1.UpdatePasswordResponse, error) {
+	id, err := toUserID(req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetNewPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "new_password is required")
+	}
+
+	if err := h.service.UpdatePassword(ctx, id, req.GetOldPassword(), req.GetNewPassword()); err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &authv1.UpdatePasswordResponse{}, nil
+}
+
 func (h *UserHandler) Delete(ctx context.Context, req *authv1.DeleteRequest) (*authv1.DeleteResponse, error) {
 	id, err := toUserID(req.GetId())
 	if err != nil {
@@ -81,8 +103,15 @@ func (h *UserHandler) Delete(ctx context.Context, req *authv1.DeleteRequest) (*a
 }
 
 func toGRPCError(err error) error {
-	if errors.Is(err, repository.ErrUserNotFound) {
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
 		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, repository.ErrEmailTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, services.ErrInvalidCredentials),
+		errors.Is(err, services.ErrInvalidRefreshToken),
+		errors.Is(err, services.ErrRefreshTokenReused):
+		return status.Error(codes.Unauthenticated, err.Error())
 	}
 
 	return status.Error(codes.Internal, err.Error())
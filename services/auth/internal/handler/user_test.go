@@ -16,6 +16,7 @@ import (
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/handler/mocks"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
 	"github.com/BeInBloom/grpc-chat/services/auth/internal/repository"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/services"
 )
 
 var testUUID = uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
@@ -243,3 +244,78 @@ func TestUserHandler_DeleteNotFound(t *testing.T) {
 	require.True(t, ok)
 	assert.Equal(t, codes.NotFound, st.Code())
 }
+
+func TestUserHandler_UpdatePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockuserService(ctrl)
+	handler := New(mockService)
+
+	ctx := context.Background()
+	req := &authv1.UpdatePasswordRequest{
+		Id:          testUUID.String(),
+		OldPassword: "secret123",
+		NewPassword: "new-secret",
+	}
+
+	mockService.EXPECT().
+		UpdatePassword(ctx, testUUID, "secret123", "new-secret").
+		Return(nil)
+
+	resp, err := handler.UpdatePassword(ctx, req)
+
+	require.NoError(t, err)
+	assert.NotNil(t, resp)
+}
+
+func TestUserHandler_UpdatePasswordWrongOldPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockuserService(ctrl)
+	handler := New(mockService)
+
+	ctx := context.Background()
+	req := &authv1.UpdatePasswordRequest{
+		Id:          testUUID.String(),
+		OldPassword: "wrong",
+		NewPassword: "new-secret",
+	}
+
+	mockService.EXPECT().
+		UpdatePassword(ctx, testUUID, "wrong", "new-secret").
+		Return(services.ErrInvalidCredentials)
+
+	resp, err := handler.UpdatePassword(ctx, req)
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.Unauthenticated, st.Code())
+}
+
+func TestUserHandler_CreateEmailTaken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockuserService(ctrl)
+	handler := New(mockService)
+
+	ctx := context.Background()
+
+	mockService.EXPECT().
+		Create(ctx, gomock.Any()).
+		Return(uuid.Nil, repository.ErrEmailTaken)
+
+	resp, err := handler.Create(ctx, &authv1.CreateRequest{
+		Name:     "test",
+		Email:    "test@example.com",
+		Password: "secret123",
+	})
+
+	assert.Nil(t, resp)
+	st, ok := status.FromError(err)
+	require.True(t, ok)
+	assert.Equal(t, codes.AlreadyExists, st.Code())
+}
@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type (
+	TokenPair struct {
+		AccessToken      string
+		AccessExpiresAt  time.Time
+		RefreshToken     string
+		RefreshExpiresAt time.Time
+	}
+
+	PublicKey struct {
+		KeyID     string
+		Algorithm string
+		Key       []byte
+	}
+
+	RefreshToken struct {
+		ID        uuid.UUID
+		UserID    uuid.UUID
+		FamilyID  uuid.UUID
+		TokenHash string
+		ExpiresAt time.Time
+		CreatedAt time.Time
+		UsedAt    *time.Time
+		RevokedAt *time.Time
+	}
+)
@@ -8,12 +8,13 @@ import (
 
 type (
 	User struct {
-		ID        uuid.UUID `validate:"-"`
-		Name      string    `validate:"required"`
-		Email     string    `validate:"required,email"`
-		Password  string    `validate:"required"`
-		Role      int32     `validate:"-"`
-		CreatedAt time.Time `validate:"-"`
-		UpdatedAt time.Time `validate:"-"`
+		ID           uuid.UUID `validate:"-"`
+		Name         string    `validate:"required"`
+		Email        string    `validate:"required,email"`
+		Password     string    `validate:"required_without=PasswordHash"`
+		PasswordHash string    `validate:"-"`
+		Role         int32     `validate:"-"`
+		CreatedAt    time.Time `validate:"-"`
+		UpdatedAt    time.Time `validate:"-"`
 	}
 )
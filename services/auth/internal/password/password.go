@@ -0,0 +1,54 @@
+package password
+
+import (
+	"errors"
+)
+
+var ErrUnknownScheme = errors.New("unknown password hash scheme")
+
+// Scheme is a single password hashing algorithm with a self-describing
+// encoding, so hashes produced with older parameters can still be verified.
+type Scheme interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	NeedsRehash(encoded string) bool
+	Recognizes(encoded string) bool
+}
+
+// Hasher hashes new passwords with the preferred scheme and verifies
+// existing hashes with whichever scheme produced them.
+type Hasher struct {
+	preferred Scheme
+	schemes   []Scheme
+}
+
+func New(preferred Scheme, legacy ...Scheme) *Hasher {
+	return &Hasher{
+		preferred: preferred,
+		schemes:   append([]Scheme{preferred}, legacy...),
+	}
+}
+
+func (h *Hasher) Hash(password string) (string, error) {
+	return h.preferred.Hash(password)
+}
+
+func (h *Hasher) Verify(password, encoded string) (bool, error) {
+	for _, s := range h.schemes {
+		if s.Recognizes(encoded) {
+			return s.Verify(password, encoded)
+		}
+	}
+
+	return false, ErrUnknownScheme
+}
+
+// NeedsRehash reports whether the hash was produced by a legacy scheme or
+// with parameters weaker than the preferred ones.
+func (h *Hasher) NeedsRehash(encoded string) bool {
+	if !h.preferred.Recognizes(encoded) {
+		return true
+	}
+
+	return h.preferred.NeedsRehash(encoded)
+}
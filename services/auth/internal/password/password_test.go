@@ -0,0 +1,87 @@
+package password
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testArgon2idParams = Argon2idParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestHasher_Argon2id(t *testing.T) {
+	hasher := New(NewArgon2id(testArgon2idParams))
+
+	encoded, err := hasher.Hash("secret123")
+	require.NoError(t, err)
+	assert.Contains(t, encoded, "$argon2id$v=19$m=1024,t=1,p=1$")
+	assert.NotContains(t, encoded, "secret123")
+
+	ok, err := hasher.Verify("secret123", encoded)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = hasher.Verify("wrong", encoded)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.False(t, hasher.NeedsRehash(encoded))
+}
+
+func TestHasher_SaltIsRandom(t *testing.T) {
+	hasher := New(NewArgon2id(testArgon2idParams))
+
+	first, err := hasher.Hash("secret123")
+	require.NoError(t, err)
+	second, err := hasher.Hash("secret123")
+	require.NoError(t, err)
+
+	assert.NotEqual(t, first, second)
+}
+
+func TestHasher_NeedsRehashOnStrongerParams(t *testing.T) {
+	weak := New(NewArgon2id(testArgon2idParams))
+	encoded, err := weak.Hash("secret123")
+	require.NoError(t, err)
+
+	stronger := testArgon2idParams
+	stronger.Iterations = 2
+	hasher := New(NewArgon2id(stronger))
+
+	ok, err := hasher.Verify("secret123", encoded)
+	require.NoError(t, err)
+	assert.True(t, ok, "hashes with old params must still verify")
+	assert.True(t, hasher.NeedsRehash(encoded))
+}
+
+func TestHasher_LegacyBcrypt(t *testing.T) {
+	legacy, err := NewBcrypt(bcrypt.MinCost).Hash("secret123")
+	require.NoError(t, err)
+
+	hasher := New(NewArgon2id(testArgon2idParams), NewBcrypt(bcrypt.MinCost))
+
+	ok, err := hasher.Verify("secret123", legacy)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, hasher.NeedsRehash(legacy))
+
+	ok, err = hasher.Verify("wrong", legacy)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestHasher_UnknownScheme(t *testing.T) {
+	hasher := New(NewArgon2id(testArgon2idParams))
+
+	_, err := hasher.Verify("secret123", "secret123")
+
+	assert.ErrorIs(t, err, ErrUnknownScheme)
+	assert.True(t, hasher.NeedsRehash("secret123"))
+}
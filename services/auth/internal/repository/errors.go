@@ -3,5 +3,9 @@ package repository
 import "errors"
 
 var (
-	ErrUserNotFound = errors.New("user not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrEmailTaken           = errors.New("email already taken")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenUsed     = errors.New("refresh token already used")
+	ErrRefreshTokenRevoked  = errors.New("refresh token revoked")
 )
@@ -0,0 +1,11 @@
+CREATE TABLE users (
+    id            UUID PRIMARY KEY,
+    name          TEXT        NOT NULL,
+    email         TEXT        NOT NULL,
+    password_hash TEXT        NOT NULL,
+    role          INTEGER     NOT NULL DEFAULT 0,
+    created_at    TIMESTAMPTZ NOT NULL,
+    updated_at    TIMESTAMPTZ NOT NULL
+);
+
+CREATE UNIQUE INDEX users_email_key ON users (lower(email));
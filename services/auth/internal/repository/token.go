@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+)
+
+type TokenRepository struct {
+	tokens map[string]*models.RefreshToken
+	mu     sync.Mutex
+}
+
+func NewTokenRepository() *TokenRepository {
+	return &TokenRepository{
+		tokens: make(map[string]*models.RefreshToken),
+	}
+}
+
+func (r *TokenRepository) Create(ctx context.Context, token models.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token.CreatedAt = time.Now()
+	r.tokens[token.TokenHash] = &token
+
+	return nil
+}
+
+// Use atomically marks the token as used. A token that was already used is
+// returned together with ErrRefreshTokenUsed so the caller can revoke its family.
+func (r *TokenRepository) Use(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.tokens[tokenHash]
+	if !ok {
+		return models.RefreshToken{}, ErrRefreshTokenNotFound
+	}
+
+	if token.RevokedAt != nil {
+		return *token, ErrRefreshTokenRevoked
+	}
+	if token.UsedAt != nil {
+		return *token, ErrRefreshTokenUsed
+	}
+
+	now := time.Now()
+	token.UsedAt = &now
+
+	return *token, nil
+}
+
+func (r *TokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range r.tokens {
+		if token.FamilyID == familyID && token.RevokedAt == nil {
+			token.RevokedAt = &now
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+)
+
+const tokenColumns = "id, user_id, family_id, token_hash, expires_at, created_at, used_at, revoked_at"
+
+type PostgresTokenRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresTokenRepository(pool *pgxpool.Pool) *PostgresTokenRepository {
+	return &PostgresTokenRepository{pool: pool}
+}
+
+func (r *PostgresTokenRepository) Create(ctx context.Context, token models.RefreshToken) error {
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)`,
+		token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("insert refresh token: %w", err)
+	}
+
+	return nil
+}
+
+// Use marks the token as used with a conditional update, so two concurrent
+// refreshes with the same token cannot both succeed.
+func (r *PostgresTokenRepository) Use(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	rows, err := r.pool.Query(ctx, `
+		UPDATE refresh_tokens
+		SET used_at = $2
+		WHERE token_hash = $1 AND used_at IS NULL AND revoked_at IS NULL
+		RETURNING `+tokenColumns,
+		tokenHash, time.Now(),
+	)
+	if err != nil {
+		return models.RefreshToken{}, fmt.Errorf("use refresh token: %w", err)
+	}
+
+	token, err := collectToken(rows)
+	if !errors.Is(err, ErrRefreshTokenNotFound) {
+		return token, err
+	}
+
+	rows, err = r.pool.Query(ctx, "SELECT "+tokenColumns+" FROM refresh_tokens WHERE token_hash = $1", tokenHash)
+	if err != nil {
+		return models.RefreshToken{}, fmt.Errorf("select refresh token: %w", err)
+	}
+
+	token, err = collectToken(rows)
+	if err != nil {
+		return models.RefreshToken{}, err
+	}
+
+	if token.RevokedAt != nil {
+		return token, ErrRefreshTokenRevoked
+	}
+
+	return token, ErrRefreshTokenUsed
+}
+
+func (r *PostgresTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = $2
+		WHERE family_id = $1 AND revoked_at IS NULL`,
+		familyID, time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("revoke refresh token family: %w", err)
+	}
+
+	return nil
+}
+
+func collectToken(rows pgx.Rows) (models.RefreshToken, error) {
+	token, err := pgx.CollectExactlyOneRow(rows, func(row pgx.CollectableRow) (models.RefreshToken, error) {
+		var t models.RefreshToken
+		err := row.Scan(&t.ID, &t.UserID, &t.FamilyID, &t.TokenHash, &t.ExpiresAt, &t.CreatedAt, &t.UsedAt, &t.RevokedAt)
+		return t, err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.RefreshToken{}, ErrRefreshTokenNotFound
+		}
+		return models.RefreshToken{}, fmt.Errorf("scan refresh token: %w", err)
+	}
+
+	return token, nil
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+)
+
+func TestTokenRepository_Use(t *testing.T) {
+	repo := NewTokenRepository()
+	ctx := context.Background()
+	familyID := uuid.New()
+
+	require.NoError(t, repo.Create(ctx, models.RefreshToken{
+		ID:        uuid.New(),
+		FamilyID:  familyID,
+		TokenHash: "hash",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}))
+
+	token, err := repo.Use(ctx, "hash")
+	require.NoError(t, err)
+	assert.Equal(t, familyID, token.FamilyID)
+	assert.NotNil(t, token.UsedAt)
+
+	token, err = repo.Use(ctx, "hash")
+	assert.ErrorIs(t, err, ErrRefreshTokenUsed)
+	assert.Equal(t, familyID, token.FamilyID)
+}
+
+func TestTokenRepository_UseNotFound(t *testing.T) {
+	repo := NewTokenRepository()
+
+	_, err := repo.Use(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenNotFound)
+}
+
+func TestTokenRepository_RevokeFamily(t *testing.T) {
+	repo := NewTokenRepository()
+	ctx := context.Background()
+	familyID := uuid.New()
+
+	require.NoError(t, repo.Create(ctx, models.RefreshToken{ID: uuid.New(), FamilyID: familyID, TokenHash: "first"}))
+	require.NoError(t, repo.Create(ctx, models.RefreshToken{ID: uuid.New(), FamilyID: familyID, TokenHash: "second"}))
+	require.NoError(t, repo.Create(ctx, models.RefreshToken{ID: uuid.New(), FamilyID: uuid.New(), TokenHash: "other"}))
+
+	require.NoError(t, repo.RevokeFamily(ctx, familyID))
+
+	_, err := repo.Use(ctx, "first")
+	assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+	_, err = repo.Use(ctx, "second")
+	assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+	_, err = repo.Use(ctx, "other")
+	assert.NoError(t, err)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"sync"
 	"time"
 
@@ -25,6 +26,10 @@ func (r *UserRepository) Create(ctx context.Context, user models.User) (uuid.UUI
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if r.emailTaken(user.Email, uuid.Nil) {
+		return uuid.Nil, ErrEmailTaken
+	}
+
 	user.ID = uuid.New()
 	now := time.Now()
 	user.CreatedAt = now
@@ -47,6 +52,19 @@ func (r *UserRepository) Get(ctx context.Context, id uuid.UUID) (models.User, er
 	return *user, nil
 }
 
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			return *user, nil
+		}
+	}
+
+	return models.User{}, ErrUserNotFound
+}
+
 func (r *UserRepository) Update(ctx context.Context, user models.User) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -56,6 +74,10 @@ func (r *UserRepository) Update(ctx context.Context, user models.User) error {
 		return ErrUserNotFound
 	}
 
+	if r.emailTaken(user.Email, user.ID) {
+		return ErrEmailTaken
+	}
+
 	user.UpdatedAt = time.Now()
 	r.users[user.ID] = &user
 
@@ -74,3 +96,13 @@ func (r *UserRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+func (r *UserRepository) emailTaken(email string, except uuid.UUID) bool {
+	for id, user := range r.users {
+		if id != except && strings.EqualFold(user.Email, email) {
+			return true
+		}
+	}
+
+	return false
+}
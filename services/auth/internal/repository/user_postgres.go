@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+)
+
+const usersEmailKey = "users_email_key"
+
+const userColumns = "id, name, email, password_hash, role, created_at, updated_at"
+
+type PostgresUserRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgres(pool *pgxpool.Pool) *PostgresUserRepository {
+	return &PostgresUserRepository{pool: pool}
+}
+
+func (r *PostgresUserRepository) Create(ctx context.Context, user models.User) (uuid.UUID, error) {
+	user.ID = uuid.New()
+	now := time.Now()
+
+	_, err := r.pool.Exec(ctx, `
+		INSERT INTO users (id, name, email, password_hash, role, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)`,
+		user.ID, user.Name, user.Email, user.PasswordHash, user.Role, now,
+	)
+	if err != nil {
+		if postgres.IsUniqueViolation(err, usersEmailKey) {
+			return uuid.Nil, ErrEmailTaken
+		}
+		return uuid.Nil, fmt.Errorf("insert user: %w", err)
+	}
+
+	return user.ID, nil
+}
+
+func (r *PostgresUserRepository) Get(ctx context.Context, id uuid.UUID) (models.User, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", id)
+	if err != nil {
+		return models.User{}, fmt.Errorf("select user: %w", err)
+	}
+
+	return collectUser(rows)
+}
+
+func (r *PostgresUserRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	rows, err := r.pool.Query(ctx, "SELECT "+userColumns+" FROM users WHERE lower(email) = lower($1)", email)
+	if err != nil {
+		return models.User{}, fmt.Errorf("select user by email: %w", err)
+	}
+
+	return collectUser(rows)
+}
+
+func (r *PostgresUserRepository) Update(ctx context.Context, user models.User) error {
+	tag, err := r.pool.Exec(ctx, `
+		UPDATE users
+		SET name = $2, email = $3, password_hash = $4, role = $5, updated_at = $6
+		WHERE id = $1`,
+		user.ID, user.Name, user.Email, user.PasswordHash, user.Role, time.Now(),
+	)
+	if err != nil {
+		if postgres.IsUniqueViolation(err, usersEmailKey) {
+			return ErrEmailTaken
+		}
+		return fmt.Errorf("update user: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func (r *PostgresUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM users WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("delete user: %w", err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		return ErrUserNotFound
+	}
+
+	return nil
+}
+
+func collectUser(rows pgx.Rows) (models.User, error) {
+	user, err := pgx.CollectExactlyOneRow(rows, func(row pgx.CollectableRow) (models.User, error) {
+		var u models.User
+		err := row.Scan(&u.ID, &u.Name, &u.Email, &u.PasswordHash, &u.Role, &u.CreatedAt, &u.UpdatedAt)
+		return u, err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.User{}, ErrUserNotFound
+		}
+		return models.User{}, fmt.Errorf("scan user: %w", err)
+	}
+
+	return user, nil
+}
@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+)
+
+// newTestPool connects to POSTGRES_TEST_DSN and skips the test when it is not set.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := postgres.NewPool(ctx, postgres.Config{DSN: dsn})
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	require.NoError(t, postgres.Migrate(ctx, pool, Migrations, MigrationsDir))
+	_, err = pool.Exec(ctx, "TRUNCATE users, refresh_tokens")
+	require.NoError(t, err)
+
+	return pool
+}
+
+func TestPostgresUserRepository_CreateGet(t *testing.T) {
+	repo := NewPostgres(newTestPool(t))
+	ctx := context.Background()
+
+	userID, err := repo.Create(ctx, models.User{
+		Name:         "test",
+		Email:        "Test@Example.com",
+		PasswordHash: "hash",
+	})
+	require.NoError(t, err)
+
+	user, err := repo.Get(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, "test", user.Name)
+	assert.Equal(t, "hash", user.PasswordHash)
+	assert.False(t, user.CreatedAt.IsZero())
+
+	user, err = repo.GetByEmail(ctx, "test@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, userID, user.ID)
+}
+
+func TestPostgresUserRepository_EmailTaken(t *testing.T) {
+	repo := NewPostgres(newTestPool(t))
+	ctx := context.Background()
+
+	_, err := repo.Create(ctx, models.User{Name: "first", Email: "test@example.com", PasswordHash: "hash"})
+	require.NoError(t, err)
+
+	_, err = repo.Create(ctx, models.User{Name: "second", Email: "TEST@example.com", PasswordHash: "hash"})
+	assert.ErrorIs(t, err, ErrEmailTaken)
+}
+
+func TestPostgresUserRepository_NotFound(t *testing.T) {
+	repo := NewPostgres(newTestPool(t))
+	ctx := context.Background()
+
+	_, err := repo.Get(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	err = repo.Update(ctx, models.User{ID: uuid.New(), Name: "test", Email: "test@example.com"})
+	assert.ErrorIs(t, err, ErrUserNotFound)
+
+	err = repo.Delete(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestPostgresTokenRepository_UseAndRevoke(t *testing.T) {
+	pool := newTestPool(t)
+	users := NewPostgres(pool)
+	tokens := NewPostgresTokenRepository(pool)
+	ctx := context.Background()
+
+	userID, err := users.Create(ctx, models.User{Name: "test", Email: "test@example.com", PasswordHash: "hash"})
+	require.NoError(t, err)
+
+	familyID := uuid.New()
+	require.NoError(t, tokens.Create(ctx, models.RefreshToken{ID: uuid.New(), UserID: userID, FamilyID: familyID, TokenHash: "first"}))
+	require.NoError(t, tokens.Create(ctx, models.RefreshToken{ID: uuid.New(), UserID: userID, FamilyID: familyID, TokenHash: "second"}))
+
+	_, err = tokens.Use(ctx, "first")
+	require.NoError(t, err)
+
+	token, err := tokens.Use(ctx, "first")
+	assert.ErrorIs(t, err, ErrRefreshTokenUsed)
+	assert.Equal(t, familyID, token.FamilyID)
+
+	require.NoError(t, tokens.RevokeFamily(ctx, familyID))
+
+	_, err = tokens.Use(ctx, "second")
+	assert.ErrorIs(t, err, ErrRefreshTokenRevoked)
+
+	_, err = tokens.Use(ctx, "missing")
+	assert.ErrorIs(t, err, ErrRefreshTokenNotFound)
+}
@@ -128,3 +128,20 @@ func TestUserRepository_DeleteNotFound(t *testing.T) {
 
 	assert.ErrorIs(t, err, ErrUserNotFound)
 }
+
+func TestUserRepository_EmailTaken(t *testing.T) {
+	repo := newTestRepo()
+	ctx := context.Background()
+
+	_, err := repo.Create(ctx, models.User{Name: "first", Email: "test@example.com", Password: "secret123"})
+	require.NoError(t, err)
+
+	secondID, err := repo.Create(ctx, models.User{Name: "second", Email: "other@example.com", Password: "secret123"})
+	require.NoError(t, err)
+
+	_, err = repo.Create(ctx, models.User{Name: "third", Email: "TEST@example.com", Password: "secret123"})
+	assert.ErrorIs(t, err, ErrEmailTaken)
+
+	err = repo.Update(ctx, models.User{ID: secondID, Name: "second", Email: "test@example.com"})
+	assert.ErrorIs(t, err, ErrEmailTaken)
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/pkg/authtoken"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/repository"
+)
+
+//go:generate mockgen -source=auth.go -destination=mocks/mock_auth_repository.go -package=mocks
+
+const (
+	refreshTokenBytes = 32
+	// dummyPassword is hashed once to have a hash to verify against when the
+	// email is unknown.
+	dummyPassword = "dummy password for unknown emails"
+)
+
+type credentialsRepository interface {
+	Get(ctx context.Context, id uuid.UUID) (models.User, error)
+	GetByEmail(ctx context.Context, email string) (models.User, error)
+	Update(ctx context.Context, user models.User) error
+}
+
+type tokenRepository interface {
+	Create(ctx context.Context, token models.RefreshToken) error
+	Use(ctx context.Context, tokenHash string) (models.RefreshToken, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+}
+
+type accessTokenSigner interface {
+	Sign(userID uuid.UUID, role int32, sessionID uuid.UUID) (string, time.Time, error)
+	PublicKey() ed25519.PublicKey
+	KeyID() string
+}
+
+type AuthService struct {
+	users      credentialsRepository
+	tokens     tokenRepository
+	signer     accessTokenSigner
+	hasher     passwordHasher
+	dummyHash  func() (string, error)
+	refreshTTL time.Duration
+	now        func() time.Time
+}
+
+func NewAuthService(
+	users credentialsRepository,
+	tokens tokenRepository,
+	signer accessTokenSigner,
+	hasher passwordHasher,
+	refreshTTL time.Duration,
+) *AuthService {
+	return &AuthService{
+		users:      users,
+		tokens:     tokens,
+		signer:     signer,
+		hasher:     hasher,
+		dummyHash:  sync.OnceValues(func() (string, error) { return hasher.Hash(dummyPassword) }),
+		refreshTTL: refreshTTL,
+		now:        time.Now,
+	}
+}
+
+func (s *AuthService) Login(ctx context.Context, email, password string) (models.TokenPair, error) {
+	user, err := s.users.GetByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			s.verifyDummy(password)
+			return models.TokenPair{}, ErrInvalidCredentials
+		}
+		return models.TokenPair{}, fmt.Errorf("get user by email: %w", err)
+	}
+
+	ok, err := s.hasher.Verify(password, user.PasswordHash)
+	if err != nil {
+		return models.TokenPair{}, fmt.Errorf("verify password: %w", err)
+	}
+	if !ok {
+		return models.TokenPair{}, ErrInvalidCredentials
+	}
+
+	if s.hasher.NeedsRehash(user.PasswordHash) {
+		if err := s.rehash(ctx, user, password); err != nil {
+			return models.TokenPair{}, err
+		}
+	}
+
+	// Every login starts a new token family; the family ID doubles as the
+	// session ID carried in access tokens.
+	return s.issue(ctx, user, uuid.New())
+}
+
+// verifyDummy verifies the password against a hash of no user, so that a
+// login with an unknown email takes as long as one with a wrong password.
+func (s *AuthService) verifyDummy(password string) {
+	hash, err := s.dummyHash()
+	if err != nil {
+		return
+	}
+	_, _ = s.hasher.Verify(password, hash)
+}
+
+func (s *AuthService) Refresh(ctx context.Context, refreshToken string) (models.TokenPair, error) {
+	token, err := s.tokens.Use(ctx, hashRefreshToken(refreshToken))
+	switch {
+	case errors.Is(err, repository.ErrRefreshTokenUsed):
+		if err := s.tokens.RevokeFamily(ctx, token.FamilyID); err != nil {
+			return models.TokenPair{}, fmt.Errorf("revoke token family: %w", err)
+		}
+		return models.TokenPair{}, ErrRefreshTokenReused
+	case errors.Is(err, repository.ErrRefreshTokenNotFound),
+		errors.Is(err, repository.ErrRefreshTokenRevoked):
+		return models.TokenPair{}, ErrInvalidRefreshToken
+	case err != nil:
+		return models.TokenPair{}, fmt.Errorf("use refresh token: %w", err)
+	}
+
+	if !s.now().Before(token.ExpiresAt) {
+		return models.TokenPair{}, ErrInvalidRefreshToken
+	}
+
+	user, err := s.users.Get(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return models.TokenPair{}, ErrInvalidRefreshToken
+		}
+		return models.TokenPair{}, fmt.Errorf("get user for refresh: %w", err)
+	}
+
+	return s.issue(ctx, user, token.FamilyID)
+}
+
+func (s *AuthService) PublicKeys(ctx context.Context) []models.PublicKey {
+	return []models.PublicKey{{
+		KeyID:     s.signer.KeyID(),
+		Algorithm: authtoken.Algorithm,
+		Key:       s.signer.PublicKey(),
+	}}
+}
+
+// rehash upgrades a hash produced by a legacy scheme or weaker parameters
+// while the plaintext is at hand.
+func (s *AuthService) rehash(ctx context.Context, user models.User, password string) error {
+	hash, err := s.hasher.Hash(password)
+	if err != nil {
+		return fmt.Errorf("rehash password: %w", err)
+	}
+	user.PasswordHash = hash
+
+	if err := s.users.Update(ctx, user); err != nil {
+		return fmt.Errorf("store rehashed password: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AuthService) issue(ctx context.Context, user models.User, familyID uuid.UUID) (models.TokenPair, error) {
+	accessToken, accessExpiresAt, err := s.signer.Sign(user.ID, user.Role, familyID)
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	refreshToken, err := newRefreshToken()
+	if err != nil {
+		return models.TokenPair{}, err
+	}
+
+	refreshExpiresAt := s.now().Add(s.refreshTTL)
+	if err := s.tokens.Create(ctx, models.RefreshToken{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: hashRefreshToken(refreshToken),
+		ExpiresAt: refreshExpiresAt,
+	}); err != nil {
+		return models.TokenPair{}, fmt.Errorf("store refresh token: %w", err)
+	}
+
+	return models.TokenPair{
+		AccessToken:      accessToken,
+		AccessExpiresAt:  accessExpiresAt,
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: refreshExpiresAt,
+	}, nil
+}
+
+func newRefreshToken() (string, error) {
+	buf := make([]byte, refreshTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate refresh token: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/repository"
+	"github.com/BeInBloom/grpc-chat/services/auth/internal/services/mocks"
+)
+
+var testFamilyID = uuid.MustParse("7d444840-9dc0-11d1-b245-5ffdce74fad2")
+
+type authServiceMocks struct {
+	users  *mocks.MockcredentialsRepository
+	tokens *mocks.MocktokenRepository
+	signer *mocks.MockaccessTokenSigner
+	hasher *mocks.MockpasswordHasher
+}
+
+func newTestAuthService(t *testing.T) (*AuthService, authServiceMocks) {
+	ctrl := gomock.NewController(t)
+
+	m := authServiceMocks{
+		users:  mocks.NewMockcredentialsRepository(ctrl),
+		tokens: mocks.NewMocktokenRepository(ctrl),
+		signer: mocks.NewMockaccessTokenSigner(ctrl),
+		hasher: mocks.NewMockpasswordHasher(ctrl),
+	}
+
+	return NewAuthService(m.users, m.tokens, m.signer, m.hasher, time.Hour), m
+}
+
+func TestAuthService_Login(t *testing.T) {
+	service, m := newTestAuthService(t)
+	ctx := context.Background()
+	accessExpiresAt := time.Now().Add(time.Minute)
+
+	m.users.EXPECT().
+		GetByEmail(ctx, "test@example.com").
+		Return(models.User{ID: testUUID, PasswordHash: "hash", Role: 1}, nil)
+	m.hasher.EXPECT().
+		Verify("secret123", "hash").
+		Return(true, nil)
+	m.hasher.EXPECT().
+		NeedsRehash("hash").
+		Return(false)
+
+	var sessionID uuid.UUID
+	m.signer.EXPECT().
+		Sign(testUUID, int32(1), gomock.Any()).
+		DoAndReturn(func(_ uuid.UUID, _ int32, sid uuid.UUID) (string, time.Time, error) {
+			sessionID = sid
+			return "access", accessExpiresAt, nil
+		})
+
+	var stored models.RefreshToken
+	m.tokens.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token models.RefreshToken) error {
+			stored = token
+			return nil
+		})
+
+	pair, err := service.Login(ctx, "test@example.com", "secret123")
+
+	require.NoError(t, err)
+	assert.Equal(t, "access", pair.AccessToken)
+	assert.Equal(t, accessExpiresAt, pair.AccessExpiresAt)
+	assert.NotEmpty(t, pair.RefreshToken)
+	assert.Equal(t, testUUID, stored.UserID)
+	assert.Equal(t, sessionID, stored.FamilyID)
+	assert.Equal(t, hashRefreshToken(pair.RefreshToken), stored.TokenHash)
+	assert.NotEqual(t, pair.RefreshToken, stored.TokenHash, "refresh token must not be stored in plaintext")
+	assert.Equal(t, stored.ExpiresAt, pair.RefreshExpiresAt)
+}
+
+func TestAuthService_LoginWrongPassword(t *testing.T) {
+	service, m := newTestAuthService(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().
+		GetByEmail(ctx, "test@example.com").
+		Return(models.User{ID: testUUID, PasswordHash: "hash"}, nil)
+	m.hasher.EXPECT().
+		Verify("wrong", "hash").
+		Return(false, nil)
+
+	_, err := service.Login(ctx, "test@example.com", "wrong")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
+
+func TestAuthService_LoginUpgradesHash(t *testing.T) {
+	service, m := newTestAuthService(t)
+	ctx := context.Background()
+	user := models.User{ID: testUUID, PasswordHash: "legacy-hash"}
+
+	m.users.EXPECT().
+		GetByEmail(ctx, "test@example.com").
+		Return(user, nil)
+	m.hasher.EXPECT().
+		Verify("secret123", "legacy-hash").
+		Return(true, nil)
+	m.hasher.EXPECT().
+		NeedsRehash("legacy-hash").
+		Return(true)
+	m.hasher.EXPECT().
+		Hash("secret123").
+		Return("new-hash", nil)
+
+	user.PasswordHash = "new-hash"
+	m.users.EXPECT().
+		Update(ctx, user).
+		Return(nil)
+	m.signer.EXPECT().
+		Sign(testUUID, int32(0), gomock.Any()).
+		Return("access", time.Now().Add(time.Minute), nil)
+	m.tokens.EXPECT().
+		Create(ctx, gomock.Any()).
+		Return(nil)
+
+	_, err := service.Login(ctx, "test@example.com", "secret123")
+
+	require.NoError(t, err)
+}
+
+func TestAuthService_LoginUnknownEmail(t *testing.T) {
+	service, m := newTestAuthService(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().
+		GetByEmail(ctx, "missing@example.com").
+		Return(models.User{}, repository.ErrUserNotFound).
+		Times(2)
+	m.hasher.EXPECT().Hash(dummyPassword).Return("dummy-hash", nil)
+	m.hasher.EXPECT().Verify("secret123", "dummy-hash").Return(false, nil).Times(2)
+
+	_, err := service.Login(ctx, "missing@example.com", "secret123")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+
+	_, err = service.Login(ctx, "missing@example.com", "secret123")
+	assert.ErrorIs(t, err, ErrInvalidCredentials, "the password is verified against the same dummy hash every time")
+}
+
+func TestAuthService_Refresh(t *testing.T) {
+	service, m := newTestAuthService(t)
+	ctx := context.Background()
+
+	m.tokens.EXPECT().
+		Use(ctx, hashRefreshToken("old-refresh")).
+		Return(models.RefreshToken{
+			UserID:    testUUID,
+			FamilyID:  testFamilyID,
+			ExpiresAt: time.Now().Add(time.Hour),
+		}, nil)
+	m.users.EXPECT().
+		Get(ctx, testUUID).
+		Return(models.User{ID: testUUID, Role: 2}, nil)
+	m.signer.EXPECT().
+		Sign(testUUID, int32(2), testFamilyID).
+		Return("access", time.Now().Add(time.Minute), nil)
+	m.tokens.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, token models.RefreshToken) error {
+			assert.Equal(t, testFamilyID, token.FamilyID)
+			return nil
+		})
+
+	pair, err := service.Refresh(ctx, "old-refresh")
+
+	require.NoError(t, err)
+	assert.Equal(t, "access", pair.AccessToken)
+	assert.NotEqual(t, "old-refresh", pair.RefreshToken)
+}
+
+func TestAuthService_RefreshReuseRevokesFamily(t *testing.T) {
+	service, m := newTestAuthService(t)
+	ctx := context.Background()
+
+	m.tokens.EXPECT().
+		Use(ctx, hashRefreshToken("stolen")).
+		Return(models.RefreshToken{UserID: testUUID, FamilyID: testFamilyID}, repository.ErrRefreshTokenUsed)
+	m.tokens.EXPECT().
+		RevokeFamily(ctx, testFamilyID).
+		Return(nil)
+
+	_, err := service.Refresh(ctx, "stolen")
+
+	assert.ErrorIs(t, err, ErrRefreshTokenReused)
+}
+
+func TestAuthService_RefreshExpired(t *testing.T) {
+	service, m := newTestAuthService(t)
+	ctx := context.Background()
+
+	m.tokens.EXPECT().
+		Use(ctx, gomock.Any()).
+		Return(models.RefreshToken{
+			UserID:    testUUID,
+			FamilyID:  testFamilyID,
+			ExpiresAt: time.Now().Add(-time.Second),
+		}, nil)
+
+	_, err := service.Refresh(ctx, "expired")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
+
+func TestAuthService_RefreshUnknown(t *testing.T) {
+	service, m := newTestAuthService(t)
+	ctx := context.Background()
+
+	m.tokens.EXPECT().
+		Use(ctx, gomock.Any()).
+		Return(models.RefreshToken{}, repository.ErrRefreshTokenNotFound)
+
+	_, err := service.Refresh(ctx, "unknown")
+
+	assert.ErrorIs(t, err, ErrInvalidRefreshToken)
+}
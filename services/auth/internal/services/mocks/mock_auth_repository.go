@@ -0,0 +1,224 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: auth.go
+//
+// Generated by this command:
+//
+//	mockgen -source=auth.go -destination=mocks/mock_auth_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	ed25519 "crypto/ed25519"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/BeInBloom/grpc-chat/services/auth/internal/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockcredentialsRepository is a mock of credentialsRepository interface.
+type MockcredentialsRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockcredentialsRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockcredentialsRepositoryMockRecorder is the mock recorder for MockcredentialsRepository.
+type MockcredentialsRepositoryMockRecorder struct {
+	mock *MockcredentialsRepository
+}
+
+// NewMockcredentialsRepository creates a new mock instance.
+func NewMockcredentialsRepository(ctrl *gomock.Controller) *MockcredentialsRepository {
+	mock := &MockcredentialsRepository{ctrl: ctrl}
+	mock.recorder = &MockcredentialsRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcredentialsRepository) EXPECT() *MockcredentialsRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockcredentialsRepository) Get(ctx context.Context, id uuid.UUID) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockcredentialsRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockcredentialsRepository)(nil).Get), ctx, id)
+}
+
+// GetByEmail mocks base method.
+func (m *MockcredentialsRepository) GetByEmail(ctx context.Context, email string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByEmail", ctx, email)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByEmail indicates an expected call of GetByEmail.
+func (mr *MockcredentialsRepositoryMockRecorder) GetByEmail(ctx, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByEmail", reflect.TypeOf((*MockcredentialsRepository)(nil).GetByEmail), ctx, email)
+}
+
+// Update mocks base method.
+func (m *MockcredentialsRepository) Update(ctx context.Context, user models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Update", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Update indicates an expected call of Update.
+func (mr *MockcredentialsRepositoryMockRecorder) Update(ctx, user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockcredentialsRepository)(nil).Update), ctx, user)
+}
+
+// MocktokenRepository is a mock of tokenRepository interface.
+type MocktokenRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MocktokenRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MocktokenRepositoryMockRecorder is the mock recorder for MocktokenRepository.
+type MocktokenRepositoryMockRecorder struct {
+	mock *MocktokenRepository
+}
+
+// NewMocktokenRepository creates a new mock instance.
+func NewMocktokenRepository(ctrl *gomock.Controller) *MocktokenRepository {
+	mock := &MocktokenRepository{ctrl: ctrl}
+	mock.recorder = &MocktokenRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktokenRepository) EXPECT() *MocktokenRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MocktokenRepository) Create(ctx context.Context, token models.RefreshToken) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MocktokenRepositoryMockRecorder) Create(ctx, token any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MocktokenRepository)(nil).Create), ctx, token)
+}
+
+// RevokeFamily mocks base method.
+func (m *MocktokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", ctx, familyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MocktokenRepositoryMockRecorder) RevokeFamily(ctx, familyID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MocktokenRepository)(nil).RevokeFamily), ctx, familyID)
+}
+
+// Use mocks base method.
+func (m *MocktokenRepository) Use(ctx context.Context, tokenHash string) (models.RefreshToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Use", ctx, tokenHash)
+	ret0, _ := ret[0].(models.RefreshToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Use indicates an expected call of Use.
+func (mr *MocktokenRepositoryMockRecorder) Use(ctx, tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MocktokenRepository)(nil).Use), ctx, tokenHash)
+}
+
+// MockaccessTokenSigner is a mock of accessTokenSigner interface.
+type MockaccessTokenSigner struct {
+	ctrl     *gomock.Controller
+	recorder *MockaccessTokenSignerMockRecorder
+	isgomock struct{}
+}
+
+// MockaccessTokenSignerMockRecorder is the mock recorder for MockaccessTokenSigner.
+type MockaccessTokenSignerMockRecorder struct {
+	mock *MockaccessTokenSigner
+}
+
+// NewMockaccessTokenSigner creates a new mock instance.
+func NewMockaccessTokenSigner(ctrl *gomock.Controller) *MockaccessTokenSigner {
+	mock := &MockaccessTokenSigner{ctrl: ctrl}
+	mock.recorder = &MockaccessTokenSignerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockaccessTokenSigner) EXPECT() *MockaccessTokenSignerMockRecorder {
+	return m.recorder
+}
+
+// KeyID mocks base method.
+func (m *MockaccessTokenSigner) KeyID() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "KeyID")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// KeyID indicates an expected call of KeyID.
+func (mr *MockaccessTokenSignerMockRecorder) KeyID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "KeyID", reflect.TypeOf((*MockaccessTokenSigner)(nil).KeyID))
+}
+
+// PublicKey mocks base method.
+func (m *MockaccessTokenSigner) PublicKey() ed25519.PublicKey {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublicKey")
+	ret0, _ := ret[0].(ed25519.PublicKey)
+	return ret0
+}
+
+// PublicKey indicates an expected call of PublicKey.
+func (mr *MockaccessTokenSignerMockRecorder) PublicKey() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublicKey", reflect.TypeOf((*MockaccessTokenSigner)(nil).PublicKey))
+}
+
+// Sign mocks base method.
+func (m *MockaccessTokenSigner) Sign(userID uuid.UUID, role int32, sessionID uuid.UUID) (string, time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sign", userID, role, sessionID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Sign indicates an expected call of Sign.
+func (mr *MockaccessTokenSignerMockRecorder) Sign(userID, role, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sign", reflect.TypeOf((*MockaccessTokenSigner)(nil).Sign), userID, role, sessionID)
+}
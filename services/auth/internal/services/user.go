@@ -21,12 +21,19 @@ type userRepository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 }
 
+type passwordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (bool, error)
+	NeedsRehash(encoded string) bool
+}
+
 type UserService struct {
-	repo userRepository
+	repo   userRepository
+	hasher passwordHasher
 }
 
-func New(repo userRepository) *UserService {
-	return &UserService{repo: repo}
+func New(repo userRepository, hasher passwordHasher) *UserService {
+	return &UserService{repo: repo, hasher: hasher}
 }
 
 func (s *UserService) Create(ctx context.Context, user models.User) (uuid.UUID, error) {
@@ -34,6 +41,13 @@ func (s *UserService) Create(ctx context.Context, user models.User) (uuid.UUID,
 		return uuid.Nil, err
 	}
 
+	hash, err := s.hasher.Hash(user.Password)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("hash password: %w", err)
+	}
+	user.PasswordHash = hash
+	user.Password = ""
+
 	return s.repo.Create(ctx, user)
 }
 
@@ -61,6 +75,33 @@ func (s *UserService) Update(ctx context.Context, user models.User) error {
 	return s.repo.Update(ctx, existing)
 }
 
+func (s *UserService) UpdatePassword(ctx context.Context, id uuid.UUID, oldPassword, newPassword string) error {
+	if err := validate.Var(newPassword, "required"); err != nil {
+		return err
+	}
+
+	existing, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return fmt.Errorf("get user for password update: %w", err)
+	}
+
+	ok, err := s.hasher.Verify(oldPassword, existing.PasswordHash)
+	if err != nil {
+		return fmt.Errorf("verify password: %w", err)
+	}
+	if !ok {
+		return ErrInvalidCredentials
+	}
+
+	hash, err := s.hasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	existing.PasswordHash = hash
+
+	return s.repo.Update(ctx, existing)
+}
+
 func (s *UserService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.repo.Delete(ctx, id)
 }
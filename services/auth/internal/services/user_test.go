@@ -20,7 +20,8 @@ func TestUserService_Create(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	mockHasher := mocks.NewMockpasswordHasher(ctrl)
+	service := New(mockRepo, mockHasher)
 
 	ctx := context.Background()
 	user := models.User{
@@ -29,8 +30,16 @@ func TestUserService_Create(t *testing.T) {
 		Password: "secret123",
 	}
 
+	mockHasher.EXPECT().
+		Hash("secret123").
+		Return("hashed", nil)
+
 	mockRepo.EXPECT().
-		Create(ctx, user).
+		Create(ctx, models.User{
+			Name:         "test",
+			Email:        "test@example.com",
+			PasswordHash: "hashed",
+		}).
 		Return(testUUID, nil)
 
 	id, err := service.Create(ctx, user)
@@ -44,7 +53,7 @@ func TestUserService_CreateValidationError(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo, mocks.NewMockpasswordHasher(ctrl))
 
 	ctx := context.Background()
 
@@ -75,7 +84,7 @@ func TestUserService_Get(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo, mocks.NewMockpasswordHasher(ctrl))
 
 	ctx := context.Background()
 	expectedUser := models.User{
@@ -100,7 +109,7 @@ func TestUserService_GetNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo, mocks.NewMockpasswordHasher(ctrl))
 
 	ctx := context.Background()
 	nonExistent := uuid.MustParse("00000000-0000-0000-0000-000000000001")
@@ -119,7 +128,7 @@ func TestUserService_Update(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo, mocks.NewMockpasswordHasher(ctrl))
 
 	ctx := context.Background()
 
@@ -157,7 +166,7 @@ func TestUserService_UpdatePartial(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo, mocks.NewMockpasswordHasher(ctrl))
 
 	ctx := context.Background()
 
@@ -194,7 +203,7 @@ func TestUserService_UpdateNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo, mocks.NewMockpasswordHasher(ctrl))
 
 	ctx := context.Background()
 	nonExistent := uuid.MustParse("00000000-0000-0000-0000-000000000001")
@@ -217,7 +226,7 @@ func TestUserService_Delete(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo, mocks.NewMockpasswordHasher(ctrl))
 
 	ctx := context.Background()
 
@@ -235,7 +244,7 @@ func TestUserService_DeleteNotFound(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockuserRepository(ctrl)
-	service := New(mockRepo)
+	service := New(mockRepo, mocks.NewMockpasswordHasher(ctrl))
 
 	ctx := context.Background()
 	nonExistent := uuid.MustParse("00000000-0000-0000-0000-000000000001")
@@ -248,3 +257,61 @@ func TestUserService_DeleteNotFound(t *testing.T) {
 
 	assert.Error(t, err)
 }
+
+func TestUserService_UpdatePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockuserRepository(ctrl)
+	mockHasher := mocks.NewMockpasswordHasher(ctrl)
+	service := New(mockRepo, mockHasher)
+
+	ctx := context.Background()
+	existingUser := models.User{
+		ID:           testUUID,
+		Name:         "test",
+		Email:        "test@example.com",
+		PasswordHash: "old-hash",
+	}
+
+	mockRepo.EXPECT().
+		Get(ctx, testUUID).
+		Return(existingUser, nil)
+	mockHasher.EXPECT().
+		Verify("secret123", "old-hash").
+		Return(true, nil)
+	mockHasher.EXPECT().
+		Hash("new-secret").
+		Return("new-hash", nil)
+
+	existingUser.PasswordHash = "new-hash"
+	mockRepo.EXPECT().
+		Update(ctx, existingUser).
+		Return(nil)
+
+	err := service.UpdatePassword(ctx, testUUID, "secret123", "new-secret")
+
+	require.NoError(t, err)
+}
+
+func TestUserService_UpdatePasswordWrongOldPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockuserRepository(ctrl)
+	mockHasher := mocks.NewMockpasswordHasher(ctrl)
+	service := New(mockRepo, mockHasher)
+
+	ctx := context.Background()
+
+	mockRepo.EXPECT().
+		Get(ctx, testUUID).
+		Return(models.User{ID: testUUID, PasswordHash: "old-hash"}, nil)
+	mockHasher.EXPECT().
+		Verify("wrong", "old-hash").
+		Return(false, nil)
+
+	err := service.UpdatePassword(ctx, testUUID, "wrong", "new-secret")
+
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+}
@@ -17,6 +17,7 @@ func main() {
 
 	cfg := config.New()
 	c := container.New(cfg)
+	defer c.Close()
 	log := c.Logger()
 
 	log.Info("starting chat app...")
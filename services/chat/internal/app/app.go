@@ -1,13 +1,106 @@
 package app
 
-import "context"
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
 
-type App struct{}
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthv1 "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
 
-func New() *App {
-	return &App{}
+	chatv1 "github.com/BeInBloom/grpc-chat/gen/go/chat/v1"
+)
+
+type chatHandlers interface {
+	chatv1.ChatServiceServer
+	Drain()
+}
+
+type App struct {
+	handlers        chatHandlers
+	logger          *slog.Logger
+	options         []grpc.ServerOption
+	addr            string
+	shutdownTimeout time.Duration
+}
+
+func New(
+	addr string,
+	shutdownTimeout time.Duration,
+	logger *slog.Logger,
+	handlers chatHandlers,
+	options ...grpc.ServerOption,
+) *App {
+	logger = logger.With("layer", "chat app")
+
+	return &App{
+		logger:          logger,
+		handlers:        handlers,
+		options:         options,
+		addr:            addr,
+		shutdownTimeout: shutdownTimeout,
+	}
 }
 
 func (a *App) Run(ctx context.Context) error {
-	return nil
+	lis, err := net.Listen("tcp", a.addr)
+	if err != nil {
+		return fmt.Errorf("running fail: %w", err)
+	}
+
+	grpcServer := grpc.NewServer(a.options...)
+	healthServer := health.NewServer()
+	chatv1.RegisterChatServiceServer(grpcServer, a.handlers)
+	healthv1.RegisterHealthServer(grpcServer, healthServer)
+	reflection.Register(grpcServer)
+
+	healthServer.SetServingStatus(chatv1.ChatService_ServiceDesc.ServiceName, healthv1.HealthCheckResponse_SERVING)
+
+	a.logger.Info("chat service listening", slog.String("addr", a.addr))
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := grpcServer.Serve(lis); err != nil {
+			errCh <- fmt.Errorf("something wrong: %w", err)
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		a.logger.Info("chat service shutdown by context")
+		healthServer.Shutdown()
+		a.shutdown(grpcServer)
+		a.logger.Info("chat service stopped")
+		return nil
+	case err := <-errCh:
+		return fmt.Errorf("chat service failed: %w", err)
+	}
+}
+
+// shutdown asks open Connect streams to finish and waits for in-flight
+// calls up to the shutdown timeout before closing the remaining ones.
+func (a *App) shutdown(grpcServer *grpc.Server) {
+	a.handlers.Drain()
+
+	stopped := make(chan struct{})
+	go func() {
+		grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	timer := time.NewTimer(a.shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+	case <-timer.C:
+		a.logger.Warn("chat service shutdown timed out, closing remaining streams",
+			slog.Duration("timeout", a.shutdownTimeout))
+		grpcServer.Stop()
+		<-stopped
+	}
 }
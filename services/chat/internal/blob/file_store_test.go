@@ -0,0 +1,93 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore_PutAndOpen(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	n, err := store.Put(ctx, "blob", strings.NewReader("0123456789"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), n)
+
+	tests := map[string]struct {
+		offset, length int64
+		want           string
+	}{
+		"whole":  {offset: 0, length: 10, want: "0123456789"},
+		"range":  {offset: 2, length: 3, want: "234"},
+		"tail":   {offset: 7, length: 10, want: "789"},
+		"at end": {offset: 10, length: 5, want: ""},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r, err := store.Open(ctx, "blob", tt.offset, tt.length)
+			require.NoError(t, err)
+			defer r.Close()
+
+			data, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestFileStore_FailedPutLeavesNothing(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileStore(dir)
+	require.NoError(t, err)
+	ctx := context.Background()
+	broken := io.MultiReader(strings.NewReader("partial"), errReader{})
+
+	_, err = store.Put(ctx, "blob", broken)
+	require.Error(t, err)
+
+	_, err = store.Open(ctx, "blob", 0, 1)
+	assert.ErrorIs(t, err, ErrNotFound)
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "the partial upload is removed")
+}
+
+func TestFileStore_Delete(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	_, err = store.Put(ctx, "blob", strings.NewReader("data"))
+	require.NoError(t, err)
+
+	require.NoError(t, store.Delete(ctx, "blob"))
+	require.NoError(t, store.Delete(ctx, "blob"), "deleting a missing blob is a no-op")
+
+	_, err = store.Open(ctx, "blob", 0, 4)
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFileStore_RejectsKeysOutsideTheDirectory(t *testing.T) {
+	store, err := NewFileStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../blob", "a/b", ".upload-1"} {
+		_, err := store.Put(context.Background(), key, strings.NewReader("data"))
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
@@ -0,0 +1,205 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+var (
+	ErrClosed            = errors.New("broker is closed")
+	ErrAlreadySubscribed = errors.New("subscription handle is already in use")
+)
+
+// Stats are cumulative delivery counters of a broker.
+type Stats struct {
+	// Published counts events sent by Publish.
+	Published uint64
+	// Delivered counts events put into a subscriber buffer.
+	Delivered uint64
+	// Dropped counts events that did not fit into a subscriber buffer.
+	Dropped uint64
+	// Evicted counts subscriptions closed because they missed a durable event.
+	Evicted uint64
+}
+
+type subscriber struct {
+	handle models.SubscriptionHandle
+	events chan models.Event
+	stop   func() bool
+}
+
+// hub keeps the local subscribers and delivers events to them without
+// blocking. A subscriber whose buffer is full loses ephemeral events; on a
+// durable event it is closed so that the client resumes from the event log.
+type hub struct {
+	mu          sync.RWMutex
+	subscribers map[uuid.UUID]map[models.SubscriptionHandle]*subscriber
+	closed      bool
+	bufferSize  int
+	logger      *slog.Logger
+
+	published atomic.Uint64
+	delivered atomic.Uint64
+	dropped   atomic.Uint64
+	evicted   atomic.Uint64
+}
+
+func newHub(bufferSize int, logger *slog.Logger) *hub {
+	return &hub{
+		subscribers: make(map[uuid.UUID]map[models.SubscriptionHandle]*subscriber),
+		bufferSize:  bufferSize,
+		logger:      logger,
+	}
+}
+
+// Subscribe returns a channel with the live events of the handle's user.
+// Every handle of the user receives every event. The channel is closed when
+// ctx is done, on Unsubscribe, or when the subscriber falls behind.
+func (h *hub) Subscribe(ctx context.Context, handle models.SubscriptionHandle) (<-chan models.Event, error) {
+	sub := &subscriber{
+		handle: handle,
+		events: make(chan models.Event, h.bufferSize),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return nil, ErrClosed
+	}
+
+	subs := h.subscribers[handle.UserID]
+	if subs == nil {
+		subs = make(map[models.SubscriptionHandle]*subscriber)
+		h.subscribers[handle.UserID] = subs
+	}
+	if _, ok := subs[handle]; ok {
+		return nil, ErrAlreadySubscribed
+	}
+	subs[handle] = sub
+	sub.stop = context.AfterFunc(ctx, func() {
+		h.remove(sub)
+	})
+
+	return sub.events, nil
+}
+
+// Unsubscribe closes the subscription of the handle and leaves the other
+// streams of the user intact.
+func (h *hub) Unsubscribe(handle models.SubscriptionHandle) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if sub, ok := h.subscribers[handle.UserID][handle]; ok {
+		h.closeLocked(sub)
+	}
+
+	return nil
+}
+
+func (h *hub) Stats() Stats {
+	return Stats{
+		Published: h.published.Load(),
+		Delivered: h.delivered.Load(),
+		Dropped:   h.dropped.Load(),
+		Evicted:   h.evicted.Load(),
+	}
+}
+
+func (h *hub) deliver(events ...models.Event) {
+	var lagging []*subscriber
+
+	h.mu.RLock()
+	for _, event := range events {
+		for _, sub := range h.subscribers[event.UserID] {
+			select {
+			case sub.events <- event:
+				h.delivered.Add(1)
+			default:
+				h.dropped.Add(1)
+				if !event.Type.IsEphemeral() {
+					lagging = append(lagging, sub)
+				}
+			}
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range lagging {
+		if h.remove(sub) {
+			h.evicted.Add(1)
+			h.logger.Warn("subscriber buffer overflow, closing subscription",
+				slog.String("user_id", sub.handle.UserID.String()),
+				slog.String("session_id", sub.handle.SessionID.String()),
+				slog.String("stream_id", sub.handle.StreamID.String()))
+		}
+	}
+}
+
+// evictAll closes every subscription, e.g. after the broker may have missed
+// events and the subscribers have to resume from the event log.
+func (h *hub) evictAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, subs := range h.subscribers {
+		for _, sub := range subs {
+			h.closeLocked(sub)
+			h.evicted.Add(1)
+		}
+	}
+}
+
+// evictUser closes the subscriptions of the user, e.g. after one of the
+// user's durable events could not be delivered.
+func (h *hub) evictUser(userID uuid.UUID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for _, sub := range h.subscribers[userID] {
+		h.closeLocked(sub)
+		h.evicted.Add(1)
+	}
+}
+
+func (h *hub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, subs := range h.subscribers {
+		for _, sub := range subs {
+			h.closeLocked(sub)
+		}
+	}
+}
+
+func (h *hub) remove(sub *subscriber) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if current, ok := h.subscribers[sub.handle.UserID][sub.handle]; !ok || current != sub {
+		return false
+	}
+	h.closeLocked(sub)
+
+	return true
+}
+
+func (h *hub) closeLocked(sub *subscriber) {
+	subs := h.subscribers[sub.handle.UserID]
+	delete(subs, sub.handle)
+	if len(subs) == 0 {
+		delete(h.subscribers, sub.handle.UserID)
+	}
+
+	sub.stop()
+	close(sub.events)
+}
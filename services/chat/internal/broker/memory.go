@@ -0,0 +1,29 @@
+package broker
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+// MemoryBroker delivers events to subscribers of the same process. It is
+// meant for single-node deployments and tests.
+type MemoryBroker struct {
+	*hub
+}
+
+func NewMemoryBroker(bufferSize int, logger *slog.Logger) *MemoryBroker {
+	return &MemoryBroker{hub: newHub(bufferSize, logger.With("layer", "memory broker"))}
+}
+
+func (b *MemoryBroker) Publish(_ context.Context, events ...models.Event) error {
+	b.published.Add(uint64(len(events)))
+	b.deliver(events...)
+
+	return nil
+}
+
+func (b *MemoryBroker) Close() {
+	b.close()
+}
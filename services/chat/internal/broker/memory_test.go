@@ -0,0 +1,150 @@
+package broker
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func newTestEvent(userID uuid.UUID, eventType models.EventType) models.Event {
+	return models.Event{
+		ID:      uuid.Must(uuid.NewV7()),
+		UserID:  userID,
+		Type:    eventType,
+		Payload: models.SystemNotificationPayload{Text: "hi"},
+	}
+}
+
+func TestMemoryBroker_PublishToEverySubscriber(t *testing.T) {
+	b := NewMemoryBroker(4, testLogger)
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+
+	phone, err := b.Subscribe(ctx, models.NewSubscriptionHandle(alice, uuid.New()))
+	require.NoError(t, err)
+	laptop, err := b.Subscribe(ctx, models.NewSubscriptionHandle(alice, uuid.New()))
+	require.NoError(t, err)
+	other, err := b.Subscribe(ctx, models.NewSubscriptionHandle(bob, uuid.New()))
+	require.NoError(t, err)
+
+	event := newTestEvent(alice, models.EventTypeSystem)
+	require.NoError(t, b.Publish(ctx, event))
+
+	assert.Equal(t, event.ID, (<-phone).ID)
+	assert.Equal(t, event.ID, (<-laptop).ID)
+	assert.Empty(t, other)
+	assert.Equal(t, Stats{Published: 1, Delivered: 2}, b.Stats())
+}
+
+func TestMemoryBroker_ContextCancelRemovesSubscriber(t *testing.T) {
+	b := NewMemoryBroker(4, testLogger)
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := b.Subscribe(ctx, models.NewSubscriptionHandle(uuid.New(), uuid.New()))
+	require.NoError(t, err)
+
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not closed")
+	}
+}
+
+func TestMemoryBroker_UnsubscribeKeepsOtherDevices(t *testing.T) {
+	b := NewMemoryBroker(4, testLogger)
+	ctx := context.Background()
+	userID := uuid.New()
+	phoneHandle := models.NewSubscriptionHandle(userID, uuid.New())
+
+	phone, err := b.Subscribe(ctx, phoneHandle)
+	require.NoError(t, err)
+	laptop, err := b.Subscribe(ctx, models.NewSubscriptionHandle(userID, uuid.New()))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Unsubscribe(phoneHandle))
+
+	_, ok := <-phone
+	assert.False(t, ok)
+
+	event := newTestEvent(userID, models.EventTypeSystem)
+	require.NoError(t, b.Publish(ctx, event))
+	assert.Equal(t, event.ID, (<-laptop).ID)
+}
+
+func TestMemoryBroker_SameSessionTwice(t *testing.T) {
+	b := NewMemoryBroker(4, testLogger)
+	ctx := context.Background()
+	userID, sessionID := uuid.New(), uuid.New()
+
+	first, err := b.Subscribe(ctx, models.NewSubscriptionHandle(userID, sessionID))
+	require.NoError(t, err)
+	second, err := b.Subscribe(ctx, models.NewSubscriptionHandle(userID, sessionID))
+	require.NoError(t, err)
+
+	event := newTestEvent(userID, models.EventTypeSystem)
+	require.NoError(t, b.Publish(ctx, event))
+	assert.Equal(t, event.ID, (<-first).ID)
+	assert.Equal(t, event.ID, (<-second).ID)
+}
+
+func TestMemoryBroker_DuplicateHandle(t *testing.T) {
+	b := NewMemoryBroker(4, testLogger)
+	ctx := context.Background()
+	handle := models.NewSubscriptionHandle(uuid.New(), uuid.New())
+
+	_, err := b.Subscribe(ctx, handle)
+	require.NoError(t, err)
+
+	_, err = b.Subscribe(ctx, handle)
+	assert.ErrorIs(t, err, ErrAlreadySubscribed)
+}
+
+func TestMemoryBroker_Overflow(t *testing.T) {
+	b := NewMemoryBroker(1, testLogger)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	events, err := b.Subscribe(ctx, models.NewSubscriptionHandle(userID, uuid.New()))
+	require.NoError(t, err)
+
+	first := newTestEvent(userID, models.EventTypeSystem)
+	require.NoError(t, b.Publish(ctx, first))
+
+	require.NoError(t, b.Publish(ctx, newTestEvent(userID, models.EventTypeTyping)))
+	assert.Equal(t, Stats{Published: 2, Delivered: 1, Dropped: 1}, b.Stats(), "ephemeral events are dropped")
+
+	require.NoError(t, b.Publish(ctx, newTestEvent(userID, models.EventTypeSystem)))
+	assert.Equal(t, Stats{Published: 3, Delivered: 1, Dropped: 2, Evicted: 1}, b.Stats())
+
+	assert.Equal(t, first.ID, (<-events).ID)
+	_, ok := <-events
+	assert.False(t, ok, "subscriber that missed a durable event is closed")
+}
+
+func TestMemoryBroker_Close(t *testing.T) {
+	b := NewMemoryBroker(1, testLogger)
+	handle := models.NewSubscriptionHandle(uuid.New(), uuid.New())
+
+	events, err := b.Subscribe(context.Background(), handle)
+	require.NoError(t, err)
+
+	b.Close()
+
+	_, ok := <-events
+	assert.False(t, ok)
+
+	_, err = b.Subscribe(context.Background(), handle)
+	assert.ErrorIs(t, err, ErrClosed)
+}
@@ -0,0 +1,237 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+const (
+	notifyChannel = "chat_events"
+	// maxNotifyPayload stays below the 8000 byte NOTIFY limit. Larger durable
+	// events are sent by reference and loaded from the event log.
+	maxNotifyPayload = 7900
+	reconnectDelay   = time.Second
+)
+
+type eventLoader interface {
+	GetEvent(ctx context.Context, userID, eventID uuid.UUID) (models.Event, error)
+}
+
+// notification is the NOTIFY payload. Payload is empty when the event is
+// sent by reference. TxID and Seq are the event's position in its user's
+// log, and zero for an ephemeral event.
+type notification struct {
+	ID        uuid.UUID        `json:"id"`
+	UserID    uuid.UUID        `json:"user_id"`
+	ChatID    *uuid.UUID       `json:"chat_id,omitempty"`
+	Type      models.EventType `json:"type"`
+	Payload   json.RawMessage  `json:"payload,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+	TxID      int64            `json:"tx_id,omitempty"`
+	Seq       int64            `json:"seq,omitempty"`
+}
+
+// PostgresBroker fans events out to every chat replica through Postgres
+// LISTEN/NOTIFY. Publishing inside postgres.TxManager.Do sends the events
+// only when the transaction commits.
+type PostgresBroker struct {
+	*hub
+	pool   *pgxpool.Pool
+	loader eventLoader
+	logger *slog.Logger
+}
+
+func NewPostgresBroker(pool *pgxpool.Pool, loader eventLoader, bufferSize int, logger *slog.Logger) *PostgresBroker {
+	logger = logger.With("layer", "postgres broker")
+
+	return &PostgresBroker{
+		hub:    newHub(bufferSize, logger),
+		pool:   pool,
+		loader: loader,
+		logger: logger,
+	}
+}
+
+func (b *PostgresBroker) Publish(ctx context.Context, events ...models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	batch := &pgx.Batch{}
+	for _, event := range events {
+		payload, err := encodeNotification(event, maxNotifyPayload)
+		if err != nil {
+			return err
+		}
+		if payload == "" {
+			b.dropped.Add(1)
+			b.logger.Warn("ephemeral event is too large to publish", slog.String("event_id", event.ID.String()))
+			continue
+		}
+
+		batch.Queue("SELECT pg_notify($1, $2)", notifyChannel, payload)
+	}
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	if err := postgres.Conn(ctx, b.pool).SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("notify events: %w", err)
+	}
+	b.published.Add(uint64(batch.Len()))
+
+	return nil
+}
+
+// Listen receives notifications until ctx is done. After a lost connection
+// all local subscriptions are closed, because notifications sent in the
+// meantime are gone.
+func (b *PostgresBroker) Listen(ctx context.Context) error {
+	defer b.close()
+
+	for {
+		err := b.listen(ctx)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		b.logger.Error("listen for events", slog.String("error", err.Error()))
+		b.evictAll()
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(reconnectDelay):
+		}
+	}
+}
+
+func (b *PostgresBroker) listen(ctx context.Context) error {
+	conn, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire connection: %w", err)
+	}
+	defer conn.Release()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+notifyChannel); err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	defer func() {
+		_, _ = conn.Exec(context.WithoutCancel(ctx), "UNLISTEN "+notifyChannel)
+	}()
+
+	for {
+		n, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return fmt.Errorf("wait for notification: %w", err)
+		}
+
+		b.receive(ctx, n.Payload)
+	}
+}
+
+// receive delivers the event of a notification. When a durable event cannot
+// be loaded, the subscribers that missed it are closed like on overflow, so
+// that they resume from the event log.
+func (b *PostgresBroker) receive(ctx context.Context, payload string) {
+	n, err := parseNotification(payload)
+	if err != nil {
+		b.logger.Error("parse notification, closing all subscriptions", slog.String("error", err.Error()))
+		b.evictAll()
+		return
+	}
+
+	event, err := b.load(ctx, n)
+	if err != nil {
+		b.logger.Error("load notified event",
+			slog.String("event_id", n.ID.String()),
+			slog.String("user_id", n.UserID.String()),
+			slog.String("error", err.Error()))
+		if !n.Type.IsEphemeral() {
+			b.evictUser(n.UserID)
+		}
+		return
+	}
+
+	b.deliver(event)
+}
+
+func parseNotification(payload string) (notification, error) {
+	var n notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		return notification{}, fmt.Errorf("unmarshal notification: %w", err)
+	}
+
+	return n, nil
+}
+
+func (b *PostgresBroker) load(ctx context.Context, n notification) (models.Event, error) {
+	if len(n.Payload) == 0 {
+		if b.loader == nil {
+			return models.Event{}, errors.New("event sent by reference but no loader configured")
+		}
+		return b.loader.GetEvent(ctx, n.UserID, n.ID)
+	}
+
+	return models.EventDTO{
+		ID:        n.ID,
+		UserID:    n.UserID,
+		ChatID:    n.ChatID,
+		Type:      n.Type,
+		Payload:   n.Payload,
+		CreatedAt: n.CreatedAt,
+		Position:  models.EventPosition{TxID: n.TxID, Seq: n.Seq},
+	}.ToEvent()
+}
+
+// encodeNotification returns the NOTIFY payload of the event, sending it
+// by reference when it is over limit bytes inline, or an empty string for
+// an ephemeral event that cannot be sent by reference.
+func encodeNotification(event models.Event, limit int) (string, error) {
+	dto, err := models.ToEventDTO(event)
+	if err != nil {
+		return "", err
+	}
+
+	n := notification{
+		ID:        dto.ID,
+		UserID:    dto.UserID,
+		ChatID:    dto.ChatID,
+		Type:      dto.Type,
+		Payload:   dto.Payload,
+		CreatedAt: dto.CreatedAt,
+		TxID:      event.Position.TxID,
+		Seq:       event.Position.Seq,
+	}
+
+	data, err := json.Marshal(n)
+	if err != nil {
+		return "", fmt.Errorf("marshal notification: %w", err)
+	}
+	if len(data) <= limit {
+		return string(data), nil
+	}
+
+	if event.Type.IsEphemeral() {
+		return "", nil
+	}
+
+	n.Payload = nil
+	data, err = json.Marshal(n)
+	if err != nil {
+		return "", fmt.Errorf("marshal notification: %w", err)
+	}
+
+	return string(data), nil
+}
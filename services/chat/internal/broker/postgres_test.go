@@ -0,0 +1,142 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+type fakeLoader map[uuid.UUID]models.Event
+
+func (l fakeLoader) GetEvent(_ context.Context, _, eventID uuid.UUID) (models.Event, error) {
+	event, ok := l[eventID]
+	if !ok {
+		return models.Event{}, errors.New("event not found")
+	}
+
+	return event, nil
+}
+
+func TestPostgresBroker_DecodeNotification(t *testing.T) {
+	userID := uuid.New()
+	small := newTestEvent(userID, models.EventTypeSystem)
+	small.Position = models.EventPosition{TxID: 7, Seq: 42}
+	large := models.Event{
+		ID:      uuid.Must(uuid.NewV7()),
+		UserID:  userID,
+		Type:    models.EventTypeSystem,
+		Payload: models.SystemNotificationPayload{Text: strings.Repeat("x", maxNotifyPayload)},
+	}
+	b := NewPostgresBroker(nil, fakeLoader{large.ID: large}, 1, testLogger)
+
+	for name, event := range map[string]models.Event{"inline": small, "by reference": large} {
+		t.Run(name, func(t *testing.T) {
+			payload, err := encodeNotification(event, maxNotifyPayload)
+			require.NoError(t, err)
+			assert.LessOrEqual(t, len(payload), maxNotifyPayload)
+
+			n, err := parseNotification(payload)
+			require.NoError(t, err)
+			got, err := b.load(context.Background(), n)
+			require.NoError(t, err)
+			assert.Equal(t, event, got)
+		})
+	}
+}
+
+func TestPostgresBroker_UnloadableEventEvictsSubscribers(t *testing.T) {
+	alice, bob := uuid.New(), uuid.New()
+	b := NewPostgresBroker(nil, fakeLoader{}, 1, testLogger)
+
+	aliceEvents, err := b.Subscribe(context.Background(), models.NewSubscriptionHandle(alice, uuid.New()))
+	require.NoError(t, err)
+	bobEvents, err := b.Subscribe(context.Background(), models.NewSubscriptionHandle(bob, uuid.New()))
+	require.NoError(t, err)
+
+	payload, err := json.Marshal(notification{ID: uuid.Must(uuid.NewV7()), UserID: alice, Type: models.EventTypeSystem})
+	require.NoError(t, err)
+	b.receive(context.Background(), string(payload))
+
+	_, open := <-aliceEvents
+	assert.False(t, open, "the subscriber missed a durable event and resumes from the log")
+	assert.Equal(t, Stats{Evicted: 1}, b.Stats())
+
+	b.receive(context.Background(), "not json")
+
+	_, open = <-bobEvents
+	assert.False(t, open, "the user of an unreadable notification is unknown")
+	assert.Equal(t, Stats{Evicted: 2}, b.Stats())
+}
+
+func TestPostgresBroker_LargeEphemeralEvent(t *testing.T) {
+	event := models.Event{
+		ID:      uuid.Must(uuid.NewV7()),
+		UserID:  uuid.New(),
+		Type:    models.EventTypeTyping,
+		Payload: models.TypingIndicatorPayload{ChatID: uuid.New(), UserID: uuid.New(), IsTyping: true},
+	}
+
+	payload, err := encodeNotification(event, maxNotifyPayload)
+	require.NoError(t, err)
+	assert.NotEmpty(t, payload)
+
+	payload, err = encodeNotification(event, len(payload)-1)
+	require.NoError(t, err)
+	assert.Empty(t, payload, "an ephemeral event is not in the log to be sent by reference")
+}
+
+func TestPostgresBroker_MismatchedPayload(t *testing.T) {
+	_, err := encodeNotification(models.Event{
+		ID:      uuid.Must(uuid.NewV7()),
+		UserID:  uuid.New(),
+		Type:    models.EventTypeTyping,
+		Payload: models.SystemNotificationPayload{Text: strings.Repeat("x", maxNotifyPayload)},
+	}, maxNotifyPayload)
+
+	assert.ErrorIs(t, err, models.ErrPayloadMismatch)
+}
+
+func TestPostgresBroker_PublishListen(t *testing.T) {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	pool, err := postgres.NewPool(ctx, postgres.Config{DSN: dsn})
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	b := NewPostgresBroker(pool, fakeLoader{}, 4, testLogger)
+	go func() {
+		_ = b.Listen(ctx)
+	}()
+
+	userID := uuid.New()
+	events, err := b.Subscribe(ctx, models.NewSubscriptionHandle(userID, uuid.New()))
+	require.NoError(t, err)
+
+	event := newTestEvent(userID, models.EventTypeSystem)
+	require.Eventually(t, func() bool {
+		require.NoError(t, b.Publish(ctx, event))
+		select {
+		case got := <-events:
+			return got.ID == event.ID
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,47 @@
+package broker
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type statsSource interface {
+	Stats() Stats
+}
+
+// ReportStats logs the broker counters every interval until ctx is done.
+// Each line carries the totals and what changed since the previous one;
+// it is a warning when subscribers lost events in between.
+func ReportStats(ctx context.Context, source statsSource, interval time.Duration, logger *slog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	previous := source.Stats()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := source.Stats()
+			logStats(ctx, logger, previous, current)
+			previous = current
+		}
+	}
+}
+
+func logStats(ctx context.Context, logger *slog.Logger, previous, current Stats) {
+	level := slog.LevelInfo
+	if current.Dropped > previous.Dropped || current.Evicted > previous.Evicted {
+		level = slog.LevelWarn
+	}
+
+	logger.Log(ctx, level, "broker stats",
+		slog.Uint64("published", current.Published),
+		slog.Uint64("delivered", current.Delivered),
+		slog.Uint64("dropped", current.Dropped),
+		slog.Uint64("evicted", current.Evicted),
+		slog.Uint64("new_dropped", current.Dropped-previous.Dropped),
+		slog.Uint64("new_evicted", current.Evicted-previous.Evicted),
+	)
+}
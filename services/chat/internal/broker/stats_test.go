@@ -0,0 +1,73 @@
+package broker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestReportStats_LogsLiveCounters(t *testing.T) {
+	b := NewMemoryBroker(1, testLogger)
+	alice := uuid.New()
+	_, err := b.Subscribe(context.Background(), models.NewSubscriptionHandle(alice, uuid.New()))
+	require.NoError(t, err)
+
+	var logs syncBuffer
+	logger := slog.New(slog.NewJSONHandler(&logs, nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		ReportStats(ctx, b, 10*time.Millisecond, logger)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return logs.String() != "" }, time.Second, 5*time.Millisecond,
+		"the reporter has taken its first reading")
+	require.NoError(t, b.Publish(ctx, newTestEvent(alice, models.EventTypeTyping), newTestEvent(alice, models.EventTypeTyping)))
+
+	require.Eventually(t, func() bool { return strings.Contains(logs.String(), `"level":"WARN"`) }, time.Second, 5*time.Millisecond)
+	cancel()
+	<-done
+
+	var line struct {
+		Dropped    uint64 `json:"dropped"`
+		NewDropped uint64 `json:"new_dropped"`
+	}
+	for _, raw := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if strings.Contains(raw, `"level":"WARN"`) {
+			require.NoError(t, json.Unmarshal([]byte(raw), &line))
+			break
+		}
+	}
+	assert.Equal(t, uint64(1), line.Dropped)
+	assert.Equal(t, uint64(1), line.NewDropped, "the drop is reported while the broker runs")
+}
+
+// syncBuffer is a bytes.Buffer the reporter writes to while the test reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
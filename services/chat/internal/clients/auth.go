@@ -0,0 +1,95 @@
+package clients
+
+import (
+	"context"
+	"crypto/ed25519"
+	"fmt"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	authv1 "github.com/BeInBloom/grpc-chat/gen/go/auth/v1"
+	"github.com/BeInBloom/grpc-chat/pkg/authtoken"
+)
+
+// maxConcurrentLookups bounds the user lookups MissingUsers runs at once.
+const maxConcurrentLookups = 8
+
+type AuthClient struct {
+	conn  *grpc.ClientConn
+	auth  authv1.AuthServiceClient
+	users authv1.UserAPIServiceClient
+}
+
+func NewAuthClient(addr string) (*AuthClient, error) {
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("create auth client: %w", err)
+	}
+
+	return &AuthClient{
+		conn:  conn,
+		auth:  authv1.NewAuthServiceClient(conn),
+		users: authv1.NewUserAPIServiceClient(conn),
+	}, nil
+}
+
+// PublicKeys returns the ed25519 keys the auth service signs access tokens with.
+func (c *AuthClient) PublicKeys(ctx context.Context) ([]ed25519.PublicKey, error) {
+	resp, err := c.auth.GetPublicKeys(ctx, &authv1.GetPublicKeysRequest{})
+	if err != nil {
+		return nil, fmt.Errorf("get public keys: %w", err)
+	}
+
+	keys := make([]ed25519.PublicKey, 0, len(resp.GetKeys()))
+	for _, key := range resp.GetKeys() {
+		if key.GetAlgorithm() != authtoken.Algorithm || len(key.GetKey()) != ed25519.PublicKeySize {
+			continue
+		}
+		keys = append(keys, ed25519.PublicKey(key.GetKey()))
+	}
+
+	return keys, nil
+}
+
+// MissingUsers returns the IDs the auth service does not know, in the order
+// they were given. The users are looked up concurrently.
+func (c *AuthClient) MissingUsers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	notFound := make([]bool, len(ids))
+
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(maxConcurrentLookups)
+	for i, id := range ids {
+		g.Go(func() error {
+			_, err := c.users.Get(gctx, &authv1.GetRequest{Id: id.String()})
+			if status.Code(err) == codes.NotFound {
+				notFound[i] = true
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("get user %s: %w", id, err)
+			}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+
+	var missing []uuid.UUID
+	for i, id := range ids {
+		if notFound[i] {
+			missing = append(missing, id)
+		}
+	}
+
+	return missing, nil
+}
+
+func (c *AuthClient) Close() error {
+	return c.conn.Close()
+}
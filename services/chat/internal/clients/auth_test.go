@@ -0,0 +1,73 @@
+package clients
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	authv1 "github.com/BeInBloom/grpc-chat/gen/go/auth/v1"
+)
+
+type fakeUsers struct {
+	authv1.UserAPIServiceClient
+
+	known    map[string]bool
+	failFor  string
+	inFlight atomic.Int32
+	mu       sync.Mutex
+	peak     int32
+}
+
+func (u *fakeUsers) Get(_ context.Context, req *authv1.GetRequest, _ ...grpc.CallOption) (*authv1.GetResponse, error) {
+	n := u.inFlight.Add(1)
+	defer u.inFlight.Add(-1)
+	u.mu.Lock()
+	u.peak = max(u.peak, n)
+	u.mu.Unlock()
+
+	if req.GetId() == u.failFor {
+		return nil, status.Error(codes.Unavailable, "down")
+	}
+	if !u.known[req.GetId()] {
+		return nil, status.Error(codes.NotFound, "no such user")
+	}
+	return &authv1.GetResponse{}, nil
+}
+
+func TestAuthClient_MissingUsers(t *testing.T) {
+	ids := make([]uuid.UUID, 3*maxConcurrentLookups)
+	users := &fakeUsers{known: make(map[string]bool)}
+	var want []uuid.UUID
+	for i := range ids {
+		ids[i] = uuid.New()
+		if i%3 == 0 {
+			want = append(want, ids[i])
+		} else {
+			users.known[ids[i].String()] = true
+		}
+	}
+	client := &AuthClient{users: users}
+
+	missing, err := client.MissingUsers(context.Background(), ids)
+
+	require.NoError(t, err)
+	assert.Equal(t, want, missing, "missing users keep the order they were given in")
+	assert.LessOrEqual(t, users.peak, int32(maxConcurrentLookups))
+}
+
+func TestAuthClient_MissingUsersFails(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	client := &AuthClient{users: &fakeUsers{failFor: ids[1].String()}}
+
+	_, err := client.MissingUsers(context.Background(), ids)
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
@@ -3,15 +3,129 @@ package config
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 
 	"github.com/BeInBloom/grpc-chat/pkg/logger"
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
 )
 
 type Config struct {
-	Addr   string        `yaml:"addr" env:"addr" env-default:"localhost:50052"`
-	Logger logger.Config `yaml:"logger"`
+	Addr string `yaml:"addr" env:"addr" env-default:"localhost:50052"`
+	// ShutdownTimeout bounds how long open streams may take to drain.
+	ShutdownTimeout time.Duration     `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" env-default:"10s"`
+	Logger          logger.Config     `yaml:"logger"`
+	Auth            AuthConfig        `yaml:"auth"`
+	Postgres        postgres.Config   `yaml:"postgres"`
+	Broker          BrokerConfig      `yaml:"broker"`
+	Messages        MessagesConfig    `yaml:"messages"`
+	Cursor          CursorConfig      `yaml:"cursor"`
+	Typing          TypingConfig      `yaml:"typing"`
+	Events          EventsConfig      `yaml:"events"`
+	Stream          StreamConfig      `yaml:"stream"`
+	Snapshot        SnapshotConfig    `yaml:"snapshot"`
+	Attachments     AttachmentsConfig `yaml:"attachments"`
+}
+
+type AttachmentsConfig struct {
+	// Store selects where attachment bytes are kept: fs for a directory on
+	// the local filesystem.
+	Store string `yaml:"store" env:"ATTACHMENT_STORE" env-default:"fs"`
+	// Dir is the directory of the fs store.
+	Dir string `yaml:"dir" env:"ATTACHMENT_DIR" env-default:"data/attachments"`
+	// MaxSize is the largest attachment in bytes.
+	MaxSize int64 `yaml:"max_size" env:"ATTACHMENT_MAX_SIZE" env-default:"104857600"`
+	// Quota is how many bytes of attachments a user can upload in total;
+	// zero does not limit it.
+	Quota int64 `yaml:"quota" env:"ATTACHMENT_QUOTA" env-default:"1073741824"`
+	// OrphanAfter is the age from which attachments no message refers to,
+	// such as uploads that were never sent, are deleted and their bytes
+	// returned to the quota; zero keeps them.
+	OrphanAfter time.Duration `yaml:"orphan_after" env:"ATTACHMENT_ORPHAN_AFTER" env-default:"24h"`
+}
+
+type SnapshotConfig struct {
+	// Interval is how often users whose event logs grew are snapshotted;
+	// zero turns snapshots off.
+	Interval time.Duration `yaml:"interval" env:"SNAPSHOT_INTERVAL" env-default:"1m"`
+	// MinEvents is how many new events make a user's snapshot stale.
+	MinEvents int32 `yaml:"min_events" env:"SNAPSHOT_MIN_EVENTS" env-default:"500"`
+	// BatchSize bounds the users snapshotted per interval.
+	BatchSize int32 `yaml:"batch_size" env:"SNAPSHOT_BATCH_SIZE" env-default:"100"`
+	// CompactAfter is the age from which events a snapshot covers are
+	// deleted; zero keeps them.
+	CompactAfter time.Duration `yaml:"compact_after" env:"SNAPSHOT_COMPACT_AFTER" env-default:"0s"`
+}
+
+type StreamConfig struct {
+	// BufferSize is the number of live events kept for a Connect stream that
+	// replays history or reads slowly.
+	BufferSize int `yaml:"buffer_size" env:"STREAM_BUFFER_SIZE" env-default:"100"`
+	// DropTyping drops typing events of a full stream instead of ending it.
+	DropTyping bool `yaml:"drop_typing" env:"STREAM_DROP_TYPING" env-default:"true"`
+	// CoalesceReadReceipts lets a read receipt replace the pending receipt of
+	// the same reader in a full stream instead of ending it.
+	CoalesceReadReceipts bool `yaml:"coalesce_read_receipts" env:"STREAM_COALESCE_READ_RECEIPTS" env-default:"true"`
+}
+
+type EventsConfig struct {
+	// CatchUpLimit is the most missed events Connect replays before it asks
+	// the client to resync instead.
+	CatchUpLimit int32 `yaml:"catch_up_limit" env:"CATCH_UP_LIMIT" env-default:"1000"`
+	// Retention is how long events stay in the log; zero keeps them.
+	Retention time.Duration `yaml:"retention" env:"EVENT_RETENTION" env-default:"720h"`
+	// RetentionInterval is how often expired and superseded events are
+	// deleted; zero turns the retention worker off.
+	RetentionInterval time.Duration `yaml:"retention_interval" env:"EVENT_RETENTION_INTERVAL" env-default:"10m"`
+	// CollapseAfter is the age from which events superseded by later ones,
+	// such as older edits of a message, are deleted.
+	CollapseAfter time.Duration `yaml:"collapse_after" env:"EVENT_COLLAPSE_AFTER" env-default:"1h"`
+	// RetentionBatchSize bounds the events deleted by one statement.
+	RetentionBatchSize int32 `yaml:"retention_batch_size" env:"EVENT_RETENTION_BATCH_SIZE" env-default:"1000"`
+}
+
+type TypingConfig struct {
+	// Throttle is the shortest interval between two typing starts of a user
+	// in a chat that are sent to the other members.
+	Throttle time.Duration `yaml:"throttle" env:"TYPING_THROTTLE" env-default:"3s"`
+	// Expiry is how long a user is shown as typing without a new SetTyping.
+	Expiry time.Duration `yaml:"expiry" env:"TYPING_EXPIRY" env-default:"6s"`
+}
+
+type CursorConfig struct {
+	// Secret signs pagination cursors and must be shared by all replicas;
+	// when empty a random secret is generated at startup.
+	Secret string `yaml:"secret" env:"CURSOR_SECRET"`
+}
+
+type MessagesConfig struct {
+	// IdempotencyTTL is how long a retried SendMessage with the same key
+	// returns the original message.
+	IdempotencyTTL time.Duration `yaml:"idempotency_ttl" env:"IDEMPOTENCY_TTL" env-default:"24h"`
+	// EditWindow is how long after sending a message its sender can edit it.
+	EditWindow time.Duration `yaml:"edit_window" env:"EDIT_WINDOW" env-default:"48h"`
+}
+
+type BrokerConfig struct {
+	// Driver selects the live event broker: memory for a single replica or
+	// postgres to fan out through LISTEN/NOTIFY.
+	Driver string `yaml:"driver" env:"BROKER_DRIVER" env-default:"memory"`
+	// BufferSize is the number of undelivered events kept per subscriber.
+	BufferSize int `yaml:"buffer_size" env:"BROKER_BUFFER_SIZE" env-default:"256"`
+	// StatsInterval is how often the delivery counters, such as events
+	// dropped for slow subscribers, are logged; zero turns it off.
+	StatsInterval time.Duration `yaml:"stats_interval" env:"BROKER_STATS_INTERVAL" env-default:"1m"`
+}
+
+type AuthConfig struct {
+	Addr   string `yaml:"addr" env:"AUTH_ADDR" env-default:"localhost:50051"`
+	Issuer string `yaml:"issuer" env:"AUTH_TOKEN_ISSUER" env-default:"grpc-chat-auth"`
+	// PublicKey is a base64-encoded ed25519 key; when empty the signing keys
+	// are fetched from the auth service.
+	PublicKey string `yaml:"public_key" env:"AUTH_PUBLIC_KEY"`
+	// KeysRefreshInterval limits how often unknown key IDs trigger a refetch.
+	KeysRefreshInterval time.Duration `yaml:"keys_refresh_interval" env:"AUTH_KEYS_REFRESH_INTERVAL" env-default:"1m"`
 }
 
 func New() Config {
@@ -1,17 +1,81 @@
 package container
 
 import (
+	"context"
+	"io"
+	"log"
 	"log/slog"
+	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
+	"google.golang.org/grpc"
+
+	"github.com/BeInBloom/grpc-chat/pkg/authtoken"
 	"github.com/BeInBloom/grpc-chat/pkg/logger"
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
 	"github.com/BeInBloom/grpc-chat/services/chat/internal/app"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/blob"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/broker"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/clients"
 	"github.com/BeInBloom/grpc-chat/services/chat/internal/config"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/handlers"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/interceptors"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/retention"
+	chatservice "github.com/BeInBloom/grpc-chat/services/chat/internal/services/chat_service"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/snapshot"
 )
 
+const startupTimeout = 30 * time.Second
+
+type eventBroker interface {
+	Subscribe(ctx context.Context, handle models.SubscriptionHandle) (<-chan models.Event, error)
+	Unsubscribe(handle models.SubscriptionHandle) error
+	Publish(ctx context.Context, events ...models.Event) error
+	Stats() broker.Stats
+}
+
+type blobStore interface {
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	Open(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var publicMethods = []string{
+	"/grpc.health.v1.Health/",
+	"/grpc.reflection.",
+}
+
 type container struct {
-	app    *app.App
-	logger *slog.Logger
-	config config.Config
+	app           *app.App
+	handlers      *handlers.Handlers
+	chatService   *chatservice.ChatService
+	eventStore    *repository.EventRepository
+	chatRepo      *repository.ChatRepository
+	messageRepo   *repository.MessageRepository
+	reactionRepo  *repository.ReactionRepository
+	attachments   *repository.AttachmentRepository
+	blobs         blobStore
+	memberRepo    *repository.MemberRepository
+	idempotency   *repository.IdempotencyRepository
+	readModel     *repository.ReadModelRepository
+	snapshots     *repository.SnapshotRepository
+	stopSnapshots context.CancelFunc
+	retention     *retention.Worker
+	stopRetention context.CancelFunc
+	txManager     *postgres.TxManager
+	cursors       *cursor.Codec
+	broker        eventBroker
+	stopBroker    context.CancelFunc
+	stopStats     context.CancelFunc
+	pool          *pgxpool.Pool
+	logger        *slog.Logger
+	authClient    *clients.AuthClient
+	verifier      *authtoken.Verifier
+	authenticator *interceptors.Authenticator
+	config        config.Config
 }
 
 func New(cfg config.Config) *container {
@@ -22,12 +86,337 @@ func New(cfg config.Config) *container {
 
 func (c *container) App() *app.App {
 	if c.app == nil {
-		c.app = app.New()
+		c.startSnapshotWorker()
+		c.startRetentionWorker()
+		c.startBrokerStats()
+
+		authenticator := c.Authenticator()
+		c.app = app.New(
+			c.config.Addr,
+			c.config.ShutdownTimeout,
+			c.Logger(),
+			c.Handlers(),
+			grpc.ChainUnaryInterceptor(authenticator.Unary()),
+			grpc.ChainStreamInterceptor(authenticator.Stream()),
+		)
 	}
 
 	return c.app
 }
 
+func (c *container) Handlers() *handlers.Handlers {
+	if c.handlers == nil {
+		c.handlers = handlers.New(c.ChatService(), c.Logger())
+	}
+
+	return c.handlers
+}
+
+func (c *container) ChatService() *chatservice.ChatService {
+	if c.chatService == nil {
+		c.chatService = chatservice.New(
+			chatservice.Deps{
+				TxManager:   c.TxManager(),
+				EventStore:  c.EventStore(),
+				Chats:       c.ChatRepo(),
+				Messages:    c.MessageRepo(),
+				Reactions:   c.ReactionRepo(),
+				Attachments: c.AttachmentRepo(),
+				Blobs:       c.Blobs(),
+				Members:     c.MemberRepo(),
+				Idempotency: c.IdempotencyRepo(),
+				Users:       c.AuthClient(),
+				Cursors:     c.Cursors(),
+				Publisher:   c.Broker(),
+				ReadModel:   c.ReadModel(),
+				Snapshotter: c.SnapshotRepo(),
+				Retention:   c.Retention(),
+			},
+			chatservice.Config{
+				IdempotencyTTL:    c.config.Messages.IdempotencyTTL,
+				EditWindow:        c.config.Messages.EditWindow,
+				MaxAttachmentSize: c.config.Attachments.MaxSize,
+				AttachmentQuota:   c.config.Attachments.Quota,
+				TypingThrottle:    c.config.Typing.Throttle,
+				TypingExpiry:      c.config.Typing.Expiry,
+				CatchUpLimit:      c.config.Events.CatchUpLimit,
+				LiveBufferSize:    c.config.Stream.BufferSize,
+				Backpressure:      c.backpressurePolicy(),
+			},
+			c.Logger(),
+		)
+	}
+
+	return c.chatService
+}
+
+func (c *container) backpressurePolicy() chatservice.BackpressurePolicy {
+	policy := chatservice.BackpressurePolicy{}
+	if c.config.Stream.DropTyping {
+		policy[models.EventTypeTyping] = chatservice.OverflowDrop
+	}
+	if c.config.Stream.CoalesceReadReceipts {
+		policy[models.EventTypeReadReceipt] = chatservice.OverflowCoalesce
+	}
+
+	return policy
+}
+
+func (c *container) Cursors() *cursor.Codec {
+	if c.cursors == nil {
+		secret := []byte(c.config.Cursor.Secret)
+		if len(secret) == 0 {
+			generated, err := cursor.GenerateSecret()
+			if err != nil {
+				log.Fatalf("cannot generate cursor secret: %s", err)
+			}
+			c.Logger().Warn("cursor secret is not configured, cursors will not survive a restart")
+			secret = generated
+		}
+		c.cursors = cursor.NewCodec(secret)
+	}
+
+	return c.cursors
+}
+
+func (c *container) ChatRepo() *repository.ChatRepository {
+	if c.chatRepo == nil {
+		c.chatRepo = repository.NewChatRepository(c.Pool())
+	}
+
+	return c.chatRepo
+}
+
+func (c *container) MessageRepo() *repository.MessageRepository {
+	if c.messageRepo == nil {
+		c.messageRepo = repository.NewMessageRepository(c.Pool())
+	}
+
+	return c.messageRepo
+}
+
+func (c *container) ReactionRepo() *repository.ReactionRepository {
+	if c.reactionRepo == nil {
+		c.reactionRepo = repository.NewReactionRepository(c.Pool())
+	}
+
+	return c.reactionRepo
+}
+
+func (c *container) AttachmentRepo() *repository.AttachmentRepository {
+	if c.attachments == nil {
+		c.attachments = repository.NewAttachmentRepository(c.Pool())
+	}
+
+	return c.attachments
+}
+
+func (c *container) Blobs() blobStore {
+	if c.blobs == nil {
+		cfg := c.config.Attachments
+
+		switch cfg.Store {
+		case "fs":
+			store, err := blob.NewFileStore(cfg.Dir)
+			if err != nil {
+				log.Fatalf("cannot create attachment store: %s", err)
+			}
+			c.blobs = store
+		default:
+			log.Fatalf("unsupported attachment store: %s", cfg.Store)
+		}
+	}
+
+	return c.blobs
+}
+
+func (c *container) MemberRepo() *repository.MemberRepository {
+	if c.memberRepo == nil {
+		c.memberRepo = repository.NewMemberRepository(c.Pool())
+	}
+
+	return c.memberRepo
+}
+
+func (c *container) IdempotencyRepo() *repository.IdempotencyRepository {
+	if c.idempotency == nil {
+		c.idempotency = repository.NewIdempotencyRepository(c.Pool())
+	}
+
+	return c.idempotency
+}
+
+func (c *container) ReadModel() *repository.ReadModelRepository {
+	if c.readModel == nil {
+		c.readModel = repository.NewReadModelRepository(c.Pool())
+	}
+
+	return c.readModel
+}
+
+func (c *container) SnapshotRepo() *repository.SnapshotRepository {
+	if c.snapshots == nil {
+		c.snapshots = repository.NewSnapshotRepository(c.Pool())
+	}
+
+	return c.snapshots
+}
+
+// startSnapshotWorker runs the snapshot worker until Close.
+func (c *container) startSnapshotWorker() {
+	cfg := c.config.Snapshot
+	if cfg.Interval <= 0 || c.stopSnapshots != nil {
+		return
+	}
+
+	worker := snapshot.NewWorker(c.SnapshotRepo(), c.EventStore(), snapshot.Config{
+		Interval:     cfg.Interval,
+		MinEvents:    cfg.MinEvents,
+		BatchSize:    cfg.BatchSize,
+		CompactAfter: cfg.CompactAfter,
+	}, c.Logger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go worker.Run(ctx)
+	c.stopSnapshots = cancel
+}
+
+func (c *container) Retention() *retention.Worker {
+	if c.retention == nil {
+		cfg := c.config.Events
+		c.retention = retention.NewWorker(c.EventStore(), c.IdempotencyRepo(), c.AttachmentRepo(), c.Blobs(), retention.Config{
+			TTL:                    cfg.Retention,
+			Interval:               cfg.RetentionInterval,
+			CollapseAfter:          cfg.CollapseAfter,
+			IdempotencyTTL:         c.config.Messages.IdempotencyTTL,
+			OrphanAttachmentsAfter: c.config.Attachments.OrphanAfter,
+			BatchSize:              cfg.RetentionBatchSize,
+		}, c.Logger())
+	}
+
+	return c.retention
+}
+
+// startRetentionWorker runs the retention worker until Close.
+func (c *container) startRetentionWorker() {
+	if c.config.Events.RetentionInterval <= 0 || c.stopRetention != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go c.Retention().Run(ctx)
+	c.stopRetention = cancel
+}
+
+func (c *container) TxManager() *postgres.TxManager {
+	if c.txManager == nil {
+		c.txManager = postgres.NewTxManager(c.Pool())
+	}
+
+	return c.txManager
+}
+
+func (c *container) EventStore() *repository.EventRepository {
+	if c.eventStore == nil {
+		c.eventStore = repository.NewEventRepository(c.Pool())
+	}
+
+	return c.eventStore
+}
+
+func (c *container) Broker() eventBroker {
+	if c.broker == nil {
+		cfg := c.config.Broker
+
+		switch cfg.Driver {
+		case "memory":
+			c.broker = broker.NewMemoryBroker(cfg.BufferSize, c.Logger())
+		case "postgres":
+			pgBroker := broker.NewPostgresBroker(c.Pool(), c.EventStore(), cfg.BufferSize, c.Logger())
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				_ = pgBroker.Listen(ctx)
+			}()
+			c.broker = pgBroker
+			c.stopBroker = cancel
+		default:
+			log.Fatalf("unsupported broker driver: %s", cfg.Driver)
+		}
+	}
+
+	return c.broker
+}
+
+// startBrokerStats logs the broker counters periodically until Close.
+func (c *container) startBrokerStats() {
+	if c.config.Broker.StatsInterval <= 0 || c.stopStats != nil {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go broker.ReportStats(ctx, c.Broker(), c.config.Broker.StatsInterval, c.Logger().With("layer", "broker stats"))
+	c.stopStats = cancel
+}
+
+func (c *container) Pool() *pgxpool.Pool {
+	if c.pool == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), startupTimeout)
+		defer cancel()
+
+		pool, err := postgres.NewPool(ctx, c.config.Postgres)
+		if err != nil {
+			log.Fatalf("cannot connect to postgres: %s", err)
+		}
+
+		if err := postgres.Migrate(ctx, pool, repository.Migrations, repository.MigrationsDir); err != nil {
+			pool.Close()
+			log.Fatalf("cannot apply migrations: %s", err)
+		}
+
+		c.pool = pool
+	}
+
+	return c.pool
+}
+
+func (c *container) Authenticator() *interceptors.Authenticator {
+	if c.authenticator == nil {
+		c.authenticator = interceptors.NewAuthenticator(c.Verifier(), publicMethods...)
+	}
+
+	return c.authenticator
+}
+
+func (c *container) Verifier() *authtoken.Verifier {
+	if c.verifier == nil {
+		cfg := c.config.Auth
+
+		if cfg.PublicKey != "" {
+			key, err := authtoken.ParsePublicKey(cfg.PublicKey)
+			if err != nil {
+				log.Fatalf("cannot parse auth public key: %s", err)
+			}
+			c.verifier = authtoken.NewStaticVerifier(cfg.Issuer, key)
+		} else {
+			c.verifier = authtoken.NewRemoteVerifier(cfg.Issuer, c.AuthClient().PublicKeys, cfg.KeysRefreshInterval)
+		}
+	}
+
+	return c.verifier
+}
+
+func (c *container) AuthClient() *clients.AuthClient {
+	if c.authClient == nil {
+		client, err := clients.NewAuthClient(c.config.Auth.Addr)
+		if err != nil {
+			log.Fatalf("cannot create auth client: %s", err)
+		}
+		c.authClient = client
+	}
+
+	return c.authClient
+}
+
 func (c *container) Logger() *slog.Logger {
 	if c.logger == nil {
 		c.logger = logger.New(c.config.Logger)
@@ -35,3 +424,41 @@ func (c *container) Logger() *slog.Logger {
 
 	return c.logger
 }
+
+func (c *container) Close() {
+	if c.broker != nil {
+		stats := c.broker.Stats()
+		c.Logger().Info("broker stats",
+			slog.Uint64("published", stats.Published),
+			slog.Uint64("delivered", stats.Delivered),
+			slog.Uint64("dropped", stats.Dropped),
+			slog.Uint64("evicted", stats.Evicted),
+		)
+	}
+
+	if c.stopStats != nil {
+		c.stopStats()
+	}
+
+	if c.stopBroker != nil {
+		c.stopBroker()
+	}
+
+	if c.stopSnapshots != nil {
+		c.stopSnapshots()
+	}
+
+	if c.stopRetention != nil {
+		c.stopRetention()
+	}
+
+	if c.pool != nil {
+		c.pool.Close()
+	}
+
+	if c.authClient != nil {
+		if err := c.authClient.Close(); err != nil {
+			c.Logger().Error("close auth client", slog.String("error", err.Error()))
+		}
+	}
+}
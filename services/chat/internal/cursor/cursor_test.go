@@ -0,0 +1,69 @@
+package cursor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCodec_RoundTrip(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	scope := uuid.New()
+
+	for name, cur := range map[string]Cursor{
+		"without time": {Direction: Backward, Scope: scope, ID: uuid.Must(uuid.NewV7())},
+		"with time":    {Direction: Forward, Scope: scope, ID: uuid.New(), Time: time.Date(2030, 1, 1, 0, 0, 0, 1, time.UTC)},
+		"with window": {
+			Direction: Backward,
+			Scope:     scope,
+			ID:        uuid.New(),
+			Time:      time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+			Since:     time.Date(2029, 12, 31, 0, 0, 0, 0, time.UTC),
+			Until:     time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			got, err := codec.Decode(codec.Encode(cur), scope)
+
+			require.NoError(t, err)
+			assert.Equal(t, cur, got)
+		})
+	}
+}
+
+func TestCodec_Rejects(t *testing.T) {
+	codec := NewCodec([]byte("secret"))
+	scope := uuid.New()
+	encoded := codec.Encode(Cursor{Direction: Backward, Scope: scope, ID: uuid.New()})
+
+	tampered := []byte(encoded)
+	if tampered[10] == 'A' {
+		tampered[10] = 'B'
+	} else {
+		tampered[10] = 'A'
+	}
+
+	tests := map[string]struct {
+		encoded string
+		scope   uuid.UUID
+		codec   *Codec
+	}{
+		"garbage":      {encoded: "not a cursor", scope: scope, codec: codec},
+		"raw uuid":     {encoded: uuid.NewString(), scope: scope, codec: codec},
+		"tampered":     {encoded: string(tampered), scope: scope, codec: codec},
+		"other scope":  {encoded: encoded, scope: uuid.New(), codec: codec},
+		"other secret": {encoded: encoded, scope: scope, codec: NewCodec([]byte("other"))},
+		"truncated":    {encoded: encoded[:len(encoded)-2], scope: scope, codec: codec},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := tt.codec.Decode(tt.encoded, tt.scope)
+
+			assert.ErrorIs(t, err, ErrInvalidCursor)
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package handlers
 
 import (
+	"unicode/utf8"
+
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -10,21 +12,43 @@ import (
 	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
 )
 
-//nolint:unused // will be used when chat service RPCs are implemented
+const (
+	maxIdempotencyKeyLength = 128
+	// maxEmojiLength leaves room for emoji built from several code points,
+	// such as flags and skin tones.
+	maxEmojiLength = 64
+	// downloadChunkSize is the size of the chunks DownloadAttachment sends.
+	downloadChunkSize = 64 << 10
+)
+
 func toMessageContent(c *chatv1.MessageContent) (models.MessageContent, error) {
 	if c == nil {
 		return models.MessageContent{}, status.Error(codes.InvalidArgument, "content is required")
 	}
 
-	mc := models.MessageContent{}
-
+	var (
+		mc  models.MessageContent
+		err error
+	)
 	switch v := c.GetType().(type) {
 	case *chatv1.MessageContent_Text:
+		if len(v.Text.GetCiphertext()) == 0 {
+			return models.MessageContent{}, status.Error(codes.InvalidArgument, "text ciphertext is required")
+		}
 		mc.Type = models.ContentTypeText
 		mc.Ciphertext = v.Text.GetCiphertext()
+	case *chatv1.MessageContent_Image:
+		mc, err = toAttachmentContent(models.ContentTypeImage, "image", v.Image.GetAttachmentId(), v.Image.GetCiphertext())
+	case *chatv1.MessageContent_File:
+		mc, err = toAttachmentContent(models.ContentTypeFile, "file", v.File.GetAttachmentId(), v.File.GetCiphertext())
+	case *chatv1.MessageContent_Voice:
+		mc, err = toAttachmentContent(models.ContentTypeVoice, "voice", v.Voice.GetAttachmentId(), v.Voice.GetCiphertext())
 	default:
 		return models.MessageContent{}, status.Error(codes.InvalidArgument, "unsupported content type")
 	}
+	if err != nil {
+		return models.MessageContent{}, err
+	}
 
 	if c.ReplyToMessageId != nil {
 		replyID, err := uuid.Parse(*c.ReplyToMessageId)
@@ -37,7 +61,22 @@ func toMessageContent(c *chatv1.MessageContent) (models.MessageContent, error) {
 	return mc, nil
 }
 
-//nolint:unused // will be used when chat service RPCs are implemented
+func toAttachmentContent(
+	contentType models.ContentType,
+	name, attachmentID string,
+	ciphertext []byte,
+) (models.MessageContent, error) {
+	id, err := uuid.Parse(attachmentID)
+	if err != nil {
+		return models.MessageContent{}, status.Errorf(codes.InvalidArgument, "invalid %s attachment_id: %s", name, err)
+	}
+	if len(ciphertext) == 0 {
+		return models.MessageContent{}, status.Errorf(codes.InvalidArgument, "%s ciphertext is required", name)
+	}
+
+	return models.MessageContent{Type: contentType, Ciphertext: ciphertext, AttachmentID: &id}, nil
+}
+
 func toChatID(id string) (uuid.UUID, error) {
 	parsed, err := uuid.Parse(id)
 	if err != nil {
@@ -47,7 +86,6 @@ func toChatID(id string) (uuid.UUID, error) {
 	return parsed, nil
 }
 
-//nolint:unused // will be used when chat service RPCs are implemented
 func toMessageID(id string) (uuid.UUID, error) {
 	parsed, err := uuid.Parse(id)
 	if err != nil {
@@ -57,9 +95,8 @@ func toMessageID(id string) (uuid.UUID, error) {
 	return parsed, nil
 }
 
-//nolint:unused // will be used when chat service RPCs are implemented
 func toProtoMessage(m models.Message) *chatv1.Message {
-	return &chatv1.Message{
+	message := &chatv1.Message{
 		Id:        m.ID.String(),
 		ChatId:    m.ChatID.String(),
 		Sender:    toProtoUser(m.SenderID),
@@ -67,25 +104,112 @@ func toProtoMessage(m models.Message) *chatv1.Message {
 		CreatedAt: timestamppb.New(m.CreatedAt),
 		UpdatedAt: timestamppb.New(m.UpdatedAt),
 	}
+	if m.DeletedAt != nil {
+		message.DeletedAt = timestamppb.New(*m.DeletedAt)
+	}
+	for _, r := range m.Reactions {
+		message.Reactions = append(message.Reactions, toProtoReactionSummary(r))
+	}
+	if m.ThreadRootID != nil {
+		rootID := m.ThreadRootID.String()
+		message.ThreadRootId = &rootID
+	}
+	message.ReplyCount = m.ReplyCount
+	if m.LastReplyAt != nil {
+		message.LastReplyAt = timestamppb.New(*m.LastReplyAt)
+	}
+
+	return message
+}
+
+func toProtoReactionSummary(r models.ReactionSummary) *chatv1.ReactionSummary {
+	return &chatv1.ReactionSummary{
+		Emoji:       r.Emoji,
+		Count:       r.Count,
+		ReactedByMe: r.ReactedByMe,
+	}
+}
+
+func toAttachmentID(id string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid attachment_id: %s", err)
+	}
+
+	return parsed, nil
+}
+
+func toUploadAttachmentRequest(info *chatv1.AttachmentInfo, uploaderID uuid.UUID) (models.UploadAttachmentRequest, error) {
+	if info == nil {
+		return models.UploadAttachmentRequest{}, status.Error(codes.InvalidArgument, "the first request must carry the attachment info")
+	}
+
+	chatID, err := toChatID(info.GetChatId())
+	if err != nil {
+		return models.UploadAttachmentRequest{}, err
+	}
+	if info.GetSize() <= 0 {
+		return models.UploadAttachmentRequest{}, status.Error(codes.InvalidArgument, "attachment size must be positive")
+	}
+
+	return models.UploadAttachmentRequest{
+		ChatID:     chatID,
+		UploaderID: uploaderID,
+		Size:       info.GetSize(),
+	}, nil
+}
+
+func toReactionTarget(messageID, emoji string) (uuid.UUID, string, error) {
+	id, err := toMessageID(messageID)
+	if err != nil {
+		return uuid.Nil, "", err
+	}
+
+	switch {
+	case emoji == "":
+		return uuid.Nil, "", status.Error(codes.InvalidArgument, "emoji is required")
+	case len(emoji) > maxEmojiLength:
+		return uuid.Nil, "", status.Errorf(codes.InvalidArgument, "emoji must not exceed %d bytes", maxEmojiLength)
+	case !utf8.ValidString(emoji):
+		return uuid.Nil, "", status.Error(codes.InvalidArgument, "emoji must be valid UTF-8")
+	}
+
+	return id, emoji, nil
 }
 
-//nolint:unused // will be used when chat service RPCs are implemented
 func toProtoUser(userID uuid.UUID) *chatv1.User {
 	return &chatv1.User{
 		Id: userID.String(),
 	}
 }
 
-//nolint:unused // will be used when chat service RPCs are implemented
 func toProtoContent(c models.MessageContent) *chatv1.MessageContent {
 	mc := &chatv1.MessageContent{}
 
-	if c.Type == models.ContentTypeText {
+	var attachmentID string
+	if c.AttachmentID != nil {
+		attachmentID = c.AttachmentID.String()
+	}
+
+	switch c.Type {
+	case models.ContentTypeText:
 		mc.Type = &chatv1.MessageContent_Text{
 			Text: &chatv1.TextContent{
 				Ciphertext: c.Ciphertext,
 			},
 		}
+	case models.ContentTypeImage:
+		mc.Type = &chatv1.MessageContent_Image{
+			Image: &chatv1.ImageContent{AttachmentId: attachmentID, Ciphertext: c.Ciphertext},
+		}
+	case models.ContentTypeFile:
+		mc.Type = &chatv1.MessageContent_File{
+			File: &chatv1.FileContent{AttachmentId: attachmentID, Ciphertext: c.Ciphertext},
+		}
+	case models.ContentTypeVoice:
+		mc.Type = &chatv1.MessageContent_Voice{
+			Voice: &chatv1.VoiceContent{AttachmentId: attachmentID, Ciphertext: c.Ciphertext},
+		}
 	}
 
 	if c.ReplyToMessageID != nil {
@@ -96,7 +220,16 @@ func toProtoContent(c models.MessageContent) *chatv1.MessageContent {
 	return mc
 }
 
-//nolint:unused // will be used when chat service RPCs are implemented
+func toProtoAttachment(a models.Attachment) *chatv1.Attachment {
+	return &chatv1.Attachment{
+		Id:         a.ID.String(),
+		ChatId:     a.ChatID.String(),
+		UploaderId: a.UploaderID.String(),
+		Size:       a.Size,
+		CreatedAt:  timestamppb.New(a.CreatedAt),
+	}
+}
+
 func toProtoMessages(messages []models.Message) []*chatv1.Message {
 	result := make([]*chatv1.Message, 0, len(messages))
 	for _, m := range messages {
@@ -106,29 +239,218 @@ func toProtoMessages(messages []models.Message) []*chatv1.Message {
 	return result
 }
 
-func toProtoEvent(e models.Event) *chatv1.ConnectResponse {
-	resp := &chatv1.ConnectResponse{
-		Id: e.ID.String(),
+func toConnectRequest(handle models.SubscriptionHandle, lastEventID uuid.UUID) models.SubscribeRequest {
+	return models.SubscribeRequest{
+		Handle:      handle,
+		LastEventID: lastEventID,
+	}
+}
+
+func toSendMessageRequest(req *chatv1.SendMessageRequest, senderID uuid.UUID) (models.SendMessageRequest, error) {
+	chatID, err := toChatID(req.GetChatId())
+	if err != nil {
+		return models.SendMessageRequest{}, err
+	}
+
+	if len(req.GetIdempotencyKey()) > maxIdempotencyKeyLength {
+		return models.SendMessageRequest{}, status.Errorf(codes.InvalidArgument,
+			"idempotency_key must not exceed %d bytes", maxIdempotencyKeyLength)
+	}
+
+	content, err := toMessageContent(req.GetContent())
+	if err != nil {
+		return models.SendMessageRequest{}, err
 	}
 
-	switch e.Type {
-	case models.EventTypeMessageNew:
-		msg, ok := e.Payload.(models.Message)
-		if ok {
-			resp.Payload = &chatv1.ConnectResponse_MessageNew{
-				MessageNew: &chatv1.MessageNew{
-					Message: toProtoMessage(msg),
-				},
-			}
+	var threadRootID *uuid.UUID
+	if req.ThreadRootId != nil {
+		rootID, err := uuid.Parse(*req.ThreadRootId)
+		if err != nil {
+			return models.SendMessageRequest{}, status.Errorf(codes.InvalidArgument, "invalid thread_root_id: %s", err)
 		}
+		threadRootID = &rootID
 	}
 
-	return resp
+	return models.SendMessageRequest{
+		ChatID:         chatID,
+		SenderID:       senderID,
+		IdempotencyKey: req.GetIdempotencyKey(),
+		Content:        content,
+		ThreadRootID:   threadRootID,
+	}, nil
 }
 
-func toConnectRequest(userUI uuid.UUID, lastEventID uuid.UUID) models.SubscribeRequest {
-	return models.SubscribeRequest{
-		UserID:      userUI,
-		LastEventID: lastEventID,
+func toProtoSendMessageResponse(resp models.SendMessageResponse) *chatv1.SendMessageResponse {
+	return &chatv1.SendMessageResponse{
+		MessageId: resp.MessageID.String(),
+		CreatedAt: timestamppb.New(resp.CreatedAt),
+	}
+}
+
+func toEditMessageRequest(req *chatv1.EditMessageRequest, userID uuid.UUID) (models.EditMessageRequest, error) {
+	messageID, err := toMessageID(req.GetMessageId())
+	if err != nil {
+		return models.EditMessageRequest{}, err
+	}
+
+	content, err := toMessageContent(req.GetContent())
+	if err != nil {
+		return models.EditMessageRequest{}, err
+	}
+
+	return models.EditMessageRequest{
+		MessageID: messageID,
+		UserID:    userID,
+		Content:   content,
+	}, nil
+}
+
+func toGetHistoryRequest(req *chatv1.GetHistoryRequest, userID uuid.UUID) (models.GetHistoryRequest, error) {
+	chatID, err := toChatID(req.GetChatId())
+	if err != nil {
+		return models.GetHistoryRequest{}, err
+	}
+
+	return models.GetHistoryRequest{
+		ChatID:   chatID,
+		UserID:   userID,
+		PageSize: req.GetPageSize(),
+		Cursor:   req.GetCursor(),
+	}, nil
+}
+
+func toProtoGetHistoryResponse(resp models.GetHistoryResponse) *chatv1.GetHistoryResponse {
+	return &chatv1.GetHistoryResponse{
+		Messages:   toProtoMessages(resp.Messages),
+		NextCursor: resp.NextCursor,
+	}
+}
+
+func toCreateChatRequest(req *chatv1.CreateChatRequest, ownerID uuid.UUID) (models.CreateChatRequest, error) {
+	memberIDs := make([]uuid.UUID, 0, len(req.GetMemberIds()))
+	for _, id := range req.GetMemberIds() {
+		memberID, err := uuid.Parse(id)
+		if err != nil {
+			return models.CreateChatRequest{}, status.Errorf(codes.InvalidArgument, "invalid member_id: %s", err)
+		}
+		memberIDs = append(memberIDs, memberID)
+	}
+
+	return models.CreateChatRequest{
+		OwnerID:   ownerID,
+		Name:      req.GetName(),
+		Type:      models.ChatType(req.GetType()),
+		MemberIDs: memberIDs,
+	}, nil
+}
+
+func toProtoChat(c models.Chat) *chatv1.Chat {
+	return &chatv1.Chat{
+		Id:        c.ID.String(),
+		Name:      c.Name,
+		Type:      chatv1.ChatType(c.Type),
+		Members:   toProtoChatMembers(c.Members),
+		CreatedAt: timestamppb.New(c.CreatedAt),
+		UpdatedAt: timestamppb.New(c.UpdatedAt),
+	}
+}
+
+func toListChatsRequest(req *chatv1.ListChatsRequest, userID uuid.UUID) models.ListChatsRequest {
+	return models.ListChatsRequest{
+		UserID:   userID,
+		PageSize: req.GetPageSize(),
+		Cursor:   req.GetCursor(),
+	}
+}
+
+func toProtoListChatsResponse(resp models.ListChatsResponse) *chatv1.ListChatsResponse {
+	chats := make([]*chatv1.ChatPreview, 0, len(resp.Chats))
+	for _, c := range resp.Chats {
+		chats = append(chats, toProtoChatPreview(c))
+	}
+
+	return &chatv1.ListChatsResponse{
+		Chats:      chats,
+		NextCursor: resp.NextCursor,
+	}
+}
+
+func toProtoChatPreview(c models.ChatPreview) *chatv1.ChatPreview {
+	preview := &chatv1.ChatPreview{
+		Id:          c.ID.String(),
+		Name:        c.Name,
+		Type:        chatv1.ChatType(c.Type),
+		UnreadCount: c.UnreadCount,
+		UpdatedAt:   timestamppb.New(c.UpdatedAt),
+	}
+	if c.LastMessage != nil {
+		preview.LastMessage = toProtoMessage(*c.LastMessage)
 	}
+
+	return preview
+}
+
+func toProtoChatMembers(members []models.ChatMember) []*chatv1.ChatMember {
+	result := make([]*chatv1.ChatMember, 0, len(members))
+	for _, m := range members {
+		result = append(result, toProtoChatMember(m))
+	}
+
+	return result
+}
+
+func toProtoChatMember(m models.ChatMember) *chatv1.ChatMember {
+	return &chatv1.ChatMember{
+		UserId:   m.UserID.String(),
+		ChatId:   m.ChatID.String(),
+		Role:     chatv1.MemberRole(m.Role),
+		JoinedAt: timestamppb.New(m.JoinedAt),
+	}
+}
+
+func toAddMembersRequest(req *chatv1.AddMembersRequest, userID uuid.UUID) (models.AddMembersRequest, error) {
+	chatID, err := toChatID(req.GetChatId())
+	if err != nil {
+		return models.AddMembersRequest{}, err
+	}
+
+	memberIDs := make([]uuid.UUID, 0, len(req.GetUserIds()))
+	for _, id := range req.GetUserIds() {
+		memberID, err := uuid.Parse(id)
+		if err != nil {
+			return models.AddMembersRequest{}, status.Errorf(codes.InvalidArgument, "invalid user_id: %s", err)
+		}
+		memberIDs = append(memberIDs, memberID)
+	}
+
+	return models.AddMembersRequest{
+		ChatID:    chatID,
+		UserID:    userID,
+		MemberIDs: memberIDs,
+	}, nil
+}
+
+// toChatMember parses the chat and user IDs of a request about a member.
+func toChatMember(chatID, userID string) (uuid.UUID, uuid.UUID, error) {
+	chat, err := toChatID(chatID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+
+	user, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid user_id: %s", err)
+	}
+
+	return chat, user, nil
+}
+
+func toProtoReadState(s models.ReadState) *chatv1.MemberReadState {
+	state := &chatv1.MemberReadState{UserId: s.UserID.String()}
+	if s.LastReadMessageID != uuid.Nil {
+		state.LastReadMessageId = s.LastReadMessageID.String()
+		state.ReadAt = timestamppb.New(s.ReadAt)
+	}
+
+	return state
 }
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+	chatservice "github.com/BeInBloom/grpc-chat/services/chat/internal/services/chat_service"
+)
+
+func toGRPCError(err error) error {
+	switch {
+	case errors.Is(err, repository.ErrChatNotFound),
+		errors.Is(err, repository.ErrMemberNotFound),
+		errors.Is(err, repository.ErrMessageNotFound),
+		errors.Is(err, repository.ErrAttachmentNotFound),
+		errors.Is(err, chatservice.ErrUserNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, chatservice.ErrNotMember),
+		errors.Is(err, chatservice.ErrForbidden):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, cursor.ErrInvalidCursor),
+		errors.Is(err, chatservice.ErrInvalidPageSize),
+		errors.Is(err, chatservice.ErrInvalidChat),
+		errors.Is(err, chatservice.ErrInvalidRole),
+		errors.Is(err, chatservice.ErrInvalidThread),
+		errors.Is(err, chatservice.ErrInvalidAttachment):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, chatservice.ErrInvalidRange):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, chatservice.ErrQuotaExceeded):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case errors.Is(err, chatservice.ErrOwnerMustStay),
+		errors.Is(err, chatservice.ErrEditWindowEnded),
+		errors.Is(err, chatservice.ErrTooManyReactions):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	return status.Error(codes.Internal, err.Error())
+}
+
+var errDraining = errors.New("server is shutting down")
+
+// toStreamError maps the end of a stream. A client that went away gets no
+// status; otherwise the status of a Connect stream tells the client whether
+// and how to reconnect with its last event ID.
+func toStreamError(ctx context.Context, err error) error {
+	var st interface{ GRPCStatus() *status.Status }
+
+	switch {
+	case errors.Is(err, errDraining):
+		return status.Error(codes.Unavailable, errDraining.Error())
+	case ctx.Err() != nil:
+		return nil
+	case errors.Is(err, chatservice.ErrBufferOverflow):
+		return slowConsumerError(err)
+	case errors.Is(err, chatservice.ErrBrokerSubClosed):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.As(err, &st):
+		return st.GRPCStatus().Err()
+	}
+
+	return toGRPCError(err)
+}
+
+// slowConsumerError ends the stream of a client that reads too slowly. The
+// status carries the last event the client received, so it reconnects with
+// it as last_event_id and loses nothing.
+func slowConsumerError(err error) error {
+	st := status.New(codes.ResourceExhausted, err.Error())
+
+	var ended *chatservice.StreamEndedError
+	if !errors.As(err, &ended) {
+		return st.Err()
+	}
+
+	detailed, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   "SLOW_CONSUMER",
+		Domain:   "chat.v1",
+		Metadata: map[string]string{"last_event_id": ended.LastEventID.String()},
+	})
+	if detailErr != nil {
+		return st.Err()
+	}
+
+	return detailed.Err()
+}
@@ -0,0 +1,402 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	chatv1 "github.com/BeInBloom/grpc-chat/gen/go/chat/v1"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+// toProtoEvent converts an event for the Connect stream. It fails for an
+// unknown event type or a payload that does not match the type, rather than
+// sending an event without a payload.
+//
+// Only events stored in the event log carry an id: a client resumes with
+// it, and an id the log never held could not be resumed from. Ephemeral
+// events and events without an id are sent with an empty one.
+func toProtoEvent(e models.Event) (*chatv1.ConnectResponse, error) {
+	if err := models.ValidatePayload(e.Type, e.Payload); err != nil {
+		return nil, err
+	}
+
+	resp := &chatv1.ConnectResponse{}
+	if !e.Type.IsEphemeral() && e.ID != uuid.Nil {
+		resp.Id = e.ID.String()
+	}
+
+	switch p := e.Payload.(type) {
+	case models.MessageNewPayload:
+		resp.Payload = &chatv1.ConnectResponse_MessageNew{
+			MessageNew: &chatv1.MessageNew{
+				Message: toProtoMessage(models.Message{
+					ID:           p.MessageID,
+					ChatID:       p.ChatID,
+					SenderID:     p.SenderID,
+					Content:      p.Content,
+					CreatedAt:    p.CreatedAt,
+					UpdatedAt:    p.CreatedAt,
+					ThreadRootID: p.ThreadRootID,
+				}),
+			},
+		}
+
+	case models.MessageUpdatedPayload:
+		resp.Payload = &chatv1.ConnectResponse_MessageUpdated{
+			MessageUpdated: &chatv1.MessageUpdated{
+				MessageId:  p.MessageID.String(),
+				ChatId:     p.ChatID.String(),
+				NewContent: toProtoContent(p.NewContent),
+				UpdatedAt:  timestamppb.New(p.UpdatedAt),
+			},
+		}
+
+	case models.MessageDeletedPayload:
+		resp.Payload = &chatv1.ConnectResponse_MessageDeleted{
+			MessageDeleted: &chatv1.MessageDeleted{
+				MessageId: p.MessageID.String(),
+				ChatId:    p.ChatID.String(),
+				DeletedAt: timestamppb.New(p.DeletedAt),
+			},
+		}
+
+	case models.TypingIndicatorPayload:
+		resp.Payload = &chatv1.ConnectResponse_Typing{
+			Typing: &chatv1.TypingIndicator{
+				ChatId:   p.ChatID.String(),
+				User:     toProtoUser(p.UserID),
+				IsTyping: p.IsTyping,
+			},
+		}
+
+	case models.ReadReceiptPayload:
+		resp.Payload = &chatv1.ConnectResponse_ReadReceipt{
+			ReadReceipt: &chatv1.ReadReceipt{
+				ChatId:    p.ChatID.String(),
+				UserId:    p.UserID.String(),
+				MessageId: p.MessageID.String(),
+				ReadAt:    timestamppb.New(p.ReadAt),
+			},
+		}
+
+	case models.ReactionUpdatedPayload:
+		resp.Payload = &chatv1.ConnectResponse_ReactionUpdated{
+			ReactionUpdated: &chatv1.ReactionUpdated{
+				MessageId: p.MessageID.String(),
+				ChatId:    p.ChatID.String(),
+				UserId:    p.UserID.String(),
+				Emoji:     p.Emoji,
+				Added:     p.Added,
+				Count:     p.Count,
+				UpdatedAt: timestamppb.New(p.UpdatedAt),
+			},
+		}
+
+	case models.ThreadUpdatedPayload:
+		resp.Payload = &chatv1.ConnectResponse_ThreadUpdated{
+			ThreadUpdated: &chatv1.ThreadUpdated{
+				ChatId:        p.ChatID.String(),
+				RootMessageId: p.RootMessageID.String(),
+				ReplyCount:    p.ReplyCount,
+				LastReplyId:   p.LastReplyID.String(),
+				LastReplyAt:   timestamppb.New(p.LastReplyAt),
+			},
+		}
+
+	case models.SystemNotificationPayload:
+		resp.Payload = &chatv1.ConnectResponse_System{
+			System: &chatv1.SystemNotification{
+				Text:           p.Text,
+				Level:          chatv1.SystemNotificationLevel(p.Level),
+				ResyncRequired: p.ResyncRequired,
+			},
+		}
+
+	case models.SnapshotPayload:
+		resp.Payload = &chatv1.ConnectResponse_Snapshot{
+			Snapshot: toProtoSnapshot(p),
+		}
+	}
+
+	return resp, nil
+}
+
+// toEvent is the inverse of toProtoEvent. The recipient and the creation
+// time are not part of the stream message, so UserID and CreatedAt are left
+// unset, and an empty id becomes uuid.Nil.
+func toEvent(resp *chatv1.ConnectResponse) (models.Event, error) {
+	var event models.Event
+
+	var err error
+	if resp.GetId() != "" {
+		event.ID, err = parseEventUUID("id", resp.GetId())
+		if err != nil {
+			return models.Event{}, err
+		}
+	}
+
+	switch v := resp.GetPayload().(type) {
+	case *chatv1.ConnectResponse_MessageNew:
+		event.Type = models.EventTypeMessageNew
+		event.Payload, err = toMessageNewPayload(v.MessageNew.GetMessage())
+	case *chatv1.ConnectResponse_MessageUpdated:
+		event.Type = models.EventTypeMessageUpdated
+		event.Payload, err = toMessageUpdatedPayload(v.MessageUpdated)
+	case *chatv1.ConnectResponse_MessageDeleted:
+		event.Type = models.EventTypeMessageDeleted
+		event.Payload, err = toMessageDeletedPayload(v.MessageDeleted)
+	case *chatv1.ConnectResponse_Typing:
+		event.Type = models.EventTypeTyping
+		event.Payload, err = toTypingIndicatorPayload(v.Typing)
+	case *chatv1.ConnectResponse_ReadReceipt:
+		event.Type = models.EventTypeReadReceipt
+		event.Payload, err = toReadReceiptPayload(v.ReadReceipt)
+	case *chatv1.ConnectResponse_ReactionUpdated:
+		event.Type = models.EventTypeReactionUpdated
+		event.Payload, err = toReactionUpdatedPayload(v.ReactionUpdated)
+	case *chatv1.ConnectResponse_ThreadUpdated:
+		event.Type = models.EventTypeThreadUpdated
+		event.Payload, err = toThreadUpdatedPayload(v.ThreadUpdated)
+	case *chatv1.ConnectResponse_System:
+		event.Type = models.EventTypeSystem
+		event.Payload = models.SystemNotificationPayload{
+			Text:           v.System.GetText(),
+			Level:          models.SystemNotificationLevel(v.System.GetLevel()),
+			ResyncRequired: v.System.GetResyncRequired(),
+		}
+	case *chatv1.ConnectResponse_Snapshot:
+		event.Type = models.EventTypeSnapshot
+		event.Payload, err = toSnapshotPayload(v.Snapshot)
+	default:
+		return models.Event{}, fmt.Errorf("%w: event %q has no payload", models.ErrUnknownEventType, resp.GetId())
+	}
+	if err != nil {
+		return models.Event{}, err
+	}
+
+	if chatID, ok := payloadChatID(event.Payload); ok {
+		event.ChatID = &chatID
+	}
+
+	return event, nil
+}
+
+func toMessageNewPayload(m *chatv1.Message) (models.MessageNewPayload, error) {
+	var f eventFields
+	p := models.MessageNewPayload{
+		MessageID: f.uuid("message.id", m.GetId()),
+		ChatID:    f.uuid("message.chat_id", m.GetChatId()),
+		SenderID:  f.uuid("message.sender.id", m.GetSender().GetId()),
+		CreatedAt: m.GetCreatedAt().AsTime(),
+	}
+	if m.ThreadRootId != nil {
+		rootID := f.uuid("message.thread_root_id", m.GetThreadRootId())
+		p.ThreadRootID = &rootID
+	}
+	if f.err != nil {
+		return models.MessageNewPayload{}, f.err
+	}
+
+	content, err := toMessageContent(m.GetContent())
+	if err != nil {
+		return models.MessageNewPayload{}, err
+	}
+	p.Content = content
+
+	return p, nil
+}
+
+func toMessageUpdatedPayload(u *chatv1.MessageUpdated) (models.MessageUpdatedPayload, error) {
+	var f eventFields
+	p := models.MessageUpdatedPayload{
+		MessageID: f.uuid("message_id", u.GetMessageId()),
+		ChatID:    f.uuid("chat_id", u.GetChatId()),
+		UpdatedAt: u.GetUpdatedAt().AsTime(),
+	}
+	if f.err != nil {
+		return models.MessageUpdatedPayload{}, f.err
+	}
+
+	content, err := toMessageContent(u.GetNewContent())
+	if err != nil {
+		return models.MessageUpdatedPayload{}, err
+	}
+	p.NewContent = content
+
+	return p, nil
+}
+
+func toMessageDeletedPayload(d *chatv1.MessageDeleted) (models.MessageDeletedPayload, error) {
+	var f eventFields
+	p := models.MessageDeletedPayload{
+		MessageID: f.uuid("message_id", d.GetMessageId()),
+		ChatID:    f.uuid("chat_id", d.GetChatId()),
+		DeletedAt: d.GetDeletedAt().AsTime(),
+	}
+
+	return p, f.err
+}
+
+func toTypingIndicatorPayload(t *chatv1.TypingIndicator) (models.TypingIndicatorPayload, error) {
+	var f eventFields
+	p := models.TypingIndicatorPayload{
+		ChatID:   f.uuid("chat_id", t.GetChatId()),
+		UserID:   f.uuid("user.id", t.GetUser().GetId()),
+		IsTyping: t.GetIsTyping(),
+	}
+
+	return p, f.err
+}
+
+func toReadReceiptPayload(r *chatv1.ReadReceipt) (models.ReadReceiptPayload, error) {
+	var f eventFields
+	p := models.ReadReceiptPayload{
+		ChatID:    f.uuid("chat_id", r.GetChatId()),
+		UserID:    f.uuid("user_id", r.GetUserId()),
+		MessageID: f.uuid("message_id", r.GetMessageId()),
+		ReadAt:    r.GetReadAt().AsTime(),
+	}
+
+	return p, f.err
+}
+
+func toReactionUpdatedPayload(r *chatv1.ReactionUpdated) (models.ReactionUpdatedPayload, error) {
+	var f eventFields
+	p := models.ReactionUpdatedPayload{
+		MessageID: f.uuid("message_id", r.GetMessageId()),
+		ChatID:    f.uuid("chat_id", r.GetChatId()),
+		UserID:    f.uuid("user_id", r.GetUserId()),
+		Emoji:     r.GetEmoji(),
+		Added:     r.GetAdded(),
+		Count:     r.GetCount(),
+		UpdatedAt: r.GetUpdatedAt().AsTime(),
+	}
+
+	return p, f.err
+}
+
+func toThreadUpdatedPayload(t *chatv1.ThreadUpdated) (models.ThreadUpdatedPayload, error) {
+	var f eventFields
+	p := models.ThreadUpdatedPayload{
+		ChatID:        f.uuid("chat_id", t.GetChatId()),
+		RootMessageID: f.uuid("root_message_id", t.GetRootMessageId()),
+		ReplyCount:    t.GetReplyCount(),
+		LastReplyID:   f.uuid("last_reply_id", t.GetLastReplyId()),
+		LastReplyAt:   t.GetLastReplyAt().AsTime(),
+	}
+
+	return p, f.err
+}
+
+func toProtoSnapshot(p models.SnapshotPayload) *chatv1.Snapshot {
+	chats := make([]*chatv1.ChatSnapshot, 0, len(p.Chats))
+	for _, c := range p.Chats {
+		chat := &chatv1.ChatSnapshot{
+			Chat:        toProtoChat(c.Chat),
+			UnreadCount: c.UnreadCount,
+			ReadStates:  make([]*chatv1.MemberReadState, 0, len(c.ReadStates)),
+			UpdatedAt:   timestamppb.New(c.LastActivityAt),
+		}
+		if c.LastMessageID != uuid.Nil {
+			chat.LastMessageId = c.LastMessageID.String()
+		}
+		for _, rs := range c.ReadStates {
+			chat.ReadStates = append(chat.ReadStates, toProtoReadState(rs))
+		}
+		chats = append(chats, chat)
+	}
+
+	return &chatv1.Snapshot{Chats: chats}
+}
+
+func toSnapshotPayload(s *chatv1.Snapshot) (models.SnapshotPayload, error) {
+	var f eventFields
+	chats := make([]models.ChatSnapshot, 0, len(s.GetChats()))
+	for _, c := range s.GetChats() {
+		chat := models.ChatSnapshot{
+			Chat: models.Chat{
+				ID:        f.uuid("chat.id", c.GetChat().GetId()),
+				Name:      c.GetChat().GetName(),
+				Type:      models.ChatType(c.GetChat().GetType()),
+				CreatedAt: c.GetChat().GetCreatedAt().AsTime(),
+				UpdatedAt: c.GetChat().GetUpdatedAt().AsTime(),
+			},
+			UnreadCount:    c.GetUnreadCount(),
+			LastActivityAt: c.GetUpdatedAt().AsTime(),
+		}
+		if c.GetLastMessageId() != "" {
+			chat.LastMessageID = f.uuid("last_message_id", c.GetLastMessageId())
+		}
+		for _, m := range c.GetChat().GetMembers() {
+			chat.Chat.Members = append(chat.Chat.Members, models.ChatMember{
+				ChatID:   f.uuid("member.chat_id", m.GetChatId()),
+				UserID:   f.uuid("member.user_id", m.GetUserId()),
+				Role:     models.MemberRole(m.GetRole()),
+				JoinedAt: m.GetJoinedAt().AsTime(),
+			})
+		}
+		for _, rs := range c.GetReadStates() {
+			state := models.ReadState{UserID: f.uuid("read_state.user_id", rs.GetUserId())}
+			if rs.GetLastReadMessageId() != "" {
+				state.LastReadMessageID = f.uuid("read_state.last_read_message_id", rs.GetLastReadMessageId())
+				state.ReadAt = rs.GetReadAt().AsTime()
+			}
+			chat.ReadStates = append(chat.ReadStates, state)
+		}
+		chats = append(chats, chat)
+	}
+	if f.err != nil {
+		return models.SnapshotPayload{}, f.err
+	}
+
+	return models.SnapshotPayload{Chats: chats}, nil
+}
+
+// payloadChatID returns the chat an event payload belongs to.
+func payloadChatID(payload any) (uuid.UUID, bool) {
+	switch p := payload.(type) {
+	case models.MessageNewPayload:
+		return p.ChatID, true
+	case models.MessageUpdatedPayload:
+		return p.ChatID, true
+	case models.MessageDeletedPayload:
+		return p.ChatID, true
+	case models.TypingIndicatorPayload:
+		return p.ChatID, true
+	case models.ReadReceiptPayload:
+		return p.ChatID, true
+	case models.ReactionUpdatedPayload:
+		return p.ChatID, true
+	case models.ThreadUpdatedPayload:
+		return p.ChatID, true
+	default:
+		return uuid.Nil, false
+	}
+}
+
+// eventFields parses the IDs of an event message and keeps the first error.
+type eventFields struct {
+	err error
+}
+
+func (f *eventFields) uuid(field, value string) uuid.UUID {
+	if f.err != nil {
+		return uuid.Nil
+	}
+
+	id, err := parseEventUUID(field, value)
+	f.err = err
+
+	return id
+}
+
+func parseEventUUID(field, value string) (uuid.UUID, error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid event %s: %w", field, err)
+	}
+
+	return id, nil
+}
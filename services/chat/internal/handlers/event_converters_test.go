@@ -0,0 +1,136 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestToProtoEvent_RoundTrip(t *testing.T) {
+	chatID := uuid.New()
+	replyTo := uuid.New()
+	reader := uuid.New()
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	content := models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi"), ReplyToMessageID: &replyTo}
+
+	payloads := map[models.EventType]any{
+		models.EventTypeMessageNew: models.MessageNewPayload{
+			MessageID:    uuid.New(),
+			ChatID:       chatID,
+			SenderID:     uuid.New(),
+			Content:      content,
+			CreatedAt:    at,
+			ThreadRootID: &replyTo,
+		},
+		models.EventTypeMessageUpdated: models.MessageUpdatedPayload{
+			MessageID:  uuid.New(),
+			ChatID:     chatID,
+			NewContent: content,
+			UpdatedAt:  at,
+		},
+		models.EventTypeMessageDeleted: models.MessageDeletedPayload{MessageID: uuid.New(), ChatID: chatID, DeletedAt: at},
+		models.EventTypeTyping:         models.TypingIndicatorPayload{ChatID: chatID, UserID: uuid.New(), IsTyping: true},
+		models.EventTypeReadReceipt: models.ReadReceiptPayload{
+			ChatID:    chatID,
+			UserID:    uuid.New(),
+			MessageID: uuid.New(),
+			ReadAt:    at,
+		},
+		models.EventTypeThreadUpdated: models.ThreadUpdatedPayload{
+			ChatID:        chatID,
+			RootMessageID: replyTo,
+			ReplyCount:    3,
+			LastReplyID:   uuid.New(),
+			LastReplyAt:   at,
+		},
+		models.EventTypeReactionUpdated: models.ReactionUpdatedPayload{
+			MessageID: uuid.New(),
+			ChatID:    chatID,
+			UserID:    reader,
+			Emoji:     "👍",
+			Added:     true,
+			Count:     2,
+			UpdatedAt: at,
+		},
+		models.EventTypeSystem: models.SystemNotificationPayload{Text: "hello", Level: models.SystemNotificationLevelInfo, ResyncRequired: true},
+		models.EventTypeSnapshot: models.SnapshotPayload{Chats: []models.ChatSnapshot{{
+			Chat: models.Chat{
+				ID:        chatID,
+				Name:      "team",
+				Type:      models.ChatTypeGroup,
+				Members:   []models.ChatMember{{ChatID: chatID, UserID: reader, Role: models.MemberRoleOwner, JoinedAt: at}},
+				CreatedAt: at,
+				UpdatedAt: at,
+			},
+			LastMessageID:  replyTo,
+			UnreadCount:    3,
+			ReadStates:     []models.ReadState{{UserID: reader, LastReadMessageID: replyTo, ReadAt: at}},
+			LastActivityAt: at,
+		}}},
+	}
+
+	for eventType, payload := range payloads {
+		t.Run(string(eventType), func(t *testing.T) {
+			event := models.Event{ID: uuid.Must(uuid.NewV7()), Type: eventType, Payload: payload}
+			if eventType != models.EventTypeSystem && eventType != models.EventTypeSnapshot {
+				event.ChatID = &chatID
+			}
+
+			resp, err := toProtoEvent(event)
+			require.NoError(t, err)
+			require.NotNil(t, resp.GetPayload())
+
+			if eventType.IsEphemeral() {
+				assert.Empty(t, resp.GetId())
+				event.ID = uuid.Nil
+			}
+
+			got, err := toEvent(resp)
+			require.NoError(t, err)
+			assert.Equal(t, event, got)
+		})
+	}
+}
+
+func TestToProtoEvent_AttachmentContents(t *testing.T) {
+	chatID := uuid.New()
+	attachmentID := uuid.New()
+
+	contentTypes := map[string]models.ContentType{"image": models.ContentTypeImage, "file": models.ContentTypeFile, "voice": models.ContentTypeVoice}
+
+	for name, contentType := range contentTypes {
+		t.Run(name, func(t *testing.T) {
+			event := models.Event{
+				ID:     uuid.Must(uuid.NewV7()),
+				ChatID: &chatID,
+				Type:   models.EventTypeMessageUpdated,
+				Payload: models.MessageUpdatedPayload{
+					MessageID:  uuid.New(),
+					ChatID:     chatID,
+					NewContent: models.MessageContent{Type: contentType, Ciphertext: []byte("key"), AttachmentID: &attachmentID},
+					UpdatedAt:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			}
+
+			resp, err := toProtoEvent(event)
+			require.NoError(t, err)
+
+			got, err := toEvent(resp)
+			require.NoError(t, err)
+			assert.Equal(t, event, got)
+		})
+	}
+}
+
+func TestToProtoEvent_Rejects(t *testing.T) {
+	_, err := toProtoEvent(models.Event{ID: uuid.New(), Type: "UNKNOWN"})
+	assert.ErrorIs(t, err, models.ErrUnknownEventType)
+
+	_, err = toProtoEvent(models.Event{ID: uuid.New(), Type: models.EventTypeMessageNew, Payload: models.Message{}})
+	assert.ErrorIs(t, err, models.ErrPayloadMismatch)
+}
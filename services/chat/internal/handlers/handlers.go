@@ -2,6 +2,10 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
 
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
@@ -12,22 +16,57 @@ import (
 	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
 )
 
+//go:generate mockgen -source=handlers.go -destination=mocks/mock_chat_service.go -package=mocks
+
 type chatService interface {
-	Subscribe(ctx context.Context, req models.SubscribeRequest) (<-chan models.Event, error)
+	Subscribe(ctx context.Context, req models.SubscribeRequest, send func(models.Event) error) error
 	SendMessage(ctx context.Context, req models.SendMessageRequest) (models.SendMessageResponse, error)
+	EditMessage(ctx context.Context, req models.EditMessageRequest) (models.EditMessageResponse, error)
+	DeleteMessage(ctx context.Context, req models.DeleteMessageRequest) error
+	SetTyping(ctx context.Context, req models.SetTypingRequest) error
+	MarkRead(ctx context.Context, req models.MarkReadRequest) (models.MarkReadResponse, error)
+	GetReadState(ctx context.Context, req models.GetReadStateRequest) (models.GetReadStateResponse, error)
+	AddReaction(ctx context.Context, req models.AddReactionRequest) (models.AddReactionResponse, error)
+	RemoveReaction(ctx context.Context, req models.RemoveReactionRequest) (models.RemoveReactionResponse, error)
 	GetHistory(ctx context.Context, req models.GetHistoryRequest) (models.GetHistoryResponse, error)
+	GetThread(ctx context.Context, req models.GetThreadRequest) (models.GetThreadResponse, error)
+	UploadAttachment(
+		ctx context.Context,
+		req models.UploadAttachmentRequest,
+		data io.Reader,
+	) (models.UploadAttachmentResponse, error)
+	DownloadAttachment(ctx context.Context, req models.DownloadAttachmentRequest) (models.DownloadAttachmentResponse, error)
 	CreateChat(ctx context.Context, req models.CreateChatRequest) (models.CreateChatResponse, error)
-	GetChat(ctx context.Context, chatID models.GetChatRequest) (models.GetChatResponse, error)
-	ListChat(ctx context.Context, req models.ListChatsRequest) (models.ListChatsResponse, error)
+	ListChats(ctx context.Context, req models.ListChatsRequest) (models.ListChatsResponse, error)
+	AddMembers(ctx context.Context, req models.AddMembersRequest) (models.AddMembersResponse, error)
+	RemoveMember(ctx context.Context, req models.RemoveMemberRequest) error
+	LeaveChat(ctx context.Context, req models.LeaveChatRequest) error
+	UpdateMemberRole(ctx context.Context, req models.UpdateMemberRoleRequest) (models.UpdateMemberRoleResponse, error)
+	TransferOwnership(ctx context.Context, req models.TransferOwnershipRequest) error
 }
 
 type Handlers struct {
 	chatv1.UnimplementedChatServiceServer
-	service chatService
+	service  chatService
+	logger   *slog.Logger
+	draining chan struct{}
+	drain    sync.Once
+}
+
+func New(service chatService, logger *slog.Logger) *Handlers {
+	return &Handlers{
+		service:  service,
+		logger:   logger.With("layer", "handlers"),
+		draining: make(chan struct{}),
+	}
 }
 
-func New(service chatService) *Handlers {
-	return &Handlers{service: service}
+// Drain ends every open Connect stream with Unavailable so that clients
+// reconnect elsewhere with their last event ID.
+func (h *Handlers) Drain() {
+	h.drain.Do(func() {
+		close(h.draining)
+	})
 }
 
 func (h *Handlers) Connect(
@@ -36,7 +75,10 @@ func (h *Handlers) Connect(
 ) error {
 	ctx := stream.Context()
 
-	userID := interceptors.UserIDFromContext(ctx)
+	handle := models.NewSubscriptionHandle(
+		interceptors.UserIDFromContext(ctx),
+		interceptors.SessionIDFromContext(ctx),
+	)
 
 	var lastEventID uuid.UUID
 	var err error
@@ -47,26 +89,473 @@ func (h *Handlers) Connect(
 		}
 	}
 
-	eventChan, err := h.service.Subscribe(ctx, toConnectRequest(userID, lastEventID))
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	go func() {
+		select {
+		case <-h.draining:
+			cancel(errDraining)
+		case <-ctx.Done():
+		}
+	}()
+
+	err = h.service.Subscribe(ctx, toConnectRequest(handle, lastEventID), func(event models.Event) error {
+		resp, err := toProtoEvent(event)
+		if err != nil {
+			// The event would be replayed again on every reconnect, so it is
+			// skipped instead of ending the stream.
+			h.logger.Error("convert event",
+				slog.String("event_id", event.ID.String()),
+				slog.String("error", err.Error()),
+			)
+			return nil
+		}
+
+		if err := stream.Send(resp); err != nil {
+			return status.Errorf(codes.Unavailable, "failed to send event: %v", err)
+		}
+
+		return nil
+	})
+
+	return toStreamError(stream.Context(), err)
+}
+
+func (h *Handlers) SendMessage(
+	ctx context.Context,
+	req *chatv1.SendMessageRequest,
+) (*chatv1.SendMessageResponse, error) {
+	sendReq, err := toSendMessageRequest(req, interceptors.UserIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.SendMessage(ctx, sendReq)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoSendMessageResponse(resp), nil
+}
+
+func (h *Handlers) EditMessage(
+	ctx context.Context,
+	req *chatv1.EditMessageRequest,
+) (*chatv1.EditMessageResponse, error) {
+	editReq, err := toEditMessageRequest(req, interceptors.UserIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.EditMessage(ctx, editReq)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.EditMessageResponse{Message: toProtoMessage(resp.Message)}, nil
+}
+
+func (h *Handlers) DeleteMessage(
+	ctx context.Context,
+	req *chatv1.DeleteMessageRequest,
+) (*chatv1.DeleteMessageResponse, error) {
+	messageID, err := toMessageID(req.GetMessageId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.DeleteMessage(ctx, models.DeleteMessageRequest{
+		MessageID: messageID,
+		UserID:    interceptors.UserIDFromContext(ctx),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.DeleteMessageResponse{}, nil
+}
+
+func (h *Handlers) SetTyping(
+	ctx context.Context,
+	req *chatv1.SetTypingRequest,
+) (*chatv1.SetTypingResponse, error) {
+	chatID, err := toChatID(req.GetChatId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.SetTyping(ctx, models.SetTypingRequest{
+		ChatID:   chatID,
+		UserID:   interceptors.UserIDFromContext(ctx),
+		IsTyping: req.GetIsTyping(),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.SetTypingResponse{}, nil
+}
+
+func (h *Handlers) MarkRead(
+	ctx context.Context,
+	req *chatv1.MarkReadRequest,
+) (*chatv1.MarkReadResponse, error) {
+	chatID, err := toChatID(req.GetChatId())
+	if err != nil {
+		return nil, err
+	}
+
+	messageID, err := toMessageID(req.GetMessageId())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.MarkRead(ctx, models.MarkReadRequest{
+		ChatID:    chatID,
+		UserID:    interceptors.UserIDFromContext(ctx),
+		MessageID: messageID,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.MarkReadResponse{State: toProtoReadState(resp.State)}, nil
+}
+
+func (h *Handlers) GetReadState(
+	ctx context.Context,
+	req *chatv1.GetReadStateRequest,
+) (*chatv1.GetReadStateResponse, error) {
+	chatID, err := toChatID(req.GetChatId())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.GetReadState(ctx, models.GetReadStateRequest{
+		ChatID: chatID,
+		UserID: interceptors.UserIDFromContext(ctx),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	members := make([]*chatv1.MemberReadState, 0, len(resp.Members))
+	for _, state := range resp.Members {
+		members = append(members, toProtoReadState(state))
+	}
+
+	return &chatv1.GetReadStateResponse{Members: members}, nil
+}
+
+func (h *Handlers) AddReaction(
+	ctx context.Context,
+	req *chatv1.AddReactionRequest,
+) (*chatv1.AddReactionResponse, error) {
+	messageID, emoji, err := toReactionTarget(req.GetMessageId(), req.GetEmoji())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.AddReaction(ctx, models.AddReactionRequest{
+		MessageID: messageID,
+		UserID:    interceptors.UserIDFromContext(ctx),
+		Emoji:     emoji,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.AddReactionResponse{Reaction: toProtoReactionSummary(resp.Reaction)}, nil
+}
+
+func (h *Handlers) RemoveReaction(
+	ctx context.Context,
+	req *chatv1.RemoveReactionRequest,
+) (*chatv1.RemoveReactionResponse, error) {
+	messageID, emoji, err := toReactionTarget(req.GetMessageId(), req.GetEmoji())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.RemoveReaction(ctx, models.RemoveReactionRequest{
+		MessageID: messageID,
+		UserID:    interceptors.UserIDFromContext(ctx),
+		Emoji:     emoji,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.RemoveReactionResponse{Reaction: toProtoReactionSummary(resp.Reaction)}, nil
+}
+
+func (h *Handlers) GetHistory(
+	ctx context.Context,
+	req *chatv1.GetHistoryRequest,
+) (*chatv1.GetHistoryResponse, error) {
+	historyReq, err := toGetHistoryRequest(req, interceptors.UserIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.GetHistory(ctx, historyReq)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoGetHistoryResponse(resp), nil
+}
+
+func (h *Handlers) GetThread(
+	ctx context.Context,
+	req *chatv1.GetThreadRequest,
+) (*chatv1.GetThreadResponse, error) {
+	rootID, err := toMessageID(req.GetRootMessageId())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.GetThread(ctx, models.GetThreadRequest{
+		RootMessageID: rootID,
+		UserID:        interceptors.UserIDFromContext(ctx),
+		PageSize:      req.GetPageSize(),
+		Cursor:        req.GetCursor(),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.GetThreadResponse{
+		Root:       toProtoMessage(resp.Root),
+		Replies:    toProtoMessages(resp.Replies),
+		NextCursor: resp.NextCursor,
+	}, nil
+}
+
+func (h *Handlers) UploadAttachment(stream chatv1.ChatService_UploadAttachmentServer) error {
+	ctx := stream.Context()
+
+	first, err := stream.Recv()
+	if errors.Is(err, io.EOF) {
+		return status.Error(codes.InvalidArgument, "attachment info is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	req, err := toUploadAttachmentRequest(first.GetInfo(), interceptors.UserIDFromContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.UploadAttachment(ctx, req, &uploadReader{stream: stream})
+	if err != nil {
+		return toStreamError(ctx, err)
+	}
+
+	return stream.SendAndClose(&chatv1.UploadAttachmentResponse{Attachment: toProtoAttachment(resp.Attachment)})
+}
+
+func (h *Handlers) DownloadAttachment(
+	req *chatv1.DownloadAttachmentRequest,
+	stream chatv1.ChatService_DownloadAttachmentServer,
+) error {
+	ctx := stream.Context()
+
+	attachmentID, err := toAttachmentID(req.GetAttachmentId())
+	if err != nil {
+		return err
+	}
+
+	resp, err := h.service.DownloadAttachment(ctx, models.DownloadAttachmentRequest{
+		AttachmentID: attachmentID,
+		UserID:       interceptors.UserIDFromContext(ctx),
+		Offset:       req.GetOffset(),
+		Length:       req.GetLength(),
+	})
 	if err != nil {
-		return status.Errorf(codes.Internal, "failed to subscribe: %v", err)
+		return toGRPCError(err)
 	}
+	defer resp.Data.Close()
 
+	offset := resp.Offset
 	for {
-		select {
-		case <-ctx.Done():
+		chunk := make([]byte, downloadChunkSize)
+		n, err := io.ReadFull(resp.Data, chunk)
+		if n > 0 {
+			sendErr := stream.Send(&chatv1.DownloadAttachmentResponse{
+				Chunk:  chunk[:n],
+				Offset: offset,
+				Size:   resp.Attachment.Size,
+			})
+			if sendErr != nil {
+				return status.Errorf(codes.Unavailable, "failed to send chunk: %v", sendErr)
+			}
+			offset += int64(n)
+		}
+
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
 			return nil
+		case err != nil:
+			return status.Errorf(codes.Internal, "failed to read attachment: %v", err)
+		}
+	}
+}
 
-		case event, ok := <-eventChan:
-			if !ok {
-				return nil
-			}
+// uploadReader reads the chunks of an upload stream as one stream of
+// bytes.
+type uploadReader struct {
+	stream chatv1.ChatService_UploadAttachmentServer
+	chunk  []byte
+}
 
-			resp := toProtoEvent(event)
+func (r *uploadReader) Read(p []byte) (int, error) {
+	for len(r.chunk) == 0 {
+		req, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
 
-			if err := stream.Send(resp); err != nil {
-				return status.Errorf(codes.Unavailable, "failed to send event: %v", err)
-			}
+		chunk, ok := req.GetPayload().(*chatv1.UploadAttachmentRequest_Chunk)
+		if !ok {
+			return 0, status.Error(codes.InvalidArgument, "attachment info must come only in the first request")
 		}
+		r.chunk = chunk.Chunk
+	}
+
+	n := copy(p, r.chunk)
+	r.chunk = r.chunk[n:]
+
+	return n, nil
+}
+
+func (h *Handlers) CreateChat(
+	ctx context.Context,
+	req *chatv1.CreateChatRequest,
+) (*chatv1.CreateChatResponse, error) {
+	createReq, err := toCreateChatRequest(req, interceptors.UserIDFromContext(ctx))
+	if err != nil {
+		return nil, err
 	}
+
+	resp, err := h.service.CreateChat(ctx, createReq)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.CreateChatResponse{Chat: toProtoChat(resp.Chat)}, nil
+}
+
+func (h *Handlers) ListChats(
+	ctx context.Context,
+	req *chatv1.ListChatsRequest,
+) (*chatv1.ListChatsResponse, error) {
+	resp, err := h.service.ListChats(ctx, toListChatsRequest(req, interceptors.UserIDFromContext(ctx)))
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return toProtoListChatsResponse(resp), nil
+}
+
+func (h *Handlers) AddMembers(
+	ctx context.Context,
+	req *chatv1.AddMembersRequest,
+) (*chatv1.AddMembersResponse, error) {
+	addReq, err := toAddMembersRequest(req, interceptors.UserIDFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.AddMembers(ctx, addReq)
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.AddMembersResponse{Members: toProtoChatMembers(resp.Members)}, nil
+}
+
+func (h *Handlers) RemoveMember(
+	ctx context.Context,
+	req *chatv1.RemoveMemberRequest,
+) (*chatv1.RemoveMemberResponse, error) {
+	chatID, memberID, err := toChatMember(req.GetChatId(), req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.RemoveMember(ctx, models.RemoveMemberRequest{
+		ChatID:   chatID,
+		UserID:   interceptors.UserIDFromContext(ctx),
+		MemberID: memberID,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.RemoveMemberResponse{}, nil
+}
+
+func (h *Handlers) LeaveChat(
+	ctx context.Context,
+	req *chatv1.LeaveChatRequest,
+) (*chatv1.LeaveChatResponse, error) {
+	chatID, err := toChatID(req.GetChatId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.LeaveChat(ctx, models.LeaveChatRequest{
+		ChatID: chatID,
+		UserID: interceptors.UserIDFromContext(ctx),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.LeaveChatResponse{}, nil
+}
+
+func (h *Handlers) UpdateMemberRole(
+	ctx context.Context,
+	req *chatv1.UpdateMemberRoleRequest,
+) (*chatv1.UpdateMemberRoleResponse, error) {
+	chatID, memberID, err := toChatMember(req.GetChatId(), req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := h.service.UpdateMemberRole(ctx, models.UpdateMemberRoleRequest{
+		ChatID:   chatID,
+		UserID:   interceptors.UserIDFromContext(ctx),
+		MemberID: memberID,
+		Role:     models.MemberRole(req.GetRole()),
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.UpdateMemberRoleResponse{Member: toProtoChatMember(resp.Member)}, nil
+}
+
+func (h *Handlers) TransferOwnership(
+	ctx context.Context,
+	req *chatv1.TransferOwnershipRequest,
+) (*chatv1.TransferOwnershipResponse, error) {
+	chatID, newOwnerID, err := toChatMember(req.GetChatId(), req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	err = h.service.TransferOwnership(ctx, models.TransferOwnershipRequest{
+		ChatID:     chatID,
+		UserID:     interceptors.UserIDFromContext(ctx),
+		NewOwnerID: newOwnerID,
+	})
+	if err != nil {
+		return nil, toGRPCError(err)
+	}
+
+	return &chatv1.TransferOwnershipResponse{}, nil
 }
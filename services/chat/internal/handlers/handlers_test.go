@@ -0,0 +1,1007 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	chatv1 "github.com/BeInBloom/grpc-chat/gen/go/chat/v1"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/handlers/mocks"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/interceptors"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+	chatservice "github.com/BeInBloom/grpc-chat/services/chat/internal/services/chat_service"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+type fakeConnectStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*chatv1.ConnectResponse
+}
+
+func (s *fakeConnectStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeConnectStream) Send(resp *chatv1.ConnectResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func TestHandlers_ConnectDrain(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, sessionID := uuid.New(), uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID, SessionID: sessionID})
+
+	mockService.EXPECT().
+		Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, req models.SubscribeRequest, _ func(models.Event) error) error {
+			assert.Equal(t, userID, req.Handle.UserID)
+			assert.Equal(t, sessionID, req.Handle.SessionID)
+			assert.NotEqual(t, uuid.Nil, req.Handle.StreamID)
+			<-ctx.Done()
+			return &chatservice.StreamEndedError{Cause: context.Cause(ctx)}
+		})
+
+	handler.Drain()
+	handler.Drain()
+
+	err := handler.Connect(&chatv1.ConnectRequest{}, &fakeConnectStream{ctx: ctx})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+}
+
+func TestHandlers_ConnectSkipsUnconvertibleEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	bad := models.Event{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeMessageNew, Payload: models.TypingIndicatorPayload{}}
+	good := models.Event{
+		ID:      uuid.Must(uuid.NewV7()),
+		Type:    models.EventTypeSystem,
+		Payload: models.SystemNotificationPayload{Text: "hi"},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mockService.EXPECT().
+		Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.SubscribeRequest, send func(models.Event) error) error {
+			require.NoError(t, send(bad))
+			require.NoError(t, send(good))
+			cancel()
+			return &chatservice.StreamEndedError{Cause: context.Canceled, LastEventID: good.ID}
+		})
+
+	stream := &fakeConnectStream{ctx: ctx}
+	err := handler.Connect(&chatv1.ConnectRequest{}, stream)
+
+	assert.NoError(t, err)
+	require.Len(t, stream.sent, 1, "the event that cannot be converted is skipped")
+	assert.Equal(t, good.ID.String(), stream.sent[0].GetId())
+}
+
+func TestHandlers_ConnectInvalidLastEventID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := New(mocks.NewMockchatService(ctrl), testLogger)
+	lastEventID := "not-a-uuid"
+
+	err := handler.Connect(
+		&chatv1.ConnectRequest{LastEventId: &lastEventID},
+		&fakeConnectStream{ctx: context.Background()},
+	)
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHandlers_SendMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID, messageID := uuid.New(), uuid.New(), uuid.Must(uuid.NewV7())
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	createdAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	mockService.EXPECT().
+		SendMessage(ctx, models.SendMessageRequest{
+			ChatID:         chatID,
+			SenderID:       userID,
+			IdempotencyKey: "key-1",
+			Content:        models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+		}).
+		Return(models.SendMessageResponse{MessageID: messageID, CreatedAt: createdAt}, nil)
+
+	resp, err := handler.SendMessage(ctx, &chatv1.SendMessageRequest{
+		ChatId:         chatID.String(),
+		IdempotencyKey: "key-1",
+		Content:        textContent("hi"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, messageID.String(), resp.GetMessageId())
+	assert.Equal(t, createdAt, resp.GetCreatedAt().AsTime())
+}
+
+func TestHandlers_SendMessageInvalidArgument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := New(mocks.NewMockchatService(ctrl), testLogger)
+	chatID := uuid.NewString()
+	invalidRootID := "nope"
+
+	tests := map[string]*chatv1.SendMessageRequest{
+		"invalid chat id":        {ChatId: "nope", Content: textContent("hi")},
+		"missing content":        {ChatId: chatID},
+		"empty ciphertext":       {ChatId: chatID, Content: textContent("")},
+		"long idempotency key":   {ChatId: chatID, Content: textContent("hi"), IdempotencyKey: strings.Repeat("k", 129)},
+		"invalid thread root":    {ChatId: chatID, Content: textContent("hi"), ThreadRootId: &invalidRootID},
+		"invalid attachment id":  {ChatId: chatID, Content: imageContent("nope", "key")},
+		"empty image ciphertext": {ChatId: chatID, Content: imageContent(uuid.NewString(), "")},
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := handler.SendMessage(context.Background(), req)
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestHandlers_SendMessageNotMember(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	mockService.EXPECT().
+		SendMessage(gomock.Any(), gomock.Any()).
+		Return(models.SendMessageResponse{}, chatservice.ErrNotMember)
+
+	_, err := handler.SendMessage(context.Background(), &chatv1.SendMessageRequest{
+		ChatId:  uuid.NewString(),
+		Content: textContent("hi"),
+	})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func textContent(ciphertext string) *chatv1.MessageContent {
+	return &chatv1.MessageContent{
+		Type: &chatv1.MessageContent_Text{
+			Text: &chatv1.TextContent{Ciphertext: []byte(ciphertext)},
+		},
+	}
+}
+
+func imageContent(attachmentID, ciphertext string) *chatv1.MessageContent {
+	return &chatv1.MessageContent{
+		Type: &chatv1.MessageContent_Image{
+			Image: &chatv1.ImageContent{AttachmentId: attachmentID, Ciphertext: []byte(ciphertext)},
+		},
+	}
+}
+
+func TestHandlers_SendMessageWithAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID, attachmentID := uuid.New(), uuid.New(), uuid.Must(uuid.NewV7())
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+
+	mockService.EXPECT().
+		SendMessage(ctx, models.SendMessageRequest{
+			ChatID:   chatID,
+			SenderID: userID,
+			Content: models.MessageContent{
+				Type:         models.ContentTypeImage,
+				Ciphertext:   []byte("key"),
+				AttachmentID: &attachmentID,
+			},
+		}).
+		Return(models.SendMessageResponse{MessageID: uuid.Must(uuid.NewV7())}, nil)
+
+	_, err := handler.SendMessage(ctx, &chatv1.SendMessageRequest{
+		ChatId:  chatID.String(),
+		Content: imageContent(attachmentID.String(), "key"),
+	})
+
+	require.NoError(t, err)
+}
+
+func TestHandlers_GetHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID := uuid.New(), uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	message := models.Message{ID: uuid.Must(uuid.NewV7()), ChatID: chatID, SenderID: userID}
+
+	mockService.EXPECT().
+		GetHistory(ctx, models.GetHistoryRequest{ChatID: chatID, UserID: userID, PageSize: 10, Cursor: "cursor"}).
+		Return(models.GetHistoryResponse{Messages: []models.Message{message}, NextCursor: "next"}, nil)
+
+	resp, err := handler.GetHistory(ctx, &chatv1.GetHistoryRequest{
+		ChatId:   chatID.String(),
+		PageSize: 10,
+		Cursor:   "cursor",
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.GetMessages(), 1)
+	assert.Equal(t, message.ID.String(), resp.GetMessages()[0].GetId())
+	assert.Equal(t, "next", resp.GetNextCursor())
+}
+
+func TestHandlers_GetHistoryInvalidCursor(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	mockService.EXPECT().
+		GetHistory(gomock.Any(), gomock.Any()).
+		Return(models.GetHistoryResponse{}, cursor.ErrInvalidCursor)
+
+	_, err := handler.GetHistory(context.Background(), &chatv1.GetHistoryRequest{
+		ChatId: uuid.NewString(),
+		Cursor: "forged",
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHandlers_SendMessageToThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID, rootID := uuid.New(), uuid.New(), uuid.Must(uuid.NewV7())
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	threadRootID := rootID.String()
+
+	mockService.EXPECT().
+		SendMessage(ctx, models.SendMessageRequest{
+			ChatID:       chatID,
+			SenderID:     userID,
+			Content:      models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+			ThreadRootID: &rootID,
+		}).
+		Return(models.SendMessageResponse{MessageID: uuid.Must(uuid.NewV7())}, nil)
+
+	_, err := handler.SendMessage(ctx, &chatv1.SendMessageRequest{
+		ChatId:       chatID.String(),
+		Content:      textContent("hi"),
+		ThreadRootId: &threadRootID,
+	})
+
+	require.NoError(t, err)
+}
+
+func TestHandlers_GetThread(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID := uuid.New(), uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	lastReplyAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	root := models.Message{
+		ID:          uuid.Must(uuid.NewV7()),
+		ChatID:      chatID,
+		SenderID:    userID,
+		ReplyCount:  1,
+		LastReplyAt: &lastReplyAt,
+	}
+	reply := models.Message{ID: uuid.Must(uuid.NewV7()), ChatID: chatID, SenderID: userID, ThreadRootID: &root.ID}
+
+	mockService.EXPECT().
+		GetThread(ctx, models.GetThreadRequest{RootMessageID: root.ID, UserID: userID, PageSize: 10, Cursor: "cursor"}).
+		Return(models.GetThreadResponse{Root: root, Replies: []models.Message{reply}, NextCursor: "next"}, nil)
+
+	resp, err := handler.GetThread(ctx, &chatv1.GetThreadRequest{
+		RootMessageId: root.ID.String(),
+		PageSize:      10,
+		Cursor:        "cursor",
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, root.ID.String(), resp.GetRoot().GetId())
+	assert.Equal(t, int32(1), resp.GetRoot().GetReplyCount())
+	assert.Equal(t, lastReplyAt, resp.GetRoot().GetLastReplyAt().AsTime())
+	require.Len(t, resp.GetReplies(), 1)
+	assert.Equal(t, root.ID.String(), resp.GetReplies()[0].GetThreadRootId())
+	assert.Equal(t, "next", resp.GetNextCursor())
+}
+
+func TestHandlers_GetThreadErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	_, err := handler.GetThread(context.Background(), &chatv1.GetThreadRequest{RootMessageId: "nope"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockService.EXPECT().
+		GetThread(gomock.Any(), gomock.Any()).
+		Return(models.GetThreadResponse{}, fmt.Errorf("load root: %w", chatservice.ErrInvalidThread))
+
+	_, err = handler.GetThread(context.Background(), &chatv1.GetThreadRequest{RootMessageId: uuid.NewString()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHandlers_CreateChat(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	ownerID, memberID := uuid.New(), uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: ownerID})
+	chat := models.Chat{
+		ID:   uuid.Must(uuid.NewV7()),
+		Name: "team",
+		Type: models.ChatTypeGroup,
+		Members: []models.ChatMember{
+			{UserID: ownerID, Role: models.MemberRoleOwner},
+			{UserID: memberID, Role: models.MemberRoleMember},
+		},
+	}
+
+	mockService.EXPECT().
+		CreateChat(ctx, models.CreateChatRequest{
+			OwnerID:   ownerID,
+			Name:      "team",
+			Type:      models.ChatTypeGroup,
+			MemberIDs: []uuid.UUID{memberID},
+		}).
+		Return(models.CreateChatResponse{Chat: chat}, nil)
+
+	resp, err := handler.CreateChat(ctx, &chatv1.CreateChatRequest{
+		Name:      "team",
+		Type:      chatv1.ChatType_CHAT_TYPE_GROUP,
+		MemberIds: []string{memberID.String()},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, chat.ID.String(), resp.GetChat().GetId())
+	require.Len(t, resp.GetChat().GetMembers(), 2)
+	assert.Equal(t, chatv1.MemberRole_MEMBER_ROLE_OWNER, resp.GetChat().GetMembers()[0].GetRole())
+}
+
+func TestHandlers_CreateChatErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	_, err := handler.CreateChat(context.Background(), &chatv1.CreateChatRequest{MemberIds: []string{"nope"}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockService.EXPECT().
+		CreateChat(gomock.Any(), gomock.Any()).
+		Return(models.CreateChatResponse{}, chatservice.ErrUserNotFound)
+
+	_, err = handler.CreateChat(context.Background(), &chatv1.CreateChatRequest{MemberIds: []string{uuid.NewString()}})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestHandlers_ListChats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID := uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	withMessage := models.ChatPreview{
+		ID:          uuid.New(),
+		Name:        "team",
+		Type:        models.ChatTypeGroup,
+		LastMessage: &models.Message{ID: uuid.Must(uuid.NewV7()), SenderID: uuid.New()},
+		UnreadCount: 3,
+	}
+	empty := models.ChatPreview{ID: uuid.New(), Type: models.ChatTypeDirect}
+
+	mockService.EXPECT().
+		ListChats(ctx, models.ListChatsRequest{UserID: userID, PageSize: 2, Cursor: "cursor"}).
+		Return(models.ListChatsResponse{Chats: []models.ChatPreview{withMessage, empty}, NextCursor: "next"}, nil)
+
+	resp, err := handler.ListChats(ctx, &chatv1.ListChatsRequest{PageSize: 2, Cursor: "cursor"})
+
+	require.NoError(t, err)
+	require.Len(t, resp.GetChats(), 2)
+	assert.Equal(t, withMessage.ID.String(), resp.GetChats()[0].GetId())
+	assert.Equal(t, int32(3), resp.GetChats()[0].GetUnreadCount())
+	assert.Equal(t, withMessage.LastMessage.ID.String(), resp.GetChats()[0].GetLastMessage().GetId())
+	assert.Nil(t, resp.GetChats()[1].GetLastMessage())
+	assert.Equal(t, "next", resp.GetNextCursor())
+}
+
+func TestHandlers_AddMembers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID, newcomer := uuid.New(), uuid.New(), uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	added := models.ChatMember{ChatID: chatID, UserID: newcomer, Role: models.MemberRoleMember}
+
+	mockService.EXPECT().
+		AddMembers(ctx, models.AddMembersRequest{ChatID: chatID, UserID: userID, MemberIDs: []uuid.UUID{newcomer}}).
+		Return(models.AddMembersResponse{Members: []models.ChatMember{added}}, nil)
+
+	resp, err := handler.AddMembers(ctx, &chatv1.AddMembersRequest{
+		ChatId:  chatID.String(),
+		UserIds: []string{newcomer.String()},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.GetMembers(), 1)
+	assert.Equal(t, newcomer.String(), resp.GetMembers()[0].GetUserId())
+	assert.Equal(t, chatv1.MemberRole_MEMBER_ROLE_MEMBER, resp.GetMembers()[0].GetRole())
+}
+
+func TestHandlers_MemberErrors(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"forbidden":        {err: chatservice.ErrForbidden, code: codes.PermissionDenied},
+		"unknown member":   {err: repository.ErrMemberNotFound, code: codes.NotFound},
+		"owner leaves":     {err: chatservice.ErrOwnerMustStay, code: codes.FailedPrecondition},
+		"owner role given": {err: chatservice.ErrInvalidRole, code: codes.InvalidArgument},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockchatService(ctrl)
+			handler := New(mockService, testLogger)
+
+			mockService.EXPECT().RemoveMember(gomock.Any(), gomock.Any()).Return(tt.err)
+
+			_, err := handler.RemoveMember(context.Background(), &chatv1.RemoveMemberRequest{
+				ChatId: uuid.NewString(),
+				UserId: uuid.NewString(),
+			})
+
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestHandlers_RemoveMemberInvalidUserID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := New(mocks.NewMockchatService(ctrl), testLogger)
+
+	_, err := handler.RemoveMember(context.Background(), &chatv1.RemoveMemberRequest{
+		ChatId: uuid.NewString(),
+		UserId: "bob",
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHandlers_EditMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, messageID := uuid.New(), uuid.Must(uuid.NewV7())
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	content := models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("edited")}
+	edited := models.Message{ID: messageID, SenderID: userID, Content: content}
+
+	mockService.EXPECT().
+		EditMessage(ctx, models.EditMessageRequest{MessageID: messageID, UserID: userID, Content: content}).
+		Return(models.EditMessageResponse{Message: edited}, nil)
+
+	resp, err := handler.EditMessage(ctx, &chatv1.EditMessageRequest{
+		MessageId: messageID.String(),
+		Content:   textContent("edited"),
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, messageID.String(), resp.GetMessage().GetId())
+	assert.Equal(t, []byte("edited"), resp.GetMessage().GetContent().GetText().GetCiphertext())
+}
+
+func TestHandlers_EditMessageErrors(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		code codes.Code
+	}{
+		"not the sender":    {err: chatservice.ErrForbidden, code: codes.PermissionDenied},
+		"unknown message":   {err: repository.ErrMessageNotFound, code: codes.NotFound},
+		"edit window ended": {err: chatservice.ErrEditWindowEnded, code: codes.FailedPrecondition},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockService := mocks.NewMockchatService(ctrl)
+			handler := New(mockService, testLogger)
+
+			mockService.EXPECT().EditMessage(gomock.Any(), gomock.Any()).Return(models.EditMessageResponse{}, tt.err)
+
+			_, err := handler.EditMessage(context.Background(), &chatv1.EditMessageRequest{
+				MessageId: uuid.NewString(),
+				Content:   textContent("x"),
+			})
+
+			assert.Equal(t, tt.code, status.Code(err))
+		})
+	}
+}
+
+func TestHandlers_DeleteMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, messageID := uuid.New(), uuid.Must(uuid.NewV7())
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+
+	mockService.EXPECT().
+		DeleteMessage(ctx, models.DeleteMessageRequest{MessageID: messageID, UserID: userID}).
+		Return(nil)
+
+	_, err := handler.DeleteMessage(ctx, &chatv1.DeleteMessageRequest{MessageId: messageID.String()})
+
+	require.NoError(t, err)
+}
+
+func TestHandlers_ConnectUnknownEvent(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	mockService.EXPECT().
+		Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.SubscribeRequest, send func(models.Event) error) error {
+			return &chatservice.StreamEndedError{Cause: send(models.Event{ID: uuid.Must(uuid.NewV7()), Type: "UNKNOWN"})}
+		})
+
+	stream := &fakeConnectStream{ctx: context.Background()}
+	err := handler.Connect(&chatv1.ConnectRequest{}, stream)
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Empty(t, stream.sent, "no event goes out without a payload")
+}
+
+func TestHandlers_ConnectEnd(t *testing.T) {
+	tests := map[string]struct {
+		cause error
+		code  codes.Code
+	}{
+		"slow client":      {cause: chatservice.ErrBufferOverflow, code: codes.ResourceExhausted},
+		"evicted":          {cause: chatservice.ErrBrokerSubClosed, code: codes.Unavailable},
+		"store failure":    {cause: errors.New("connection refused"), code: codes.Internal},
+		"client went away": {cause: context.Canceled, code: codes.OK},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockService := mocks.NewMockchatService(ctrl)
+			handler := New(mockService, testLogger)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			lastEventID := uuid.Must(uuid.NewV7())
+
+			mockService.EXPECT().
+				Subscribe(gomock.Any(), gomock.Any(), gomock.Any()).
+				DoAndReturn(func(context.Context, models.SubscribeRequest, func(models.Event) error) error {
+					if errors.Is(tt.cause, context.Canceled) {
+						cancel()
+					}
+					return &chatservice.StreamEndedError{Cause: tt.cause, LastEventID: lastEventID}
+				})
+
+			err := handler.Connect(&chatv1.ConnectRequest{}, &fakeConnectStream{ctx: ctx})
+
+			assert.Equal(t, tt.code, status.Code(err))
+			if tt.code == codes.ResourceExhausted {
+				details := status.Convert(err).Details()
+				require.Len(t, details, 1)
+				info, ok := details[0].(*errdetails.ErrorInfo)
+				require.True(t, ok)
+				assert.Equal(t, lastEventID.String(), info.GetMetadata()["last_event_id"])
+			}
+		})
+	}
+}
+
+func TestHandlers_SetTyping(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID := uuid.New(), uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+
+	mockService.EXPECT().
+		SetTyping(ctx, models.SetTypingRequest{ChatID: chatID, UserID: userID, IsTyping: true}).
+		Return(chatservice.ErrNotMember)
+
+	_, err := handler.SetTyping(ctx, &chatv1.SetTypingRequest{ChatId: chatID.String(), IsTyping: true})
+
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestHandlers_MarkRead(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID, messageID := uuid.New(), uuid.New(), uuid.Must(uuid.NewV7())
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+
+	mockService.EXPECT().
+		MarkRead(ctx, models.MarkReadRequest{ChatID: chatID, UserID: userID, MessageID: messageID}).
+		Return(models.MarkReadResponse{State: models.ReadState{UserID: userID, LastReadMessageID: messageID}}, nil)
+
+	resp, err := handler.MarkRead(ctx, &chatv1.MarkReadRequest{ChatId: chatID.String(), MessageId: messageID.String()})
+
+	require.NoError(t, err)
+	assert.Equal(t, messageID.String(), resp.GetState().GetLastReadMessageId())
+}
+
+func TestHandlers_AddReaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, messageID := uuid.New(), uuid.Must(uuid.NewV7())
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+
+	mockService.EXPECT().
+		AddReaction(ctx, models.AddReactionRequest{MessageID: messageID, UserID: userID, Emoji: "👍"}).
+		Return(models.AddReactionResponse{Reaction: models.ReactionSummary{Emoji: "👍", Count: 3, ReactedByMe: true}}, nil)
+
+	resp, err := handler.AddReaction(ctx, &chatv1.AddReactionRequest{MessageId: messageID.String(), Emoji: "👍"})
+
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), resp.GetReaction().GetCount())
+	assert.True(t, resp.GetReaction().GetReactedByMe())
+}
+
+func TestHandlers_ReactionRejects(t *testing.T) {
+	handler := New(mocks.NewMockchatService(gomock.NewController(t)), testLogger)
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: uuid.New()})
+	messageID := uuid.Must(uuid.NewV7()).String()
+
+	tests := map[string]*chatv1.AddReactionRequest{
+		"bad message id": {MessageId: "nope", Emoji: "👍"},
+		"no emoji":       {MessageId: messageID},
+		"long emoji":     {MessageId: messageID, Emoji: strings.Repeat("👍", 20)},
+		"invalid utf-8":  {MessageId: messageID, Emoji: "\xff"},
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := handler.AddReaction(ctx, req)
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+			_, err = handler.RemoveReaction(ctx, &chatv1.RemoveReactionRequest{MessageId: req.GetMessageId(), Emoji: req.GetEmoji()})
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestHandlers_AddReactionLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: uuid.New()})
+
+	mockService.EXPECT().AddReaction(ctx, gomock.Any()).Return(models.AddReactionResponse{}, chatservice.ErrTooManyReactions)
+
+	_, err := handler.AddReaction(ctx, &chatv1.AddReactionRequest{MessageId: uuid.NewString(), Emoji: "🦄"})
+
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
+
+func TestHandlers_GetReadState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID, other := uuid.New(), uuid.New(), uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	read := models.ReadState{UserID: userID, LastReadMessageID: uuid.Must(uuid.NewV7()), ReadAt: time.Now()}
+
+	mockService.EXPECT().
+		GetReadState(ctx, models.GetReadStateRequest{ChatID: chatID, UserID: userID}).
+		Return(models.GetReadStateResponse{Members: []models.ReadState{read, {UserID: other}}}, nil)
+
+	resp, err := handler.GetReadState(ctx, &chatv1.GetReadStateRequest{ChatId: chatID.String()})
+
+	require.NoError(t, err)
+	require.Len(t, resp.GetMembers(), 2)
+	assert.Equal(t, read.LastReadMessageID.String(), resp.GetMembers()[0].GetLastReadMessageId())
+	assert.Empty(t, resp.GetMembers()[1].GetLastReadMessageId())
+	assert.Nil(t, resp.GetMembers()[1].GetReadAt())
+}
+
+type fakeUploadStream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	requests []*chatv1.UploadAttachmentRequest
+	resp     *chatv1.UploadAttachmentResponse
+}
+
+func (s *fakeUploadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeUploadStream) Recv() (*chatv1.UploadAttachmentRequest, error) {
+	if len(s.requests) == 0 {
+		return nil, io.EOF
+	}
+	req := s.requests[0]
+	s.requests = s.requests[1:]
+
+	return req, nil
+}
+
+func (s *fakeUploadStream) SendAndClose(resp *chatv1.UploadAttachmentResponse) error {
+	s.resp = resp
+	return nil
+}
+
+func uploadInfo(chatID string, size int64) *chatv1.UploadAttachmentRequest {
+	return &chatv1.UploadAttachmentRequest{
+		Payload: &chatv1.UploadAttachmentRequest_Info{Info: &chatv1.AttachmentInfo{ChatId: chatID, Size: size}},
+	}
+}
+
+func uploadChunk(chunk string) *chatv1.UploadAttachmentRequest {
+	return &chatv1.UploadAttachmentRequest{Payload: &chatv1.UploadAttachmentRequest_Chunk{Chunk: []byte(chunk)}}
+}
+
+func TestHandlers_UploadAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID, chatID := uuid.New(), uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	attachment := models.Attachment{ID: uuid.Must(uuid.NewV7()), ChatID: chatID, UploaderID: userID, Size: 10}
+
+	mockService.EXPECT().
+		UploadAttachment(ctx, models.UploadAttachmentRequest{ChatID: chatID, UploaderID: userID, Size: 10}, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.UploadAttachmentRequest, data io.Reader) (models.UploadAttachmentResponse, error) {
+			read, err := io.ReadAll(data)
+			require.NoError(t, err)
+			assert.Equal(t, "0123456789", string(read))
+			return models.UploadAttachmentResponse{Attachment: attachment}, nil
+		})
+
+	stream := &fakeUploadStream{
+		ctx:      ctx,
+		requests: []*chatv1.UploadAttachmentRequest{uploadInfo(chatID.String(), 10), uploadChunk("01234"), uploadChunk("56789")},
+	}
+	err := handler.UploadAttachment(stream)
+
+	require.NoError(t, err)
+	assert.Equal(t, attachment.ID.String(), stream.resp.GetAttachment().GetId())
+	assert.Equal(t, int64(10), stream.resp.GetAttachment().GetSize())
+}
+
+func TestHandlers_UploadAttachmentInvalidArgument(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	handler := New(mocks.NewMockchatService(ctrl), testLogger)
+	chatID := uuid.NewString()
+
+	tests := map[string][]*chatv1.UploadAttachmentRequest{
+		"no requests":     nil,
+		"chunk first":     {uploadChunk("data")},
+		"invalid chat id": {uploadInfo("nope", 4)},
+		"empty size":      {uploadInfo(chatID, 0)},
+	}
+
+	for name, requests := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := handler.UploadAttachment(&fakeUploadStream{ctx: context.Background(), requests: requests})
+
+			assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		})
+	}
+}
+
+func TestHandlers_UploadAttachmentRepeatedInfo(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+	chatID := uuid.NewString()
+
+	mockService.EXPECT().
+		UploadAttachment(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ models.UploadAttachmentRequest, data io.Reader) (models.UploadAttachmentResponse, error) {
+			_, err := io.ReadAll(data)
+			return models.UploadAttachmentResponse{}, fmt.Errorf("store attachment: %w", err)
+		})
+
+	err := handler.UploadAttachment(&fakeUploadStream{
+		ctx:      context.Background(),
+		requests: []*chatv1.UploadAttachmentRequest{uploadInfo(chatID, 4), uploadInfo(chatID, 4)},
+	})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestHandlers_UploadAttachmentQuotaExceeded(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	mockService.EXPECT().
+		UploadAttachment(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(models.UploadAttachmentResponse{}, chatservice.ErrQuotaExceeded)
+
+	err := handler.UploadAttachment(&fakeUploadStream{
+		ctx:      context.Background(),
+		requests: []*chatv1.UploadAttachmentRequest{uploadInfo(uuid.NewString(), 4)},
+	})
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+type fakeDownloadStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*chatv1.DownloadAttachmentResponse
+}
+
+func (s *fakeDownloadStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeDownloadStream) Send(resp *chatv1.DownloadAttachmentResponse) error {
+	s.sent = append(s.sent, resp)
+	return nil
+}
+
+func TestHandlers_DownloadAttachment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	userID := uuid.New()
+	ctx := interceptors.WithIdentity(context.Background(), interceptors.Identity{UserID: userID})
+	attachment := models.Attachment{ID: uuid.Must(uuid.NewV7()), ChatID: uuid.New(), Size: 3 * downloadChunkSize}
+	data := strings.Repeat("x", downloadChunkSize+10)
+
+	mockService.EXPECT().
+		DownloadAttachment(ctx, models.DownloadAttachmentRequest{
+			AttachmentID: attachment.ID,
+			UserID:       userID,
+			Offset:       100,
+			Length:       int64(len(data)),
+		}).
+		Return(models.DownloadAttachmentResponse{
+			Attachment: attachment,
+			Offset:     100,
+			Data:       io.NopCloser(strings.NewReader(data)),
+		}, nil)
+
+	stream := &fakeDownloadStream{ctx: ctx}
+	err := handler.DownloadAttachment(&chatv1.DownloadAttachmentRequest{
+		AttachmentId: attachment.ID.String(),
+		Offset:       100,
+		Length:       int64(len(data)),
+	}, stream)
+
+	require.NoError(t, err)
+	require.Len(t, stream.sent, 2)
+	assert.Len(t, stream.sent[0].GetChunk(), downloadChunkSize)
+	assert.Equal(t, int64(100), stream.sent[0].GetOffset())
+	assert.Len(t, stream.sent[1].GetChunk(), 10)
+	assert.Equal(t, int64(100+downloadChunkSize), stream.sent[1].GetOffset())
+	assert.Equal(t, attachment.Size, stream.sent[1].GetSize())
+}
+
+func TestHandlers_DownloadAttachmentErrors(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockchatService(ctrl)
+	handler := New(mockService, testLogger)
+
+	err := handler.DownloadAttachment(&chatv1.DownloadAttachmentRequest{AttachmentId: "nope"}, &fakeDownloadStream{ctx: context.Background()})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	mockService.EXPECT().
+		DownloadAttachment(gomock.Any(), gomock.Any()).
+		Return(models.DownloadAttachmentResponse{}, chatservice.ErrInvalidRange)
+
+	err = handler.DownloadAttachment(
+		&chatv1.DownloadAttachmentRequest{AttachmentId: uuid.NewString(), Offset: 1 << 40},
+		&fakeDownloadStream{ctx: context.Background()},
+	)
+	assert.Equal(t, codes.OutOfRange, status.Code(err))
+}
@@ -0,0 +1,337 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: handlers.go
+//
+// Generated by this command:
+//
+//	mockgen -source=handlers.go -destination=mocks/mock_chat_service.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+
+	models "github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockchatService is a mock of chatService interface.
+type MockchatService struct {
+	ctrl     *gomock.Controller
+	recorder *MockchatServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockchatServiceMockRecorder is the mock recorder for MockchatService.
+type MockchatServiceMockRecorder struct {
+	mock *MockchatService
+}
+
+// NewMockchatService creates a new mock instance.
+func NewMockchatService(ctrl *gomock.Controller) *MockchatService {
+	mock := &MockchatService{ctrl: ctrl}
+	mock.recorder = &MockchatServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockchatService) EXPECT() *MockchatServiceMockRecorder {
+	return m.recorder
+}
+
+// AddMembers mocks base method.
+func (m *MockchatService) AddMembers(ctx context.Context, req models.AddMembersRequest) (models.AddMembersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMembers", ctx, req)
+	ret0, _ := ret[0].(models.AddMembersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddMembers indicates an expected call of AddMembers.
+func (mr *MockchatServiceMockRecorder) AddMembers(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMembers", reflect.TypeOf((*MockchatService)(nil).AddMembers), ctx, req)
+}
+
+// AddReaction mocks base method.
+func (m *MockchatService) AddReaction(ctx context.Context, req models.AddReactionRequest) (models.AddReactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddReaction", ctx, req)
+	ret0, _ := ret[0].(models.AddReactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddReaction indicates an expected call of AddReaction.
+func (mr *MockchatServiceMockRecorder) AddReaction(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddReaction", reflect.TypeOf((*MockchatService)(nil).AddReaction), ctx, req)
+}
+
+// CreateChat mocks base method.
+func (m *MockchatService) CreateChat(ctx context.Context, req models.CreateChatRequest) (models.CreateChatResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateChat", ctx, req)
+	ret0, _ := ret[0].(models.CreateChatResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateChat indicates an expected call of CreateChat.
+func (mr *MockchatServiceMockRecorder) CreateChat(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChat", reflect.TypeOf((*MockchatService)(nil).CreateChat), ctx, req)
+}
+
+// DeleteMessage mocks base method.
+func (m *MockchatService) DeleteMessage(ctx context.Context, req models.DeleteMessageRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessage", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteMessage indicates an expected call of DeleteMessage.
+func (mr *MockchatServiceMockRecorder) DeleteMessage(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessage", reflect.TypeOf((*MockchatService)(nil).DeleteMessage), ctx, req)
+}
+
+// DownloadAttachment mocks base method.
+func (m *MockchatService) DownloadAttachment(ctx context.Context, req models.DownloadAttachmentRequest) (models.DownloadAttachmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DownloadAttachment", ctx, req)
+	ret0, _ := ret[0].(models.DownloadAttachmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DownloadAttachment indicates an expected call of DownloadAttachment.
+func (mr *MockchatServiceMockRecorder) DownloadAttachment(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DownloadAttachment", reflect.TypeOf((*MockchatService)(nil).DownloadAttachment), ctx, req)
+}
+
+// EditMessage mocks base method.
+func (m *MockchatService) EditMessage(ctx context.Context, req models.EditMessageRequest) (models.EditMessageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EditMessage", ctx, req)
+	ret0, _ := ret[0].(models.EditMessageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EditMessage indicates an expected call of EditMessage.
+func (mr *MockchatServiceMockRecorder) EditMessage(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EditMessage", reflect.TypeOf((*MockchatService)(nil).EditMessage), ctx, req)
+}
+
+// GetHistory mocks base method.
+func (m *MockchatService) GetHistory(ctx context.Context, req models.GetHistoryRequest) (models.GetHistoryResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHistory", ctx, req)
+	ret0, _ := ret[0].(models.GetHistoryResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHistory indicates an expected call of GetHistory.
+func (mr *MockchatServiceMockRecorder) GetHistory(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHistory", reflect.TypeOf((*MockchatService)(nil).GetHistory), ctx, req)
+}
+
+// GetReadState mocks base method.
+func (m *MockchatService) GetReadState(ctx context.Context, req models.GetReadStateRequest) (models.GetReadStateResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReadState", ctx, req)
+	ret0, _ := ret[0].(models.GetReadStateResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReadState indicates an expected call of GetReadState.
+func (mr *MockchatServiceMockRecorder) GetReadState(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReadState", reflect.TypeOf((*MockchatService)(nil).GetReadState), ctx, req)
+}
+
+// GetThread mocks base method.
+func (m *MockchatService) GetThread(ctx context.Context, req models.GetThreadRequest) (models.GetThreadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetThread", ctx, req)
+	ret0, _ := ret[0].(models.GetThreadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetThread indicates an expected call of GetThread.
+func (mr *MockchatServiceMockRecorder) GetThread(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetThread", reflect.TypeOf((*MockchatService)(nil).GetThread), ctx, req)
+}
+
+// LeaveChat mocks base method.
+func (m *MockchatService) LeaveChat(ctx context.Context, req models.LeaveChatRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LeaveChat", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LeaveChat indicates an expected call of LeaveChat.
+func (mr *MockchatServiceMockRecorder) LeaveChat(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LeaveChat", reflect.TypeOf((*MockchatService)(nil).LeaveChat), ctx, req)
+}
+
+// ListChats mocks base method.
+func (m *MockchatService) ListChats(ctx context.Context, req models.ListChatsRequest) (models.ListChatsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", ctx, req)
+	ret0, _ := ret[0].(models.ListChatsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockchatServiceMockRecorder) ListChats(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockchatService)(nil).ListChats), ctx, req)
+}
+
+// MarkRead mocks base method.
+func (m *MockchatService) MarkRead(ctx context.Context, req models.MarkReadRequest) (models.MarkReadResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkRead", ctx, req)
+	ret0, _ := ret[0].(models.MarkReadResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkRead indicates an expected call of MarkRead.
+func (mr *MockchatServiceMockRecorder) MarkRead(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkRead", reflect.TypeOf((*MockchatService)(nil).MarkRead), ctx, req)
+}
+
+// RemoveMember mocks base method.
+func (m *MockchatService) RemoveMember(ctx context.Context, req models.RemoveMemberRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveMember", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveMember indicates an expected call of RemoveMember.
+func (mr *MockchatServiceMockRecorder) RemoveMember(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveMember", reflect.TypeOf((*MockchatService)(nil).RemoveMember), ctx, req)
+}
+
+// RemoveReaction mocks base method.
+func (m *MockchatService) RemoveReaction(ctx context.Context, req models.RemoveReactionRequest) (models.RemoveReactionResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveReaction", ctx, req)
+	ret0, _ := ret[0].(models.RemoveReactionResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveReaction indicates an expected call of RemoveReaction.
+func (mr *MockchatServiceMockRecorder) RemoveReaction(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveReaction", reflect.TypeOf((*MockchatService)(nil).RemoveReaction), ctx, req)
+}
+
+// SendMessage mocks base method.
+func (m *MockchatService) SendMessage(ctx context.Context, req models.SendMessageRequest) (models.SendMessageResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendMessage", ctx, req)
+	ret0, _ := ret[0].(models.SendMessageResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendMessage indicates an expected call of SendMessage.
+func (mr *MockchatServiceMockRecorder) SendMessage(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockchatService)(nil).SendMessage), ctx, req)
+}
+
+// SetTyping mocks base method.
+func (m *MockchatService) SetTyping(ctx context.Context, req models.SetTypingRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetTyping", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetTyping indicates an expected call of SetTyping.
+func (mr *MockchatServiceMockRecorder) SetTyping(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetTyping", reflect.TypeOf((*MockchatService)(nil).SetTyping), ctx, req)
+}
+
+// Subscribe mocks base method.
+func (m *MockchatService) Subscribe(ctx context.Context, req models.SubscribeRequest, send func(models.Event) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, req, send)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockchatServiceMockRecorder) Subscribe(ctx, req, send any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockchatService)(nil).Subscribe), ctx, req, send)
+}
+
+// TransferOwnership mocks base method.
+func (m *MockchatService) TransferOwnership(ctx context.Context, req models.TransferOwnershipRequest) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferOwnership", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferOwnership indicates an expected call of TransferOwnership.
+func (mr *MockchatServiceMockRecorder) TransferOwnership(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferOwnership", reflect.TypeOf((*MockchatService)(nil).TransferOwnership), ctx, req)
+}
+
+// UpdateMemberRole mocks base method.
+func (m *MockchatService) UpdateMemberRole(ctx context.Context, req models.UpdateMemberRoleRequest) (models.UpdateMemberRoleResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMemberRole", ctx, req)
+	ret0, _ := ret[0].(models.UpdateMemberRoleResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateMemberRole indicates an expected call of UpdateMemberRole.
+func (mr *MockchatServiceMockRecorder) UpdateMemberRole(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMemberRole", reflect.TypeOf((*MockchatService)(nil).UpdateMemberRole), ctx, req)
+}
+
+// UploadAttachment mocks base method.
+func (m *MockchatService) UploadAttachment(ctx context.Context, req models.UploadAttachmentRequest, data io.Reader) (models.UploadAttachmentResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UploadAttachment", ctx, req, data)
+	ret0, _ := ret[0].(models.UploadAttachmentResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UploadAttachment indicates an expected call of UploadAttachment.
+func (mr *MockchatServiceMockRecorder) UploadAttachment(ctx, req, data any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UploadAttachment", reflect.TypeOf((*MockchatService)(nil).UploadAttachment), ctx, req, data)
+}
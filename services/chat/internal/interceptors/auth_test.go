@@ -0,0 +1,127 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/BeInBloom/grpc-chat/pkg/authtoken"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+type fakeVerifier struct {
+	tokens map[string]authtoken.Identity
+}
+
+func (v fakeVerifier) Verify(_ context.Context, token string) (authtoken.Identity, error) {
+	identity, ok := v.tokens[token]
+	if !ok {
+		return authtoken.Identity{}, errors.New("invalid token")
+	}
+
+	return identity, nil
+}
+
+var testIdentity = authtoken.Identity{
+	UserID:    uuid.MustParse("550e8400-e29b-41d4-a716-446655440000"),
+	Role:      int32(models.UserRoleAdmin),
+	SessionID: uuid.MustParse("7d444840-9dc0-11d1-b245-5ffdce74fad2"),
+}
+
+func newTestAuthenticator() *Authenticator {
+	return NewAuthenticator(
+		fakeVerifier{tokens: map[string]authtoken.Identity{"valid": testIdentity}},
+		"/grpc.health.v1.Health/",
+	)
+}
+
+func incomingContext(authorization string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(authorizationHeader, authorization))
+}
+
+func TestAuthenticator_Unary(t *testing.T) {
+	interceptor := newTestAuthenticator().Unary()
+	info := &grpc.UnaryServerInfo{FullMethod: "/chat.v1.ChatService/SendMessage"}
+
+	var got Identity
+	_, err := interceptor(incomingContext("Bearer valid"), nil, info, func(ctx context.Context, _ any) (any, error) {
+		got, _ = IdentityFromContext(ctx)
+		return nil, nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, testIdentity.UserID, got.UserID)
+	assert.Equal(t, models.UserRoleAdmin, got.Role)
+	assert.Equal(t, testIdentity.SessionID, got.SessionID)
+}
+
+func TestAuthenticator_UnaryRejects(t *testing.T) {
+	interceptor := newTestAuthenticator().Unary()
+	info := &grpc.UnaryServerInfo{FullMethod: "/chat.v1.ChatService/SendMessage"}
+
+	tests := map[string]context.Context{
+		"no metadata":    context.Background(),
+		"no header":      metadata.NewIncomingContext(context.Background(), metadata.MD{}),
+		"wrong scheme":   incomingContext("Basic valid"),
+		"invalid token":  incomingContext("Bearer forged"),
+		"empty bearer":   incomingContext("Bearer "),
+		"lowercase none": incomingContext("valid"),
+	}
+
+	for name, ctx := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := interceptor(ctx, nil, info, func(context.Context, any) (any, error) {
+				t.Fatal("handler must not be called")
+				return nil, nil
+			})
+
+			assert.Equal(t, codes.Unauthenticated, status.Code(err))
+		})
+	}
+}
+
+func TestAuthenticator_PublicMethod(t *testing.T) {
+	interceptor := newTestAuthenticator().Unary()
+	info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+	called := false
+	_, err := interceptor(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		called = true
+		return nil, nil
+	})
+
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestAuthenticator_Stream(t *testing.T) {
+	interceptor := newTestAuthenticator().Stream()
+	info := &grpc.StreamServerInfo{FullMethod: "/chat.v1.ChatService/Connect"}
+	stream := &fakeServerStream{ctx: incomingContext("bearer valid")}
+
+	var got uuid.UUID
+	err := interceptor(nil, stream, info, func(_ any, ss grpc.ServerStream) error {
+		got = UserIDFromContext(ss.Context())
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, testIdentity.UserID, got)
+}
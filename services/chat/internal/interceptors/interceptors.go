@@ -4,17 +4,38 @@ import (
 	"context"
 
 	"github.com/google/uuid"
-)
 
-const (
-	userID = "user_id"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
 )
 
+type identityKey struct{}
+
+type Identity struct {
+	UserID    uuid.UUID
+	Role      models.UserRole
+	SessionID uuid.UUID
+}
+
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+func IdentityFromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
+
 func UserIDFromContext(ctx context.Context) uuid.UUID {
-	userID, ok := ctx.Value(userID).(uuid.UUID)
-	if ok {
-		return userID
-	}
+	identity, _ := IdentityFromContext(ctx)
+	return identity.UserID
+}
+
+func RoleFromContext(ctx context.Context) models.UserRole {
+	identity, _ := IdentityFromContext(ctx)
+	return identity.Role
+}
 
-	return uuid.UUID{}
+func SessionIDFromContext(ctx context.Context) uuid.UUID {
+	identity, _ := IdentityFromContext(ctx)
+	return identity.SessionID
 }
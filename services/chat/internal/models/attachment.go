@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Attachment is a file uploaded to a chat, encrypted by the client. The
+// server knows only its size.
+type Attachment struct {
+	ID         uuid.UUID
+	ChatID     uuid.UUID
+	UploaderID uuid.UUID
+	Size       int64
+	CreatedAt  time.Time
+}
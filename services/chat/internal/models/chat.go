@@ -18,6 +18,7 @@ type Chat struct {
 	ID        uuid.UUID
 	Name      string
 	Type      ChatType
+	Members   []ChatMember
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
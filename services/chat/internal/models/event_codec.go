@@ -0,0 +1,129 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrUnknownEventType = errors.New("unknown event type")
+	ErrPayloadMismatch  = errors.New("payload does not match the event type")
+)
+
+// EncodePayload serializes an event payload for the event log and the broker.
+func EncodePayload(eventType EventType, payload any) ([]byte, error) {
+	if err := ValidatePayload(eventType, payload); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encode %s payload: %w", eventType, err)
+	}
+
+	return data, nil
+}
+
+// DecodePayload restores the typed payload written by EncodePayload.
+func DecodePayload(eventType EventType, data []byte) (any, error) {
+	switch eventType {
+	case EventTypeMessageNew:
+		return decodePayload[MessageNewPayload](eventType, data)
+	case EventTypeMessageUpdated:
+		return decodePayload[MessageUpdatedPayload](eventType, data)
+	case EventTypeMessageDeleted:
+		return decodePayload[MessageDeletedPayload](eventType, data)
+	case EventTypeTyping:
+		return decodePayload[TypingIndicatorPayload](eventType, data)
+	case EventTypeReadReceipt:
+		return decodePayload[ReadReceiptPayload](eventType, data)
+	case EventTypeSystem:
+		return decodePayload[SystemNotificationPayload](eventType, data)
+	case EventTypeSnapshot:
+		return decodePayload[SnapshotPayload](eventType, data)
+	case EventTypeReactionUpdated:
+		return decodePayload[ReactionUpdatedPayload](eventType, data)
+	case EventTypeThreadUpdated:
+		return decodePayload[ThreadUpdatedPayload](eventType, data)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownEventType, eventType)
+	}
+}
+
+// ValidatePayload checks that the payload is the one DecodePayload restores
+// for the event type, so that an event is never stored in a form it cannot
+// be read back from.
+func ValidatePayload(eventType EventType, payload any) error {
+	var ok bool
+	switch eventType {
+	case EventTypeMessageNew:
+		_, ok = payload.(MessageNewPayload)
+	case EventTypeMessageUpdated:
+		_, ok = payload.(MessageUpdatedPayload)
+	case EventTypeMessageDeleted:
+		_, ok = payload.(MessageDeletedPayload)
+	case EventTypeTyping:
+		_, ok = payload.(TypingIndicatorPayload)
+	case EventTypeReadReceipt:
+		_, ok = payload.(ReadReceiptPayload)
+	case EventTypeSystem:
+		_, ok = payload.(SystemNotificationPayload)
+	case EventTypeSnapshot:
+		_, ok = payload.(SnapshotPayload)
+	case EventTypeReactionUpdated:
+		_, ok = payload.(ReactionUpdatedPayload)
+	case EventTypeThreadUpdated:
+		_, ok = payload.(ThreadUpdatedPayload)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownEventType, eventType)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s with %T", ErrPayloadMismatch, eventType, payload)
+	}
+
+	return nil
+}
+
+func decodePayload[T any](eventType EventType, data []byte) (T, error) {
+	var payload T
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return payload, fmt.Errorf("decode %s payload: %w", eventType, err)
+	}
+
+	return payload, nil
+}
+
+func ToEventDTO(event Event) (EventDTO, error) {
+	payload, err := EncodePayload(event.Type, event.Payload)
+	if err != nil {
+		return EventDTO{}, err
+	}
+
+	return EventDTO{
+		ID:        event.ID,
+		UserID:    event.UserID,
+		ChatID:    event.ChatID,
+		Type:      event.Type,
+		Payload:   payload,
+		CreatedAt: event.CreatedAt,
+	}, nil
+}
+
+func (d EventDTO) ToEvent() (Event, error) {
+	payload, err := DecodePayload(d.Type, d.Payload)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		ID:        d.ID,
+		UserID:    d.UserID,
+		ChatID:    d.ChatID,
+		Type:      d.Type,
+		Payload:   payload,
+		CreatedAt: d.CreatedAt,
+		Position:  d.Position,
+	}, nil
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventDTO_RoundTrip(t *testing.T) {
+	chatID := uuid.New()
+	replyTo := uuid.New()
+
+	event := Event{
+		ID:     uuid.Must(uuid.NewV7()),
+		UserID: uuid.New(),
+		ChatID: &chatID,
+		Type:   EventTypeMessageNew,
+		Payload: MessageNewPayload{
+			MessageID: uuid.New(),
+			ChatID:    chatID,
+			SenderID:  uuid.New(),
+			Content: MessageContent{
+				Type:             ContentTypeText,
+				Ciphertext:       []byte{0x00, 0xff},
+				ReplyToMessageID: &replyTo,
+			},
+			CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		CreatedAt: time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	dto, err := ToEventDTO(event)
+	require.NoError(t, err)
+
+	got, err := dto.ToEvent()
+	require.NoError(t, err)
+	assert.Equal(t, event, got)
+}
+
+func TestDecodePayload_UnknownType(t *testing.T) {
+	_, err := DecodePayload("UNKNOWN", []byte("{}"))
+
+	assert.ErrorIs(t, err, ErrUnknownEventType)
+}
+
+func TestEventDTO_RoundTripPayloads(t *testing.T) {
+	chatID := uuid.New()
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	payloads := map[EventType]any{
+		EventTypeMessageUpdated: MessageUpdatedPayload{
+			MessageID:  uuid.New(),
+			ChatID:     chatID,
+			NewContent: MessageContent{Type: ContentTypeText, Ciphertext: []byte("edited")},
+			UpdatedAt:  at,
+		},
+		EventTypeMessageDeleted: MessageDeletedPayload{MessageID: uuid.New(), ChatID: chatID, DeletedAt: at},
+		EventTypeTyping:         TypingIndicatorPayload{ChatID: chatID, UserID: uuid.New(), IsTyping: true},
+		EventTypeReadReceipt:    ReadReceiptPayload{ChatID: chatID, UserID: uuid.New(), MessageID: uuid.New(), ReadAt: at},
+		EventTypeSystem:         SystemNotificationPayload{Text: "hello", Level: SystemNotificationLevelWarning},
+		EventTypeThreadUpdated: ThreadUpdatedPayload{
+			ChatID:        chatID,
+			RootMessageID: uuid.New(),
+			ReplyCount:    3,
+			LastReplyID:   uuid.New(),
+			LastReplyAt:   at,
+		},
+		EventTypeReactionUpdated: ReactionUpdatedPayload{
+			MessageID: uuid.New(),
+			ChatID:    chatID,
+			UserID:    uuid.New(),
+			Emoji:     "👍",
+			Added:     true,
+			Count:     2,
+			UpdatedAt: at,
+		},
+		EventTypeSnapshot: SnapshotPayload{Chats: []ChatSnapshot{{
+			Chat: Chat{
+				ID:        chatID,
+				Name:      "team",
+				Type:      ChatTypeGroup,
+				Members:   []ChatMember{{ChatID: chatID, UserID: uuid.New(), Role: MemberRoleOwner, JoinedAt: at}},
+				CreatedAt: at,
+				UpdatedAt: at,
+			},
+			LastMessageID:  uuid.New(),
+			UnreadCount:    3,
+			ReadStates:     []ReadState{{UserID: uuid.New(), LastReadMessageID: uuid.New(), ReadAt: at}},
+			LastActivityAt: at,
+		}}},
+	}
+
+	for eventType, payload := range payloads {
+		t.Run(string(eventType), func(t *testing.T) {
+			event := Event{
+				ID:        uuid.Must(uuid.NewV7()),
+				UserID:    uuid.New(),
+				ChatID:    &chatID,
+				Type:      eventType,
+				Payload:   payload,
+				CreatedAt: at,
+			}
+
+			dto, err := ToEventDTO(event)
+			require.NoError(t, err)
+
+			got, err := dto.ToEvent()
+			require.NoError(t, err)
+			assert.Equal(t, event, got)
+		})
+	}
+}
+
+func TestEventDTO_RoundTripAttachmentContents(t *testing.T) {
+	chatID := uuid.New()
+	attachmentID := uuid.New()
+	at := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	contentTypes := map[string]ContentType{"image": ContentTypeImage, "file": ContentTypeFile, "voice": ContentTypeVoice}
+
+	for name, contentType := range contentTypes {
+		t.Run(name, func(t *testing.T) {
+			event := Event{
+				ID:     uuid.Must(uuid.NewV7()),
+				UserID: uuid.New(),
+				ChatID: &chatID,
+				Type:   EventTypeMessageUpdated,
+				Payload: MessageUpdatedPayload{
+					MessageID:  uuid.New(),
+					ChatID:     chatID,
+					NewContent: MessageContent{Type: contentType, Ciphertext: []byte("key"), AttachmentID: &attachmentID},
+					UpdatedAt:  at,
+				},
+				CreatedAt: at,
+			}
+
+			dto, err := ToEventDTO(event)
+			require.NoError(t, err)
+
+			got, err := dto.ToEvent()
+			require.NoError(t, err)
+			assert.Equal(t, event, got)
+		})
+	}
+}
+
+func TestEncodePayload_Rejects(t *testing.T) {
+	_, err := EncodePayload("UNKNOWN", SystemNotificationPayload{})
+	assert.ErrorIs(t, err, ErrUnknownEventType)
+
+	_, err = EncodePayload(EventTypeMessageNew, SystemNotificationPayload{})
+	assert.ErrorIs(t, err, ErrPayloadMismatch)
+
+	_, err = EncodePayload(EventTypeSystem, &SystemNotificationPayload{})
+	assert.ErrorIs(t, err, ErrPayloadMismatch, "payloads are stored by value")
+}
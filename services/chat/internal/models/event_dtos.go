@@ -15,14 +15,18 @@ const (
 	PayloadTypeTypingIndicator    EventPayloadType = "TYPING_INDICATOR"
 	PayloadTypeReadReceipt        EventPayloadType = "READ_RECEIPT"
 	PayloadTypeSystemNotification EventPayloadType = "SYSTEM_NOTIFICATION"
+	PayloadTypeSnapshot           EventPayloadType = "SNAPSHOT"
+	PayloadTypeReactionUpdated    EventPayloadType = "REACTION_UPDATED"
+	PayloadTypeThreadUpdated      EventPayloadType = "THREAD_UPDATED"
 )
 
 type MessageNewPayload struct {
-	MessageID uuid.UUID
-	ChatID    uuid.UUID
-	SenderID  uuid.UUID
-	Content   MessageContent
-	CreatedAt time.Time
+	MessageID    uuid.UUID
+	ChatID       uuid.UUID
+	SenderID     uuid.UUID
+	Content      MessageContent
+	CreatedAt    time.Time
+	ThreadRootID *uuid.UUID
 }
 
 type MessageUpdatedPayload struct {
@@ -51,9 +55,49 @@ type ReadReceiptPayload struct {
 	ReadAt    time.Time
 }
 
+// ReactionUpdatedPayload reports that UserID added or removed a reaction.
+// Count is the number of users with the emoji on the message afterwards.
+type ReactionUpdatedPayload struct {
+	MessageID uuid.UUID
+	ChatID    uuid.UUID
+	UserID    uuid.UUID
+	Emoji     string
+	Added     bool
+	Count     int32
+	UpdatedAt time.Time
+}
+
+// ThreadUpdatedPayload reports a new reply in the thread of RootMessageID.
+type ThreadUpdatedPayload struct {
+	ChatID        uuid.UUID
+	RootMessageID uuid.UUID
+	ReplyCount    int32
+	LastReplyID   uuid.UUID
+	LastReplyAt   time.Time
+}
+
 type SystemNotificationPayload struct {
 	Text  string
 	Level SystemNotificationLevel
+	// ResyncRequired asks the client to reload its chats and history
+	// because the events it missed are not replayed.
+	ResyncRequired bool
+}
+
+// SnapshotPayload is the state of every chat of a user at the event the
+// snapshot was taken at.
+type SnapshotPayload struct {
+	Chats []ChatSnapshot
+}
+
+type ChatSnapshot struct {
+	// Chat includes the members.
+	Chat Chat
+	// LastMessageID is uuid.Nil while the chat has no messages.
+	LastMessageID  uuid.UUID
+	UnreadCount    int32
+	ReadStates     []ReadState
+	LastActivityAt time.Time
 }
 
 func GetPayloadType(eventType EventType) EventPayloadType {
@@ -70,6 +114,12 @@ func GetPayloadType(eventType EventType) EventPayloadType {
 		return PayloadTypeReadReceipt
 	case EventTypeSystem:
 		return PayloadTypeSystemNotification
+	case EventTypeSnapshot:
+		return PayloadTypeSnapshot
+	case EventTypeReactionUpdated:
+		return PayloadTypeReactionUpdated
+	case EventTypeThreadUpdated:
+		return PayloadTypeThreadUpdated
 	default:
 		return ""
 	}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -8,8 +9,11 @@ import (
 
 type SendMessageRequest struct {
 	ChatID         uuid.UUID
+	SenderID       uuid.UUID
 	IdempotencyKey string
 	Content        MessageContent
+	// ThreadRootID posts the message as a reply in that message's thread.
+	ThreadRootID *uuid.UUID
 }
 
 type SendMessageResponse struct {
@@ -17,8 +21,69 @@ type SendMessageResponse struct {
 	CreatedAt time.Time
 }
 
+type EditMessageRequest struct {
+	MessageID uuid.UUID
+	UserID    uuid.UUID
+	Content   MessageContent
+}
+
+type EditMessageResponse struct {
+	Message Message
+}
+
+type DeleteMessageRequest struct {
+	MessageID uuid.UUID
+	UserID    uuid.UUID
+}
+
+type SetTypingRequest struct {
+	ChatID   uuid.UUID
+	UserID   uuid.UUID
+	IsTyping bool
+}
+
+type MarkReadRequest struct {
+	ChatID    uuid.UUID
+	UserID    uuid.UUID
+	MessageID uuid.UUID
+}
+
+type MarkReadResponse struct {
+	State ReadState
+}
+
+type GetReadStateRequest struct {
+	ChatID uuid.UUID
+	UserID uuid.UUID
+}
+
+type GetReadStateResponse struct {
+	Members []ReadState
+}
+
+type AddReactionRequest struct {
+	MessageID uuid.UUID
+	UserID    uuid.UUID
+	Emoji     string
+}
+
+type AddReactionResponse struct {
+	Reaction ReactionSummary
+}
+
+type RemoveReactionRequest struct {
+	MessageID uuid.UUID
+	UserID    uuid.UUID
+	Emoji     string
+}
+
+type RemoveReactionResponse struct {
+	Reaction ReactionSummary
+}
+
 type GetHistoryRequest struct {
 	ChatID   uuid.UUID
+	UserID   uuid.UUID
 	PageSize int32
 	Cursor   string
 }
@@ -28,7 +93,48 @@ type GetHistoryResponse struct {
 	NextCursor string
 }
 
+type GetThreadRequest struct {
+	RootMessageID uuid.UUID
+	UserID        uuid.UUID
+	PageSize      int32
+	Cursor        string
+}
+
+type GetThreadResponse struct {
+	Root       Message
+	Replies    []Message
+	NextCursor string
+}
+
+type UploadAttachmentRequest struct {
+	ChatID     uuid.UUID
+	UploaderID uuid.UUID
+	Size       int64
+}
+
+type UploadAttachmentResponse struct {
+	Attachment Attachment
+}
+
+// DownloadAttachmentRequest asks for Length bytes from Offset; zero Length
+// reads to the end.
+type DownloadAttachmentRequest struct {
+	AttachmentID uuid.UUID
+	UserID       uuid.UUID
+	Offset       int64
+	Length       int64
+}
+
+// DownloadAttachmentResponse holds the requested range of the attachment.
+// The caller closes Data.
+type DownloadAttachmentResponse struct {
+	Attachment Attachment
+	Offset     int64
+	Data       io.ReadCloser
+}
+
 type CreateChatRequest struct {
+	OwnerID   uuid.UUID
 	Name      string
 	Type      ChatType
 	MemberIDs []uuid.UUID
@@ -38,6 +144,44 @@ type CreateChatResponse struct {
 	Chat Chat
 }
 
+type AddMembersRequest struct {
+	ChatID    uuid.UUID
+	UserID    uuid.UUID
+	MemberIDs []uuid.UUID
+}
+
+type AddMembersResponse struct {
+	Members []ChatMember
+}
+
+type RemoveMemberRequest struct {
+	ChatID   uuid.UUID
+	UserID   uuid.UUID
+	MemberID uuid.UUID
+}
+
+type LeaveChatRequest struct {
+	ChatID uuid.UUID
+	UserID uuid.UUID
+}
+
+type UpdateMemberRoleRequest struct {
+	ChatID   uuid.UUID
+	UserID   uuid.UUID
+	MemberID uuid.UUID
+	Role     MemberRole
+}
+
+type UpdateMemberRoleResponse struct {
+	Member ChatMember
+}
+
+type TransferOwnershipRequest struct {
+	ChatID     uuid.UUID
+	UserID     uuid.UUID
+	NewOwnerID uuid.UUID
+}
+
 type GetChatRequest struct {
 	ChatID uuid.UUID
 }
@@ -53,7 +197,7 @@ type ListChatsRequest struct {
 }
 
 type ListChatsResponse struct {
-	Chats      []Chat
+	Chats      []ChatPreview
 	NextCursor string
 }
 
@@ -67,6 +211,6 @@ type ChatPreview struct {
 }
 
 type SubscribeRequest struct {
-	UserID      uuid.UUID
+	Handle      SubscriptionHandle
 	LastEventID uuid.UUID
 }
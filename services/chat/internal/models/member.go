@@ -21,3 +21,11 @@ type ChatMember struct {
 	Role     MemberRole
 	JoinedAt time.Time
 }
+
+// ReadState is the read cursor of a chat member. LastReadMessageID is
+// uuid.Nil until the member reads a message.
+type ReadState struct {
+	UserID            uuid.UUID
+	LastReadMessageID uuid.UUID
+	ReadAt            time.Time
+}
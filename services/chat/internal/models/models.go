@@ -11,9 +11,9 @@ type ContentType int32
 const (
 	ContentTypeUnspecified ContentType = iota
 	ContentTypeText
-	// ContentTypeImage  // будущее расширение
-	// ContentTypeFile
-	// ContentTypeVoice
+	ContentTypeImage
+	ContentTypeFile
+	ContentTypeVoice
 )
 
 type SystemNotificationLevel int32
@@ -28,14 +28,25 @@ const (
 type EventType string
 
 const (
-	EventTypeMessageNew     EventType = "MESSAGE_NEW"
-	EventTypeMessageUpdated EventType = "MESSAGE_UPDATED"
-	EventTypeMessageDeleted EventType = "MESSAGE_DELETED"
-	EventTypeTyping         EventType = "TYPING"
-	EventTypeReadReceipt    EventType = "READ_RECEIPT"
-	EventTypeSystem         EventType = "SYSTEM"
+	EventTypeMessageNew      EventType = "MESSAGE_NEW"
+	EventTypeMessageUpdated  EventType = "MESSAGE_UPDATED"
+	EventTypeMessageDeleted  EventType = "MESSAGE_DELETED"
+	EventTypeTyping          EventType = "TYPING"
+	EventTypeReadReceipt     EventType = "READ_RECEIPT"
+	EventTypeSystem          EventType = "SYSTEM"
+	EventTypeReactionUpdated EventType = "REACTION_UPDATED"
+	EventTypeThreadUpdated   EventType = "THREAD_UPDATED"
+	// EventTypeSnapshot carries a user's materialized state. It is built
+	// for a reconnecting client and never written to the event log.
+	EventTypeSnapshot EventType = "SNAPSHOT"
 )
 
+// IsEphemeral reports whether events of this type are delivered live only
+// and never written to the event log.
+func (t EventType) IsEphemeral() bool {
+	return t == EventTypeTyping
+}
+
 type Message struct {
 	ID        uuid.UUID
 	ChatID    uuid.UUID
@@ -43,17 +54,51 @@ type Message struct {
 	Content   MessageContent
 	CreatedAt time.Time
 	UpdatedAt time.Time
+	DeletedAt *time.Time
+	// Reactions is filled in only when the message is read for a user.
+	Reactions []ReactionSummary
+	// ThreadRootID is set on a thread reply.
+	ThreadRootID *uuid.UUID
+	// ReplyCount and LastReplyAt summarize the thread of a root message.
+	ReplyCount  int32
+	LastReplyAt *time.Time
 }
+
 type Event struct {
 	ID        uuid.UUID
 	UserID    uuid.UUID
+	ChatID    *uuid.UUID
 	Type      EventType
 	Payload   any
 	CreatedAt time.Time
+	// Position is where the event is in its user's log. It is zero for an
+	// event that was not read from the log.
+	Position EventPosition
+}
+
+// EventPosition orders the events of a user's log by the ID of the
+// transaction that stored them, TxID, and by Seq within it. Transaction IDs
+// are handed out when a transaction first writes, not when it commits, so
+// this is not commit order; but once the log is read below its horizon no
+// event can land before a position already read. The zero position is
+// before every event.
+type EventPosition struct {
+	TxID int64
+	Seq  int64
+}
+
+// After reports whether p comes after other.
+func (p EventPosition) After(other EventPosition) bool {
+	if p.TxID != other.TxID {
+		return p.TxID > other.TxID
+	}
+	return p.Seq > other.Seq
 }
 
 type MessageContent struct {
-	Type             ContentType
-	Ciphertext       []byte
+	Type       ContentType
+	Ciphertext []byte
+	// AttachmentID is set on image, file and voice contents.
+	AttachmentID     *uuid.UUID
 	ReplyToMessageID *uuid.UUID
 }
@@ -0,0 +1,24 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reaction is the reaction of one user to a message with one emoji.
+type Reaction struct {
+	MessageID uuid.UUID
+	UserID    uuid.UUID
+	Emoji     string
+	CreatedAt time.Time
+}
+
+// ReactionSummary counts the users that reacted to a message with an
+// emoji. ReactedByMe tells whether the user reading the message is one of
+// them.
+type ReactionSummary struct {
+	Emoji       string
+	Count       int32
+	ReactedByMe bool
+}
@@ -13,6 +13,7 @@ type EventDTO struct {
 	Type      EventType
 	Payload   []byte
 	CreatedAt time.Time
+	Position  EventPosition
 }
 
 type ChatDTO struct {
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Snapshot is a user's state materialized at Position: it reflects every
+// event of the user's log up to and including that position. EventID is
+// the ID the snapshot is sent with.
+type Snapshot struct {
+	UserID    uuid.UUID
+	EventID   uuid.UUID
+	Position  EventPosition
+	State     SnapshotPayload
+	CreatedAt time.Time
+}
+
+// Event returns the snapshot as a stream event. Its ID is the snapshot's
+// event ID, so a client that received it resumes right after the snapshot.
+func (s Snapshot) Event() Event {
+	return Event{
+		ID:        s.EventID,
+		UserID:    s.UserID,
+		Type:      EventTypeSnapshot,
+		Payload:   s.State,
+		CreatedAt: s.CreatedAt,
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+import "github.com/google/uuid"
+
+// SubscriptionHandle identifies one live stream. A user may hold several at
+// once, one or more per device session, and each is torn down on its own.
+type SubscriptionHandle struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	StreamID  uuid.UUID
+}
+
+func NewSubscriptionHandle(userID, sessionID uuid.UUID) SubscriptionHandle {
+	return SubscriptionHandle{
+		UserID:    userID,
+		SessionID: sessionID,
+		StreamID:  uuid.New(),
+	}
+}
@@ -0,0 +1,9 @@
+package models
+
+type UserRole int32
+
+const (
+	UserRoleUnspecified UserRole = iota
+	UserRoleUser
+	UserRoleAdmin
+)
@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+const attachmentColumns = "id, chat_id, uploader_id, size, created_at"
+
+type AttachmentRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAttachmentRepository(pool *pgxpool.Pool) *AttachmentRepository {
+	return &AttachmentRepository{pool: pool}
+}
+
+// Create stores the attachment and adds its size to the uploader's usage.
+// It reports false and stores nothing when the usage would exceed quota.
+func (r *AttachmentRepository) Create(ctx context.Context, attachment models.Attachment, quota int64) (bool, error) {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx, `
+		WITH usage AS (
+			INSERT INTO attachment_usage AS u (user_id, used_bytes)
+			SELECT $3::uuid, $4::bigint
+			WHERE $4::bigint <= $6::bigint
+			ON CONFLICT (user_id) DO UPDATE
+			SET used_bytes = u.used_bytes + excluded.used_bytes
+			WHERE u.used_bytes + excluded.used_bytes <= $6
+			RETURNING user_id
+		)
+		INSERT INTO attachments (id, chat_id, uploader_id, size, created_at)
+		SELECT $1::uuid, $2::uuid, $3::uuid, $4::bigint, $5::timestamptz
+		FROM usage`,
+		attachment.ID, attachment.ChatID, attachment.UploaderID, attachment.Size, attachment.CreatedAt, quota,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert attachment: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Usage returns the bytes the user has uploaded.
+func (r *AttachmentRepository) Usage(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var used int64
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx,
+		"SELECT coalesce(sum(used_bytes), 0) FROM attachment_usage WHERE user_id = $1",
+		userID,
+	).Scan(&used)
+	if err != nil {
+		return 0, fmt.Errorf("select attachment usage: %w", err)
+	}
+
+	return used, nil
+}
+
+func (r *AttachmentRepository) Get(ctx context.Context, id uuid.UUID) (models.Attachment, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, "SELECT "+attachmentColumns+" FROM attachments WHERE id = $1", id)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("select attachment: %w", err)
+	}
+
+	return collectAttachment(rows)
+}
+
+// GetForShare returns the attachment and keeps DeleteOrphans from deleting
+// it until the end of the transaction.
+func (r *AttachmentRepository) GetForShare(ctx context.Context, id uuid.UUID) (models.Attachment, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx,
+		"SELECT "+attachmentColumns+" FROM attachments WHERE id = $1 FOR SHARE",
+		id,
+	)
+	if err != nil {
+		return models.Attachment{}, fmt.Errorf("select attachment for share: %w", err)
+	}
+
+	return collectAttachment(rows)
+}
+
+// IsReferenced reports whether a message refers to the attachment. The
+// content of a deleted message is erased, so it no longer does.
+func (r *AttachmentRepository) IsReferenced(ctx context.Context, id uuid.UUID) (bool, error) {
+	var referenced bool
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM messages WHERE content->>'AttachmentID' = $1::text)",
+		id,
+	).Scan(&referenced)
+	if err != nil {
+		return false, fmt.Errorf("select attachment reference: %w", err)
+	}
+
+	return referenced, nil
+}
+
+// DeleteOrphans deletes up to limit attachments uploaded before the given
+// time that no message refers to: uploads that were never sent and the
+// attachments of deleted or edited messages and of deleted chats. Their
+// sizes are subtracted from the uploaders' usage. It returns the deleted
+// attachments, whose blobs are left to the caller.
+func (r *AttachmentRepository) DeleteOrphans(ctx context.Context, before time.Time, limit int32) ([]models.Attachment, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, `
+		WITH orphans AS (
+			DELETE FROM attachments
+			WHERE id IN (
+				SELECT a.id
+				FROM attachments a
+				WHERE a.created_at < $1
+				  AND NOT EXISTS (SELECT 1 FROM messages m WHERE m.content->>'AttachmentID' = a.id::text)
+				ORDER BY a.created_at
+				LIMIT $2
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING `+attachmentColumns+`
+		), freed AS (
+			UPDATE attachment_usage u
+			SET used_bytes = u.used_bytes - o.size
+			FROM (SELECT uploader_id, sum(size)::bigint AS size FROM orphans GROUP BY uploader_id) o
+			WHERE u.user_id = o.uploader_id
+		)
+		SELECT `+attachmentColumns+` FROM orphans`,
+		before, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("delete orphan attachments: %w", err)
+	}
+
+	attachments, err := pgx.CollectRows(rows, scanAttachment)
+	if err != nil {
+		return nil, fmt.Errorf("scan attachment: %w", err)
+	}
+
+	return attachments, nil
+}
+
+func collectAttachment(rows pgx.Rows) (models.Attachment, error) {
+	attachment, err := pgx.CollectExactlyOneRow(rows, scanAttachment)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Attachment{}, ErrAttachmentNotFound
+		}
+		return models.Attachment{}, fmt.Errorf("scan attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func scanAttachment(row pgx.CollectableRow) (models.Attachment, error) {
+	var a models.Attachment
+	err := row.Scan(&a.ID, &a.ChatID, &a.UploaderID, &a.Size, &a.CreatedAt)
+
+	return a, err
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestAttachmentRepository_CreateWithinQuota(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewAttachmentRepository(pool)
+	ctx := context.Background()
+	uploaderID := uuid.New()
+	chatID := createTestChat(t, pool, uploaderID)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	create := func(size int64) (models.Attachment, bool) {
+		t.Helper()
+		attachment := models.Attachment{
+			ID:         uuid.Must(uuid.NewV7()),
+			ChatID:     chatID,
+			UploaderID: uploaderID,
+			Size:       size,
+			CreatedAt:  now,
+		}
+		created, err := repo.Create(ctx, attachment, 100)
+		require.NoError(t, err)
+		return attachment, created
+	}
+
+	first, created := create(60)
+	require.True(t, created)
+
+	got, err := repo.Get(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, first, got)
+
+	rejected, created := create(50)
+	assert.False(t, created, "the upload would exceed the quota")
+	_, err = repo.Get(ctx, rejected.ID)
+	assert.ErrorIs(t, err, ErrAttachmentNotFound)
+
+	_, created = create(40)
+	assert.True(t, created, "an upload can fill the quota exactly")
+
+	used, err := repo.Usage(ctx, uploaderID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), used)
+
+	used, err = repo.Usage(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Zero(t, used)
+}
+
+func TestAttachmentRepository_CreateLargerThanQuota(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewAttachmentRepository(pool)
+	ctx := context.Background()
+	uploaderID := uuid.New()
+
+	created, err := repo.Create(ctx, models.Attachment{
+		ID:         uuid.Must(uuid.NewV7()),
+		ChatID:     createTestChat(t, pool, uploaderID),
+		UploaderID: uploaderID,
+		Size:       101,
+		CreatedAt:  time.Now().UTC(),
+	}, 100)
+
+	require.NoError(t, err)
+	assert.False(t, created)
+}
+
+func TestAttachmentRepository_DeleteOrphans(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewAttachmentRepository(pool)
+	messages := NewMessageRepository(pool)
+	ctx := context.Background()
+	uploaderID := uuid.New()
+	chatID := createTestChat(t, pool, uploaderID)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	create := func() models.Attachment {
+		t.Helper()
+		attachment := models.Attachment{
+			ID:         uuid.Must(uuid.NewV7()),
+			ChatID:     chatID,
+			UploaderID: uploaderID,
+			Size:       10,
+			CreatedAt:  now.Add(-time.Hour),
+		}
+		created, err := repo.Create(ctx, attachment, 100)
+		require.NoError(t, err)
+		require.True(t, created)
+		return attachment
+	}
+	send := func(attachment models.Attachment) uuid.UUID {
+		t.Helper()
+		message := models.Message{
+			ID:        uuid.Must(uuid.NewV7()),
+			ChatID:    chatID,
+			SenderID:  uploaderID,
+			Content:   models.MessageContent{Type: models.ContentTypeFile, Ciphertext: []byte("name"), AttachmentID: &attachment.ID},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		require.NoError(t, messages.Create(ctx, message))
+		return message.ID
+	}
+
+	unsent := create()
+	sent := create()
+	send(sent)
+	deleted := create()
+	require.NoError(t, messages.Delete(ctx, send(deleted), now))
+
+	referenced, err := repo.IsReferenced(ctx, sent.ID)
+	require.NoError(t, err)
+	assert.True(t, referenced)
+	referenced, err = repo.IsReferenced(ctx, deleted.ID)
+	require.NoError(t, err)
+	assert.False(t, referenced, "a deleted message no longer refers to its attachment")
+
+	orphans, err := repo.DeleteOrphans(ctx, now.Add(-2*time.Hour), 100)
+	require.NoError(t, err)
+	assert.Empty(t, orphans, "recent uploads may still be sent")
+
+	orphans, err = repo.DeleteOrphans(ctx, now, 100)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.Attachment{unsent, deleted}, orphans)
+
+	_, err = repo.Get(ctx, sent.ID)
+	require.NoError(t, err)
+	used, err := repo.Usage(ctx, uploaderID)
+	require.NoError(t, err)
+	assert.Equal(t, sent.Size, used, "the quota of the deleted attachments is freed")
+}
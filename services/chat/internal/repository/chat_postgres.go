@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+const chatColumns = "id, name, type, created_at, updated_at"
+
+type ChatRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewChatRepository(pool *pgxpool.Pool) *ChatRepository {
+	return &ChatRepository{pool: pool}
+}
+
+// Create stores the chat with its members. For a direct chat it returns
+// false and stores nothing when the pair already has one.
+func (r *ChatRepository) Create(ctx context.Context, chat models.Chat) (bool, error) {
+	conn := postgres.Conn(ctx, r.pool)
+
+	var key *string
+	if chat.Type == models.ChatTypeDirect {
+		if len(chat.Members) != 2 {
+			return false, fmt.Errorf("direct chat has %d members", len(chat.Members))
+		}
+		k := directKey(chat.Members[0].UserID, chat.Members[1].UserID)
+		key = &k
+	}
+
+	tag, err := conn.Exec(ctx, `
+		INSERT INTO chats (id, name, type, direct_key, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (direct_key) DO NOTHING`,
+		chat.ID, chat.Name, chat.Type, key, chat.CreatedAt, chat.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert chat: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return false, nil
+	}
+
+	rows := make([][]any, 0, len(chat.Members))
+	for _, m := range chat.Members {
+		rows = append(rows, []any{chat.ID, m.UserID, m.Role, m.JoinedAt})
+	}
+
+	if _, err := conn.CopyFrom(
+		ctx,
+		pgx.Identifier{"chat_members"},
+		[]string{"chat_id", "user_id", "role", "joined_at"},
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		return false, fmt.Errorf("insert chat members: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *ChatRepository) Get(ctx context.Context, id uuid.UUID) (models.Chat, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, "SELECT "+chatColumns+" FROM chats WHERE id = $1", id)
+	if err != nil {
+		return models.Chat{}, fmt.Errorf("select chat: %w", err)
+	}
+
+	return r.collectChat(ctx, rows)
+}
+
+// GetForUpdate returns the chat and locks it until the end of the
+// transaction, so that membership changes of the chat are serialized.
+func (r *ChatRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (models.Chat, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, "SELECT "+chatColumns+" FROM chats WHERE id = $1 FOR UPDATE", id)
+	if err != nil {
+		return models.Chat{}, fmt.Errorf("select chat for update: %w", err)
+	}
+
+	return r.collectChat(ctx, rows)
+}
+
+// GetDirect returns the direct chat between the two users.
+func (r *ChatRepository) GetDirect(ctx context.Context, a, b uuid.UUID) (models.Chat, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx,
+		"SELECT "+chatColumns+" FROM chats WHERE direct_key = $1",
+		directKey(a, b),
+	)
+	if err != nil {
+		return models.Chat{}, fmt.Errorf("select direct chat: %w", err)
+	}
+
+	return r.collectChat(ctx, rows)
+}
+
+func (r *ChatRepository) collectChat(ctx context.Context, rows pgx.Rows) (models.Chat, error) {
+	chat, err := pgx.CollectExactlyOneRow(rows, func(row pgx.CollectableRow) (models.Chat, error) {
+		var c models.Chat
+		err := row.Scan(&c.ID, &c.Name, &c.Type, &c.CreatedAt, &c.UpdatedAt)
+		return c, err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Chat{}, ErrChatNotFound
+		}
+		return models.Chat{}, fmt.Errorf("scan chat: %w", err)
+	}
+
+	rows, err = postgres.Conn(ctx, r.pool).Query(ctx,
+		"SELECT "+memberColumns+" FROM chat_members WHERE chat_id = $1 ORDER BY joined_at, user_id",
+		chat.ID,
+	)
+	if err != nil {
+		return models.Chat{}, fmt.Errorf("select chat members: %w", err)
+	}
+
+	chat.Members, err = pgx.CollectRows(rows, scanMember)
+	if err != nil {
+		return models.Chat{}, fmt.Errorf("scan chat members: %w", err)
+	}
+
+	return chat, nil
+}
+
+// directKey is the same for both orders of the pair.
+func directKey(a, b uuid.UUID) string {
+	first, second := a.String(), b.String()
+	if first > second {
+		first, second = second, first
+	}
+
+	return first + ":" + second
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func newTestDirectChat(a, b uuid.UUID) models.Chat {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	chatID := uuid.Must(uuid.NewV7())
+
+	return models.Chat{
+		ID:        chatID,
+		Type:      models.ChatTypeDirect,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Members: []models.ChatMember{
+			{ChatID: chatID, UserID: a, Role: models.MemberRoleOwner, JoinedAt: now},
+			{ChatID: chatID, UserID: b, Role: models.MemberRoleMember, JoinedAt: now},
+		},
+	}
+}
+
+func TestChatRepository_CreateDirectOnce(t *testing.T) {
+	repo := NewChatRepository(newTestPool(t))
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+
+	first := newTestDirectChat(alice, bob)
+	created, err := repo.Create(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	created, err = repo.Create(ctx, newTestDirectChat(bob, alice))
+	require.NoError(t, err)
+	assert.False(t, created, "the pair already has a direct chat")
+
+	got, err := repo.GetDirect(ctx, bob, alice)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, got.ID)
+	assert.Len(t, got.Members, 2)
+
+	_, err = repo.Get(ctx, uuid.New())
+	assert.ErrorIs(t, err, ErrChatNotFound)
+}
@@ -0,0 +1,12 @@
+package repository
+
+import "errors"
+
+var (
+	ErrChatNotFound       = errors.New("chat not found")
+	ErrMemberNotFound     = errors.New("chat member not found")
+	ErrEventNotFound      = errors.New("event not found")
+	ErrMessageNotFound    = errors.New("message not found")
+	ErrSnapshotNotFound   = errors.New("snapshot not found")
+	ErrAttachmentNotFound = errors.New("attachment not found")
+)
@@ -0,0 +1,412 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+const eventColumns = "id, user_id, chat_id, type, payload, created_at, tx_id, seq"
+
+// visibleHorizon is the oldest transaction still running in the whole
+// cluster. Every event of an older transaction is already visible, and no
+// event can be added before it anymore, so the log is read only below it.
+// The horizon does not pass a running transaction, whatever it touches:
+// one long transaction anywhere holds back the delivery of stored events
+// to every stream until it ends.
+const visibleHorizon = "pg_snapshot_xmin(pg_current_snapshot())::text::bigint"
+
+// EventRepository is the per-user event log. Calls made inside
+// postgres.TxManager.Do join its transaction.
+type EventRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewEventRepository(pool *pgxpool.Pool) *EventRepository {
+	return &EventRepository{pool: pool}
+}
+
+// GetUserEvents returns up to limit events of the user that come after the
+// position, in log order, which is transaction ID order. It stops at the
+// visibility horizon, so an event that commits later never lands before
+// the position of one returned now. Events past the horizon wait for every
+// older transaction of the cluster to end, however long it runs.
+func (r *EventRepository) GetUserEvents(
+	ctx context.Context,
+	userID uuid.UUID,
+	after models.EventPosition,
+	limit int32,
+) ([]models.Event, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT `+eventColumns+`
+		FROM events
+		WHERE user_id = $1 AND (tx_id, seq) > ($2, $3) AND tx_id < `+visibleHorizon+`
+		ORDER BY tx_id, seq
+		LIMIT $4`,
+		userID, after.TxID, after.Seq, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select user events: %w", err)
+	}
+
+	dtos, err := pgx.CollectRows(rows, scanEvent)
+	if err != nil {
+		return nil, fmt.Errorf("scan user events: %w", err)
+	}
+
+	events := make([]models.Event, 0, len(dtos))
+	for _, dto := range dtos {
+		event, err := dto.ToEvent()
+		if err != nil {
+			return nil, fmt.Errorf("event %s: %w", dto.ID, err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
+
+// CountUserEvents counts the events of the user after the position that
+// GetUserEvents would return now, but stops at limit so that a long backlog
+// is not scanned to the end.
+func (r *EventRepository) CountUserEvents(
+	ctx context.Context,
+	userID uuid.UUID,
+	after models.EventPosition,
+	limit int32,
+) (int32, error) {
+	var count int32
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx, `
+		SELECT count(*)
+		FROM (
+			SELECT 1
+			FROM events
+			WHERE user_id = $1 AND (tx_id, seq) > ($2, $3) AND tx_id < `+visibleHorizon+`
+			LIMIT $4
+		) AS backlog`,
+		userID, after.TxID, after.Seq, limit,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count user events: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetEventPosition returns the position of the event in the user's log,
+// or the position it had when it was collapsed.
+func (r *EventRepository) GetEventPosition(
+	ctx context.Context,
+	userID, eventID uuid.UUID,
+) (models.EventPosition, error) {
+	var position models.EventPosition
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx, `
+		SELECT tx_id, seq FROM events WHERE user_id = $1 AND id = $2
+		UNION ALL
+		SELECT tx_id, seq FROM collapsed_events WHERE user_id = $1 AND id = $2
+		LIMIT 1`,
+		userID, eventID,
+	).Scan(&position.TxID, &position.Seq)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.EventPosition{}, ErrEventNotFound
+		}
+		return models.EventPosition{}, fmt.Errorf("select event position: %w", err)
+	}
+
+	return position, nil
+}
+
+// UserHead returns the position and the ID of the last event of the user
+// that GetUserEvents returns now: the events after it have not all
+// committed yet. Without such an event it returns the zero position and
+// uuid.Nil, which GetUserEvents reads from alike.
+func (r *EventRepository) UserHead(ctx context.Context, userID uuid.UUID) (models.EventPosition, uuid.UUID, error) {
+	var (
+		position models.EventPosition
+		id       uuid.UUID
+	)
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx, `
+		SELECT id, tx_id, seq
+		FROM events
+		WHERE user_id = $1 AND tx_id < `+visibleHorizon+`
+		ORDER BY tx_id DESC, seq DESC
+		LIMIT 1`,
+		userID,
+	).Scan(&id, &position.TxID, &position.Seq)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.EventPosition{}, uuid.Nil, nil
+		}
+		return models.EventPosition{}, uuid.Nil, fmt.Errorf("select head event: %w", err)
+	}
+
+	return position, id, nil
+}
+
+// CompactUserEvents deletes the events of the user up to and including
+// the position that were created before the given time. Call it only for
+// events a snapshot of the user already covers.
+func (r *EventRepository) CompactUserEvents(
+	ctx context.Context,
+	userID uuid.UUID,
+	through models.EventPosition,
+	before time.Time,
+) (int64, error) {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx,
+		"DELETE FROM events WHERE user_id = $1 AND (tx_id, seq) <= ($2, $3) AND created_at < $4",
+		userID, through.TxID, through.Seq, before,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete user events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// DeleteEventsBefore deletes up to limit events of any user whose ID is
+// older than horizon and returns how many it deleted.
+func (r *EventRepository) DeleteEventsBefore(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error) {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx, `
+		DELETE FROM events
+		WHERE (user_id, id) IN (
+			SELECT user_id, id
+			FROM events
+			WHERE id < $1
+			ORDER BY id
+			LIMIT $2
+		)`,
+		horizon, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// DeleteCollapsedBefore deletes up to limit positions of collapsed events
+// whose ID is older than horizon and returns how many it deleted.
+func (r *EventRepository) DeleteCollapsedBefore(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error) {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx, `
+		DELETE FROM collapsed_events
+		WHERE (user_id, id) IN (
+			SELECT user_id, id
+			FROM collapsed_events
+			WHERE id < $1
+			ORDER BY id
+			LIMIT $2
+		)`,
+		horizon, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired collapsed events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+// CollapseSupersededEvents deletes up to limit events older than horizon
+// that a later event of the same user makes redundant: edits of a message
+// followed by another edit or its deletion, read receipts followed by a
+// later receipt of the same reader in the chat, and typing events, which
+// are not meant to be stored at all. "Later" is later in the log, by
+// position. The positions of the deleted events are kept in
+// collapsed_events, so a client whose cursor is one of them still resumes
+// after it. It returns how many it deleted.
+func (r *EventRepository) CollapseSupersededEvents(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error) {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx, `
+		WITH collapsed AS (
+			DELETE FROM events
+			WHERE (user_id, id) IN (
+				SELECT old.user_id, old.id
+				FROM events old
+				WHERE old.id < $1
+				  AND (
+					old.type = $3
+					OR (old.type = $4 AND EXISTS (
+						SELECT 1
+						FROM events newer
+						WHERE newer.user_id = old.user_id
+						  AND (newer.tx_id, newer.seq) > (old.tx_id, old.seq)
+						  AND newer.chat_id = old.chat_id
+						  AND newer.type IN ($4, $5)
+						  AND newer.payload->>'MessageID' = old.payload->>'MessageID'
+					))
+					OR (old.type = $6 AND EXISTS (
+						SELECT 1
+						FROM events newer
+						WHERE newer.user_id = old.user_id
+						  AND (newer.tx_id, newer.seq) > (old.tx_id, old.seq)
+						  AND newer.chat_id = old.chat_id
+						  AND newer.type = $6
+						  AND newer.payload->>'UserID' = old.payload->>'UserID'
+					))
+				  )
+				LIMIT $2
+			)
+			RETURNING user_id, id, tx_id, seq
+		)
+		INSERT INTO collapsed_events (user_id, id, tx_id, seq)
+		SELECT user_id, id, tx_id, seq FROM collapsed`,
+		horizon, limit,
+		string(models.EventTypeTyping),
+		string(models.EventTypeMessageUpdated),
+		string(models.EventTypeMessageDeleted),
+		string(models.EventTypeReadReceipt),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("collapse superseded events: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func (r *EventRepository) GetEvent(ctx context.Context, userID, eventID uuid.UUID) (models.Event, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx,
+		"SELECT "+eventColumns+" FROM events WHERE user_id = $1 AND id = $2",
+		userID, eventID,
+	)
+	if err != nil {
+		return models.Event{}, fmt.Errorf("select event: %w", err)
+	}
+
+	dto, err := pgx.CollectExactlyOneRow(rows, scanEvent)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Event{}, ErrEventNotFound
+		}
+		return models.Event{}, fmt.Errorf("scan event: %w", err)
+	}
+
+	return dto.ToEvent()
+}
+
+// Append writes events that are already addressed to their users and sets
+// the position each of them got in its user's log.
+func (r *EventRepository) Append(ctx context.Context, events ...models.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([][]any, 0, len(events))
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		dto, err := models.ToEventDTO(event)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, []any{dto.ID, dto.UserID, dto.ChatID, string(dto.Type), dto.Payload, dto.CreatedAt})
+		ids = append(ids, dto.ID)
+	}
+
+	conn := postgres.Conn(ctx, r.pool)
+	_, err := conn.CopyFrom(
+		ctx,
+		pgx.Identifier{"events"},
+		[]string{"id", "user_id", "chat_id", "type", "payload", "created_at"},
+		pgx.CopyFromRows(rows),
+	)
+	if err != nil {
+		return fmt.Errorf("insert events: %w", err)
+	}
+
+	positionRows, err := conn.Query(ctx, "SELECT user_id, id, tx_id, seq FROM events WHERE id = ANY($1)", ids)
+	if err != nil {
+		return fmt.Errorf("select event positions: %w", err)
+	}
+
+	type eventKey struct{ userID, id uuid.UUID }
+	positions := make(map[eventKey]models.EventPosition, len(events))
+	var (
+		key      eventKey
+		position models.EventPosition
+	)
+	_, err = pgx.ForEachRow(positionRows, []any{&key.userID, &key.id, &position.TxID, &position.Seq}, func() error {
+		positions[key] = position
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("scan event positions: %w", err)
+	}
+
+	for i := range events {
+		events[i].Position = positions[eventKey{userID: events[i].UserID, id: events[i].ID}]
+	}
+
+	return nil
+}
+
+// AppendToChat copies one event into the log of every member of the chat
+// and returns the copies, with their positions, in member order. Run it in the transaction that
+// writes the change the event describes.
+func (r *EventRepository) AppendToChat(
+	ctx context.Context,
+	chatID uuid.UUID,
+	eventType models.EventType,
+	payload any,
+) ([]models.Event, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT user_id
+		FROM chat_members
+		WHERE chat_id = $1
+		ORDER BY user_id`,
+		chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select chat members: %w", err)
+	}
+
+	members, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("scan chat members: %w", err)
+	}
+
+	if len(members) == 0 {
+		return nil, ErrChatNotFound
+	}
+
+	now := time.Now().UTC()
+	events := make([]models.Event, 0, len(members))
+	for _, userID := range members {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return nil, fmt.Errorf("generate event id: %w", err)
+		}
+
+		events = append(events, models.Event{
+			ID:        id,
+			UserID:    userID,
+			ChatID:    &chatID,
+			Type:      eventType,
+			Payload:   payload,
+			CreatedAt: now,
+		})
+	}
+
+	if err := r.Append(ctx, events...); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func scanEvent(row pgx.CollectableRow) (models.EventDTO, error) {
+	var (
+		dto       models.EventDTO
+		eventType string
+	)
+	err := row.Scan(&dto.ID, &dto.UserID, &dto.ChatID, &eventType, &dto.Payload, &dto.CreatedAt,
+		&dto.Position.TxID, &dto.Position.Seq)
+	dto.Type = models.EventType(eventType)
+
+	return dto, err
+}
@@ -0,0 +1,387 @@
+package repository
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+// newTestPool connects to POSTGRES_TEST_DSN and skips the test when it is not set.
+func newTestPool(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := postgres.NewPool(ctx, postgres.Config{DSN: dsn})
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	require.NoError(t, postgres.Migrate(ctx, pool, Migrations, MigrationsDir))
+	_, err = pool.Exec(ctx, "TRUNCATE chats, chat_members, messages, events, collapsed_events, idempotency_keys, chat_previews, user_snapshots, message_reactions, attachments, attachment_usage")
+	require.NoError(t, err)
+
+	return pool
+}
+
+func createTestChat(t *testing.T, pool *pgxpool.Pool, members ...uuid.UUID) uuid.UUID {
+	t.Helper()
+
+	ctx := context.Background()
+	chatID := uuid.Must(uuid.NewV7())
+	now := time.Now()
+
+	_, err := pool.Exec(ctx, "INSERT INTO chats (id, name, type, created_at, updated_at) VALUES ($1, 'test', $2, $3, $3)",
+		chatID, models.ChatTypeGroup, now)
+	require.NoError(t, err)
+
+	for _, userID := range members {
+		_, err := pool.Exec(ctx, "INSERT INTO chat_members (chat_id, user_id, role, joined_at) VALUES ($1, $2, $3, $4)",
+			chatID, userID, models.MemberRoleMember, now)
+		require.NoError(t, err)
+	}
+
+	return chatID
+}
+
+func TestEventRepository_AppendToChatWithMessage(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	messages := NewMessageRepository(pool)
+	tx := postgres.NewTxManager(pool)
+	ctx := context.Background()
+
+	alice, bob := uuid.New(), uuid.New()
+	chatID := createTestChat(t, pool, alice, bob)
+
+	message := models.Message{
+		ID:        uuid.Must(uuid.NewV7()),
+		ChatID:    chatID,
+		SenderID:  alice,
+		Content:   models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}
+	message.UpdatedAt = message.CreatedAt
+
+	var appended []models.Event
+	err := tx.Do(ctx, func(ctx context.Context) error {
+		if err := messages.Create(ctx, message); err != nil {
+			return err
+		}
+		var err error
+		appended, err = events.AppendToChat(ctx, chatID, models.EventTypeMessageNew, models.MessageNewPayload{
+			MessageID: message.ID,
+			ChatID:    chatID,
+			SenderID:  alice,
+			Content:   message.Content,
+			CreatedAt: message.CreatedAt,
+		})
+		return err
+	})
+	require.NoError(t, err)
+
+	require.Len(t, appended, 2)
+	for _, e := range appended {
+		got, err := events.GetUserEvents(ctx, e.UserID, models.EventPosition{}, 10)
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, e.Position, got[0].Position, "the appended events know their positions")
+		assert.Equal(t, models.EventTypeMessageNew, got[0].Type)
+		assert.Equal(t, &chatID, got[0].ChatID)
+
+		payload, ok := got[0].Payload.(models.MessageNewPayload)
+		require.True(t, ok)
+		assert.Equal(t, message.ID, payload.MessageID)
+		assert.Equal(t, []byte("hi"), payload.Content.Ciphertext)
+	}
+}
+
+func TestEventRepository_AppendToChatRollsBack(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	tx := postgres.NewTxManager(pool)
+	ctx := context.Background()
+
+	alice := uuid.New()
+	chatID := createTestChat(t, pool, alice)
+
+	err := tx.Do(ctx, func(ctx context.Context) error {
+		if _, err := events.AppendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "hi"}); err != nil {
+			return err
+		}
+		return assert.AnError
+	})
+	require.ErrorIs(t, err, assert.AnError)
+
+	got, err := events.GetUserEvents(ctx, alice, models.EventPosition{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// appendEvents appends n system events to the chat and returns the
+// events of the user as read back from the log.
+func appendEvents(t *testing.T, events *EventRepository, chatID, userID uuid.UUID, n int) []models.Event {
+	t.Helper()
+
+	ctx := context.Background()
+	for range n {
+		_, err := events.AppendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "hi"})
+		require.NoError(t, err)
+	}
+
+	got, err := events.GetUserEvents(ctx, userID, models.EventPosition{}, int32(n))
+	require.NoError(t, err)
+	require.Len(t, got, n)
+
+	return got
+}
+
+func TestEventRepository_GetUserEventsAfterCursor(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	ctx := context.Background()
+
+	alice := uuid.New()
+	chatID := createTestChat(t, pool, alice)
+	appended := appendEvents(t, events, chatID, alice, 5)
+
+	got, err := events.GetUserEvents(ctx, alice, appended[1].Position, 2)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, appended[2].ID, got[0].ID)
+	assert.Equal(t, appended[3].ID, got[1].ID)
+
+	position, err := events.GetEventPosition(ctx, alice, appended[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, appended[1].Position, position)
+
+	_, err = events.GetEventPosition(ctx, alice, uuid.New())
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestEventRepository_GetUserEventsInTransactionOrder(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	tx := postgres.NewTxManager(pool)
+	ctx := context.Background()
+
+	alice := uuid.New()
+	chatID := createTestChat(t, pool, alice)
+
+	appended := make(chan []models.Event, 1)
+	release := make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		done <- tx.Do(ctx, func(ctx context.Context) error {
+			batch, err := events.AppendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "first"})
+			appended <- batch
+			if err != nil {
+				return err
+			}
+			<-release
+			return nil
+		})
+	}()
+	first := <-appended
+
+	second, err := events.AppendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "second"})
+	require.NoError(t, err)
+
+	head, headID, err := events.UserHead(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, uuid.Nil, headID, "the committed event behind the running transaction is not the head yet")
+
+	got, err := events.GetUserEvents(ctx, alice, models.EventPosition{}, 10)
+	require.NoError(t, err)
+	assert.Empty(t, got, "nothing is read past a transaction that may still add events")
+	count, err := events.CountUserEvents(ctx, alice, models.EventPosition{}, 10)
+	require.NoError(t, err)
+	assert.Zero(t, count, "the count stops at the same horizon as the reads")
+
+	close(release)
+	require.NoError(t, <-done)
+
+	got, err = events.GetUserEvents(ctx, alice, models.EventPosition{}, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, first[0].ID, got[0].ID, "the events keep the order of their transactions")
+	assert.Equal(t, second[0].ID, got[1].ID)
+	assert.True(t, got[0].Position.After(head), "the head is before the events of a running transaction")
+
+	head, headID, err = events.UserHead(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, second[0].ID, headID)
+	assert.Equal(t, got[1].Position, head)
+}
+
+func TestEventRepository_CountUserEvents(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	ctx := context.Background()
+
+	alice := uuid.New()
+	chatID := createTestChat(t, pool, alice)
+	appended := appendEvents(t, events, chatID, alice, 5)
+
+	count, err := events.CountUserEvents(ctx, alice, appended[0].Position, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int32(4), count)
+
+	count, err = events.CountUserEvents(ctx, alice, models.EventPosition{}, 3)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), count, "counting stops at the limit")
+}
+
+func TestEventRepository_CompactUserEvents(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	ctx := context.Background()
+
+	alice := uuid.New()
+	chatID := createTestChat(t, pool, alice)
+	appended := appendEvents(t, events, chatID, alice, 4)
+
+	compacted, err := events.CompactUserEvents(ctx, alice, appended[1].Position, time.Now().Add(-time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, compacted, "recent events are kept")
+
+	compacted, err = events.CompactUserEvents(ctx, alice, appended[1].Position, time.Now().Add(time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), compacted)
+
+	got, err := events.GetUserEvents(ctx, alice, models.EventPosition{}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, appended[2:], got)
+}
+
+func TestEventRepository_DeleteEventsBefore(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	ctx := context.Background()
+
+	alice, bob := uuid.New(), uuid.New()
+	chatID := createTestChat(t, pool, alice, bob)
+
+	for range 3 {
+		_, err := events.AppendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "hi"})
+		require.NoError(t, err)
+	}
+	horizon := uuid.Must(uuid.NewV7())
+	kept, err := events.AppendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "hi"})
+	require.NoError(t, err)
+
+	deleted, err := events.DeleteEventsBefore(ctx, horizon, 4)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), deleted, "the batch is bounded")
+
+	deleted, err = events.DeleteEventsBefore(ctx, horizon, 4)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), deleted)
+
+	got, err := events.GetUserEvents(ctx, alice, models.EventPosition{}, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Contains(t, []uuid.UUID{kept[0].ID, kept[1].ID}, got[0].ID)
+}
+
+func TestEventRepository_CollapseSupersededEvents(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	ctx := context.Background()
+
+	alice, bob := uuid.New(), uuid.New()
+	chatID := createTestChat(t, pool, alice)
+	messageID, otherID := uuid.New(), uuid.New()
+	content := models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")}
+
+	appendOne := func(eventType models.EventType, payload any) uuid.UUID {
+		t.Helper()
+		batch, err := events.AppendToChat(ctx, chatID, eventType, payload)
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		return batch[0].ID
+	}
+
+	appendOne(models.EventTypeMessageUpdated, models.MessageUpdatedPayload{MessageID: messageID, ChatID: chatID, NewContent: content})
+	lastEdit := appendOne(models.EventTypeMessageUpdated, models.MessageUpdatedPayload{MessageID: messageID, ChatID: chatID, NewContent: content})
+	otherEdit := appendOne(models.EventTypeMessageUpdated, models.MessageUpdatedPayload{MessageID: otherID, ChatID: chatID, NewContent: content})
+	appendOne(models.EventTypeReadReceipt, models.ReadReceiptPayload{ChatID: chatID, UserID: bob, MessageID: messageID})
+	lastReceipt := appendOne(models.EventTypeReadReceipt, models.ReadReceiptPayload{ChatID: chatID, UserID: bob, MessageID: otherID})
+
+	collapsed, err := events.CollapseSupersededEvents(ctx, uuid.Must(uuid.NewV7()), 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), collapsed)
+
+	got, err := events.GetUserEvents(ctx, alice, models.EventPosition{}, 10)
+	require.NoError(t, err)
+	ids := make([]uuid.UUID, 0, len(got))
+	for _, e := range got {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []uuid.UUID{lastEdit, otherEdit, lastReceipt}, ids)
+}
+
+func TestEventRepository_CollapsedEventCursor(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	ctx := context.Background()
+
+	alice := uuid.New()
+	chatID := createTestChat(t, pool, alice)
+	messageID := uuid.New()
+	content := models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")}
+
+	edit := func() models.Event {
+		t.Helper()
+		batch, err := events.AppendToChat(ctx, chatID, models.EventTypeMessageUpdated,
+			models.MessageUpdatedPayload{MessageID: messageID, ChatID: chatID, NewContent: content})
+		require.NoError(t, err)
+		require.Len(t, batch, 1)
+		return batch[0]
+	}
+	cursor := edit()
+	last := edit()
+
+	before, err := events.GetEventPosition(ctx, alice, cursor.ID)
+	require.NoError(t, err)
+
+	collapsed, err := events.CollapseSupersededEvents(ctx, uuid.Must(uuid.NewV7()), 10)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), collapsed)
+
+	position, err := events.GetEventPosition(ctx, alice, cursor.ID)
+	require.NoError(t, err, "a cursor on a collapsed event still resolves")
+	assert.Equal(t, before, position)
+
+	got, err := events.GetUserEvents(ctx, alice, position, 10)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, last.ID, got[0].ID)
+
+	deleted, err := events.DeleteCollapsedBefore(ctx, uuid.Must(uuid.NewV7()), 10)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+
+	_, err = events.GetEventPosition(ctx, alice, cursor.ID)
+	assert.ErrorIs(t, err, ErrEventNotFound)
+}
+
+func TestEventRepository_AppendToChatWithoutMembers(t *testing.T) {
+	events := NewEventRepository(newTestPool(t))
+
+	_, err := events.AppendToChat(context.Background(), uuid.New(), models.EventTypeSystem, models.SystemNotificationPayload{})
+
+	assert.ErrorIs(t, err, ErrChatNotFound)
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+type IdempotencyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewIdempotencyRepository(pool *pgxpool.Pool) *IdempotencyRepository {
+	return &IdempotencyRepository{pool: pool}
+}
+
+// Claim records result under the sender's key in the chat. When the key was already
+// claimed after notBefore it returns the stored result and false; an older
+// claim is taken over. A concurrent claim of the same key waits for the
+// other transaction to finish.
+func (r *IdempotencyRepository) Claim(
+	ctx context.Context,
+	senderID uuid.UUID,
+	chatID uuid.UUID,
+	key string,
+	result models.IdempotencyResult,
+	notBefore time.Time,
+) (models.IdempotencyResult, bool, error) {
+	conn := postgres.Conn(ctx, r.pool)
+
+	rows, err := conn.Query(ctx, `
+		INSERT INTO idempotency_keys (sender_id, chat_id, idempotency_key, message_id, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (sender_id, chat_id, idempotency_key) DO UPDATE
+		SET message_id = excluded.message_id, created_at = excluded.created_at
+		WHERE idempotency_keys.created_at < $6
+		RETURNING message_id, created_at`,
+		senderID, chatID, key, result.MessageID, result.CreatedAt, notBefore,
+	)
+	if err != nil {
+		return models.IdempotencyResult{}, false, fmt.Errorf("claim idempotency key: %w", err)
+	}
+
+	claimed, err := pgx.CollectExactlyOneRow(rows, scanIdempotencyResult)
+	if err == nil {
+		return claimed, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return models.IdempotencyResult{}, false, fmt.Errorf("scan idempotency key: %w", err)
+	}
+
+	rows, err = conn.Query(ctx, `
+		SELECT message_id, created_at
+		FROM idempotency_keys
+		WHERE sender_id = $1 AND chat_id = $2 AND idempotency_key = $3`,
+		senderID, chatID, key,
+	)
+	if err != nil {
+		return models.IdempotencyResult{}, false, fmt.Errorf("select idempotency key: %w", err)
+	}
+
+	existing, err := pgx.CollectExactlyOneRow(rows, scanIdempotencyResult)
+	if err != nil {
+		return models.IdempotencyResult{}, false, fmt.Errorf("scan idempotency key: %w", err)
+	}
+
+	return existing, false, nil
+}
+
+// DeleteKeysBefore deletes up to limit keys claimed before the given time and
+// returns how many it deleted.
+func (r *IdempotencyRepository) DeleteKeysBefore(ctx context.Context, before time.Time, limit int32) (int64, error) {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx, `
+		DELETE FROM idempotency_keys
+		WHERE (sender_id, chat_id, idempotency_key) IN (
+			SELECT sender_id, chat_id, idempotency_key
+			FROM idempotency_keys
+			WHERE created_at < $1
+			ORDER BY created_at
+			LIMIT $2
+		)`,
+		before, limit,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete expired idempotency keys: %w", err)
+	}
+
+	return tag.RowsAffected(), nil
+}
+
+func scanIdempotencyResult(row pgx.CollectableRow) (models.IdempotencyResult, error) {
+	var result models.IdempotencyResult
+	err := row.Scan(&result.MessageID, &result.CreatedAt)
+
+	return result, err
+}
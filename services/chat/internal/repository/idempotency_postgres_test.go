@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestIdempotencyRepository_Claim(t *testing.T) {
+	repo := NewIdempotencyRepository(newTestPool(t))
+	ctx := context.Background()
+	senderID, chatID := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	first := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: now}
+	got, claimed, err := repo.Claim(ctx, senderID, chatID, "key", first, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, first, got)
+
+	retry := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: now.Add(time.Minute)}
+	got, claimed, err = repo.Claim(ctx, senderID, chatID, "key", retry, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.False(t, claimed)
+	assert.Equal(t, first.MessageID, got.MessageID)
+	assert.True(t, first.CreatedAt.Equal(got.CreatedAt))
+
+	expired := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: now.Add(2 * time.Hour)}
+	got, claimed, err = repo.Claim(ctx, senderID, chatID, "key", expired, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, claimed, "a key older than the retention window is reused")
+	assert.Equal(t, expired.MessageID, got.MessageID)
+}
+
+func TestIdempotencyRepository_ClaimInAnotherChat(t *testing.T) {
+	repo := NewIdempotencyRepository(newTestPool(t))
+	ctx := context.Background()
+	senderID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	first := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: now}
+	_, claimed, err := repo.Claim(ctx, senderID, uuid.New(), "key", first, now.Add(-time.Hour))
+	require.NoError(t, err)
+	require.True(t, claimed)
+
+	other := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: now}
+	got, claimed, err := repo.Claim(ctx, senderID, uuid.New(), "key", other, now.Add(-time.Hour))
+	require.NoError(t, err)
+	assert.True(t, claimed, "the same key in another chat is a new message")
+	assert.Equal(t, other.MessageID, got.MessageID)
+}
+
+func TestIdempotencyRepository_DeleteKeysBefore(t *testing.T) {
+	repo := NewIdempotencyRepository(newTestPool(t))
+	ctx := context.Background()
+	senderID, chatID := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	old := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: now.Add(-48 * time.Hour)}
+	_, _, err := repo.Claim(ctx, senderID, chatID, "old", old, old.CreatedAt)
+	require.NoError(t, err)
+	recent := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: now}
+	_, _, err = repo.Claim(ctx, senderID, chatID, "recent", recent, now)
+	require.NoError(t, err)
+
+	_, err = repo.DeleteKeysBefore(ctx, now.Add(-24*time.Hour), 1000)
+	require.NoError(t, err)
+
+	retry := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: now}
+	got, claimed, err := repo.Claim(ctx, senderID, chatID, "old", retry, old.CreatedAt)
+	require.NoError(t, err)
+	assert.True(t, claimed, "the expired key is gone")
+	assert.Equal(t, retry.MessageID, got.MessageID)
+
+	_, claimed, err = repo.Claim(ctx, senderID, chatID, "recent", retry, old.CreatedAt)
+	require.NoError(t, err)
+	assert.False(t, claimed, "the recent key is kept")
+}
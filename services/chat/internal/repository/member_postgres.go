@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+const (
+	memberColumns    = "chat_id, user_id, role, joined_at"
+	readStateColumns = "user_id, last_read_message_id, last_read_at"
+)
+
+type MemberRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMemberRepository(pool *pgxpool.Pool) *MemberRepository {
+	return &MemberRepository{pool: pool}
+}
+
+func (r *MemberRepository) Get(ctx context.Context, chatID, userID uuid.UUID) (models.ChatMember, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx,
+		"SELECT "+memberColumns+" FROM chat_members WHERE chat_id = $1 AND user_id = $2",
+		chatID, userID,
+	)
+	if err != nil {
+		return models.ChatMember{}, fmt.Errorf("select chat member: %w", err)
+	}
+
+	member, err := pgx.CollectExactlyOneRow(rows, scanMember)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ChatMember{}, ErrMemberNotFound
+		}
+		return models.ChatMember{}, fmt.Errorf("scan chat member: %w", err)
+	}
+
+	return member, nil
+}
+
+// ListUserIDs returns the IDs of the chat members.
+func (r *MemberRepository) ListUserIDs(ctx context.Context, chatID uuid.UUID) ([]uuid.UUID, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx,
+		"SELECT user_id FROM chat_members WHERE chat_id = $1",
+		chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select chat member ids: %w", err)
+	}
+
+	ids, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("scan chat member ids: %w", err)
+	}
+
+	return ids, nil
+}
+
+func (r *MemberRepository) Add(ctx context.Context, members ...models.ChatMember) error {
+	rows := make([][]any, 0, len(members))
+	for _, m := range members {
+		rows = append(rows, []any{m.ChatID, m.UserID, m.Role, m.JoinedAt})
+	}
+
+	if _, err := postgres.Conn(ctx, r.pool).CopyFrom(
+		ctx,
+		pgx.Identifier{"chat_members"},
+		[]string{"chat_id", "user_id", "role", "joined_at"},
+		pgx.CopyFromRows(rows),
+	); err != nil {
+		return fmt.Errorf("insert chat members: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MemberRepository) Remove(ctx context.Context, chatID, userID uuid.UUID) error {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx,
+		"DELETE FROM chat_members WHERE chat_id = $1 AND user_id = $2",
+		chatID, userID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete chat member: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+func (r *MemberRepository) UpdateRole(ctx context.Context, chatID, userID uuid.UUID, role models.MemberRole) error {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx,
+		"UPDATE chat_members SET role = $3 WHERE chat_id = $1 AND user_id = $2",
+		chatID, userID, role,
+	)
+	if err != nil {
+		return fmt.Errorf("update chat member role: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMemberNotFound
+	}
+
+	return nil
+}
+
+// AdvanceReadCursor moves the member's read cursor to the message unless it
+// is already there or further. It returns the resulting read state and
+// whether the cursor moved.
+func (r *MemberRepository) AdvanceReadCursor(
+	ctx context.Context,
+	chatID, userID, messageID uuid.UUID,
+	readAt time.Time,
+) (models.ReadState, bool, error) {
+	conn := postgres.Conn(ctx, r.pool)
+
+	tag, err := conn.Exec(ctx, `
+		UPDATE chat_members
+		SET last_read_message_id = $3, last_read_at = $4
+		WHERE chat_id = $1 AND user_id = $2
+			AND (last_read_message_id IS NULL OR last_read_message_id < $3)`,
+		chatID, userID, messageID, readAt,
+	)
+	if err != nil {
+		return models.ReadState{}, false, fmt.Errorf("advance read cursor: %w", err)
+	}
+
+	rows, err := conn.Query(ctx,
+		"SELECT "+readStateColumns+" FROM chat_members WHERE chat_id = $1 AND user_id = $2",
+		chatID, userID,
+	)
+	if err != nil {
+		return models.ReadState{}, false, fmt.Errorf("select read state: %w", err)
+	}
+
+	state, err := pgx.CollectExactlyOneRow(rows, scanReadState)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.ReadState{}, false, ErrMemberNotFound
+		}
+		return models.ReadState{}, false, fmt.Errorf("scan read state: %w", err)
+	}
+
+	return state, tag.RowsAffected() > 0, nil
+}
+
+// ListReadStates returns the read cursor of every chat member.
+func (r *MemberRepository) ListReadStates(ctx context.Context, chatID uuid.UUID) ([]models.ReadState, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx,
+		"SELECT "+readStateColumns+" FROM chat_members WHERE chat_id = $1 ORDER BY joined_at, user_id",
+		chatID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select read states: %w", err)
+	}
+
+	states, err := pgx.CollectRows(rows, scanReadState)
+	if err != nil {
+		return nil, fmt.Errorf("scan read states: %w", err)
+	}
+
+	return states, nil
+}
+
+func scanReadState(row pgx.CollectableRow) (models.ReadState, error) {
+	var (
+		s         models.ReadState
+		messageID *uuid.UUID
+		readAt    *time.Time
+	)
+	if err := row.Scan(&s.UserID, &messageID, &readAt); err != nil {
+		return s, err
+	}
+	if messageID != nil {
+		s.LastReadMessageID = *messageID
+	}
+	if readAt != nil {
+		s.ReadAt = *readAt
+	}
+
+	return s, nil
+}
+
+func scanMember(row pgx.CollectableRow) (models.ChatMember, error) {
+	var m models.ChatMember
+	err := row.Scan(&m.ChatID, &m.UserID, &m.Role, &m.JoinedAt)
+
+	return m, err
+}
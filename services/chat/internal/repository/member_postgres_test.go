@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestMemberRepository_Membership(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewMemberRepository(pool)
+	ctx := context.Background()
+	owner, newcomer := uuid.New(), uuid.New()
+	chatID := createTestChat(t, pool, owner)
+
+	err := repo.Add(ctx, models.ChatMember{
+		ChatID:   chatID,
+		UserID:   newcomer,
+		Role:     models.MemberRoleMember,
+		JoinedAt: time.Now().UTC().Truncate(time.Microsecond),
+	})
+	require.NoError(t, err)
+
+	ids, err := repo.ListUserIDs(ctx, chatID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []uuid.UUID{owner, newcomer}, ids)
+
+	require.NoError(t, repo.UpdateRole(ctx, chatID, newcomer, models.MemberRoleAdmin))
+	member, err := repo.Get(ctx, chatID, newcomer)
+	require.NoError(t, err)
+	assert.Equal(t, models.MemberRoleAdmin, member.Role)
+
+	require.NoError(t, repo.Remove(ctx, chatID, newcomer))
+	_, err = repo.Get(ctx, chatID, newcomer)
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+
+	assert.ErrorIs(t, repo.Remove(ctx, chatID, newcomer), ErrMemberNotFound)
+	assert.ErrorIs(t, repo.UpdateRole(ctx, chatID, newcomer, models.MemberRoleMember), ErrMemberNotFound)
+}
+
+func TestMemberRepository_AdvanceReadCursor(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewMemberRepository(pool)
+	ctx := context.Background()
+	reader, other := uuid.New(), uuid.New()
+	chatID := createTestChat(t, pool, reader, other)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	older, newer := uuid.Must(uuid.NewV7()), uuid.Must(uuid.NewV7())
+
+	state, advanced, err := repo.AdvanceReadCursor(ctx, chatID, reader, newer, now)
+	require.NoError(t, err)
+	assert.True(t, advanced)
+	assert.Equal(t, newer, state.LastReadMessageID)
+
+	state, advanced, err = repo.AdvanceReadCursor(ctx, chatID, reader, older, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.False(t, advanced, "the cursor never moves backwards")
+	assert.Equal(t, newer, state.LastReadMessageID)
+	assert.True(t, now.Equal(state.ReadAt))
+
+	states, err := repo.ListReadStates(ctx, chatID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []models.ReadState{
+		{UserID: reader, LastReadMessageID: newer, ReadAt: state.ReadAt},
+		{UserID: other},
+	}, states)
+
+	_, _, err = repo.AdvanceReadCursor(ctx, chatID, uuid.New(), newer, now)
+	assert.ErrorIs(t, err, ErrMemberNotFound)
+}
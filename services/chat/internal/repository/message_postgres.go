@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+const messageColumns = "id, chat_id, sender_id, content, created_at, updated_at, deleted_at, " +
+	"thread_root_id, reply_count, last_reply_at"
+
+type MessageRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewMessageRepository(pool *pgxpool.Pool) *MessageRepository {
+	return &MessageRepository{pool: pool}
+}
+
+func (r *MessageRepository) Create(ctx context.Context, message models.Message) error {
+	content, err := json.Marshal(message.Content)
+	if err != nil {
+		return fmt.Errorf("encode message content: %w", err)
+	}
+
+	_, err = postgres.Conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO messages (id, chat_id, sender_id, content, created_at, updated_at, thread_root_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		message.ID, message.ChatID, message.SenderID, content, message.CreatedAt, message.UpdatedAt, message.ThreadRootID,
+	)
+	if err != nil {
+		return fmt.Errorf("insert message: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MessageRepository) Get(ctx context.Context, id uuid.UUID) (models.Message, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, "SELECT "+messageColumns+" FROM messages WHERE id = $1", id)
+	if err != nil {
+		return models.Message{}, fmt.Errorf("select message: %w", err)
+	}
+
+	return collectMessage(rows)
+}
+
+// GetForUpdate returns the message and locks it until the end of the
+// transaction.
+func (r *MessageRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (models.Message, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx,
+		"SELECT "+messageColumns+" FROM messages WHERE id = $1 FOR UPDATE",
+		id,
+	)
+	if err != nil {
+		return models.Message{}, fmt.Errorf("select message for update: %w", err)
+	}
+
+	return collectMessage(rows)
+}
+
+func collectMessage(rows pgx.Rows) (models.Message, error) {
+	message, err := pgx.CollectExactlyOneRow(rows, scanMessage)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Message{}, ErrMessageNotFound
+		}
+		return models.Message{}, fmt.Errorf("scan message: %w", err)
+	}
+
+	return message, nil
+}
+
+// UpdateContent replaces the content of a message that is not deleted.
+func (r *MessageRepository) UpdateContent(
+	ctx context.Context,
+	id uuid.UUID,
+	content models.MessageContent,
+	updatedAt time.Time,
+) error {
+	encoded, err := json.Marshal(content)
+	if err != nil {
+		return fmt.Errorf("encode message content: %w", err)
+	}
+
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx,
+		"UPDATE messages SET content = $2, updated_at = $3 WHERE id = $1 AND deleted_at IS NULL",
+		id, encoded, updatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("update message: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMessageNotFound
+	}
+
+	return nil
+}
+
+// Delete turns the message into a tombstone: the row stays so that the
+// history keeps its place, but the content is erased.
+func (r *MessageRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	encoded, err := json.Marshal(models.MessageContent{})
+	if err != nil {
+		return fmt.Errorf("encode message content: %w", err)
+	}
+
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx,
+		"UPDATE messages SET content = $2, updated_at = $3, deleted_at = $3 WHERE id = $1 AND deleted_at IS NULL",
+		id, encoded, deletedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("delete message: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrMessageNotFound
+	}
+
+	return nil
+}
+
+// AddThreadReply counts a new reply in the thread of the root message and
+// returns the reply count after it.
+func (r *MessageRepository) AddThreadReply(ctx context.Context, rootID uuid.UUID, repliedAt time.Time) (int32, error) {
+	var count int32
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx, `
+		UPDATE messages
+		SET reply_count = reply_count + 1, last_reply_at = $2
+		WHERE id = $1
+		RETURNING reply_count`,
+		rootID, repliedAt,
+	).Scan(&count)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrMessageNotFound
+		}
+		return 0, fmt.Errorf("update thread summary: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListBefore returns up to limit messages of the chat older than beforeID,
+// newest first. uuid.Nil starts from the newest message. Thread replies are
+// left out.
+func (r *MessageRepository) ListBefore(
+	ctx context.Context,
+	chatID uuid.UUID,
+	beforeID uuid.UUID,
+	limit int32,
+) ([]models.Message, error) {
+	if beforeID == uuid.Nil {
+		beforeID = uuid.Max
+	}
+
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM messages
+		WHERE chat_id = $1 AND id < $2 AND thread_root_id IS NULL
+		ORDER BY id DESC
+		LIMIT $3`,
+		chatID, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select messages: %w", err)
+	}
+
+	messages, err := pgx.CollectRows(rows, scanMessage)
+	if err != nil {
+		return nil, fmt.Errorf("scan messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// ListThreadBefore returns up to limit replies in the thread of the root
+// message older than beforeID, newest first. uuid.Nil starts from the
+// newest reply.
+func (r *MessageRepository) ListThreadBefore(
+	ctx context.Context,
+	rootID uuid.UUID,
+	beforeID uuid.UUID,
+	limit int32,
+) ([]models.Message, error) {
+	if beforeID == uuid.Nil {
+		beforeID = uuid.Max
+	}
+
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT `+messageColumns+`
+		FROM messages
+		WHERE thread_root_id = $1 AND id < $2
+		ORDER BY id DESC
+		LIMIT $3`,
+		rootID, beforeID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select thread replies: %w", err)
+	}
+
+	messages, err := pgx.CollectRows(rows, scanMessage)
+	if err != nil {
+		return nil, fmt.Errorf("scan thread replies: %w", err)
+	}
+
+	return messages, nil
+}
+
+func scanMessage(row pgx.CollectableRow) (models.Message, error) {
+	var (
+		m       models.Message
+		content []byte
+	)
+	err := row.Scan(&m.ID, &m.ChatID, &m.SenderID, &content, &m.CreatedAt, &m.UpdatedAt, &m.DeletedAt,
+		&m.ThreadRootID, &m.ReplyCount, &m.LastReplyAt)
+	if err != nil {
+		return m, err
+	}
+
+	if err := json.Unmarshal(content, &m.Content); err != nil {
+		return m, fmt.Errorf("decode message %s content: %w", m.ID, err)
+	}
+
+	return m, nil
+}
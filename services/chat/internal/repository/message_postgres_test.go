@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestMessageRepository_EditAndDelete(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewMessageRepository(pool)
+	ctx := context.Background()
+	senderID := uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	message := models.Message{
+		ID:        uuid.Must(uuid.NewV7()),
+		ChatID:    createTestChat(t, pool, senderID),
+		SenderID:  senderID,
+		Content:   models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, repo.Create(ctx, message))
+
+	edited := models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hello")}
+	require.NoError(t, repo.UpdateContent(ctx, message.ID, edited, now.Add(time.Minute)))
+
+	got, err := repo.GetForUpdate(ctx, message.ID)
+	require.NoError(t, err)
+	assert.Equal(t, edited, got.Content)
+	assert.True(t, now.Add(time.Minute).Equal(got.UpdatedAt))
+	assert.Nil(t, got.DeletedAt)
+
+	require.NoError(t, repo.Delete(ctx, message.ID, now.Add(2*time.Minute)))
+
+	got, err = repo.GetForUpdate(ctx, message.ID)
+	require.NoError(t, err)
+	assert.Empty(t, got.Content.Ciphertext, "a tombstone has no content")
+	require.NotNil(t, got.DeletedAt)
+
+	assert.ErrorIs(t, repo.Delete(ctx, message.ID, now), ErrMessageNotFound)
+	assert.ErrorIs(t, repo.UpdateContent(ctx, message.ID, edited, now), ErrMessageNotFound)
+
+	_, err = repo.GetForUpdate(ctx, uuid.Must(uuid.NewV7()))
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+}
+
+func TestMessageRepository_Threads(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewMessageRepository(pool)
+	ctx := context.Background()
+	senderID := uuid.New()
+	chatID := createTestChat(t, pool, senderID)
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	newMessage := func(rootID *uuid.UUID) models.Message {
+		message := models.Message{
+			ID:           uuid.Must(uuid.NewV7()),
+			ChatID:       chatID,
+			SenderID:     senderID,
+			Content:      models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+			CreatedAt:    now,
+			UpdatedAt:    now,
+			ThreadRootID: rootID,
+		}
+		require.NoError(t, repo.Create(ctx, message))
+		return message
+	}
+
+	root := newMessage(nil)
+	first := newMessage(&root.ID)
+	second := newMessage(&root.ID)
+
+	count, err := repo.AddThreadReply(ctx, root.ID, now)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), count)
+	count, err = repo.AddThreadReply(ctx, root.ID, now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), count)
+
+	got, err := repo.Get(ctx, root.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), got.ReplyCount)
+	require.NotNil(t, got.LastReplyAt)
+	assert.True(t, now.Add(time.Minute).Equal(*got.LastReplyAt))
+
+	history, err := repo.ListBefore(ctx, chatID, uuid.Nil, 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1, "replies stay out of the chat history")
+	assert.Equal(t, root.ID, history[0].ID)
+
+	replies, err := repo.ListThreadBefore(ctx, root.ID, uuid.Nil, 10)
+	require.NoError(t, err)
+	require.Len(t, replies, 2)
+	assert.Equal(t, second.ID, replies[0].ID)
+	assert.Equal(t, &root.ID, replies[0].ThreadRootID)
+
+	replies, err = repo.ListThreadBefore(ctx, root.ID, second.ID, 10)
+	require.NoError(t, err)
+	require.Len(t, replies, 1)
+	assert.Equal(t, first.ID, replies[0].ID)
+
+	_, err = repo.AddThreadReply(ctx, uuid.Must(uuid.NewV7()), now)
+	assert.ErrorIs(t, err, ErrMessageNotFound)
+}
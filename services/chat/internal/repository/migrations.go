@@ -0,0 +1,8 @@
+package repository
+
+import "embed"
+
+//go:embed migrations/*.sql
+var Migrations embed.FS
+
+const MigrationsDir = "migrations"
@@ -0,0 +1,17 @@
+CREATE TABLE chats (
+    id         UUID PRIMARY KEY,
+    name       TEXT        NOT NULL,
+    type       INTEGER     NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE chat_members (
+    chat_id   UUID        NOT NULL REFERENCES chats (id) ON DELETE CASCADE,
+    user_id   UUID        NOT NULL,
+    role      INTEGER     NOT NULL,
+    joined_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (chat_id, user_id)
+);
+
+CREATE INDEX chat_members_user_id_idx ON chat_members (user_id);
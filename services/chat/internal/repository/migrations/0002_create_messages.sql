@@ -0,0 +1,10 @@
+CREATE TABLE messages (
+    id         UUID PRIMARY KEY,
+    chat_id    UUID        NOT NULL REFERENCES chats (id) ON DELETE CASCADE,
+    sender_id  UUID        NOT NULL,
+    content    JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX messages_chat_id_id_idx ON messages (chat_id, id);
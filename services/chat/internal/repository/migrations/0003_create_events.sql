@@ -0,0 +1,13 @@
+-- Every user has their own event log. Event IDs are UUIDv7, so ordering by
+-- id within a user is the delivery order.
+CREATE TABLE events (
+    id         UUID        NOT NULL,
+    user_id    UUID        NOT NULL,
+    chat_id    UUID,
+    type       TEXT        NOT NULL,
+    payload    JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, id)
+);
+
+CREATE INDEX events_chat_id_idx ON events (chat_id) WHERE chat_id IS NOT NULL;
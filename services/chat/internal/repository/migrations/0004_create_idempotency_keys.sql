@@ -0,0 +1,9 @@
+CREATE TABLE idempotency_keys (
+    sender_id       UUID        NOT NULL,
+    idempotency_key TEXT        NOT NULL,
+    message_id      UUID        NOT NULL,
+    created_at      TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (sender_id, idempotency_key)
+);
+
+CREATE INDEX idempotency_keys_created_at_idx ON idempotency_keys (created_at);
@@ -0,0 +1,5 @@
+-- direct_key identifies the member pair of a direct chat, so that two
+-- concurrent CreateChat calls cannot create the same direct chat twice.
+ALTER TABLE chats ADD COLUMN direct_key TEXT;
+
+CREATE UNIQUE INDEX chats_direct_key_key ON chats (direct_key);
@@ -0,0 +1,24 @@
+-- chat_previews is the ListChats read model: one row per chat of a user,
+-- maintained from the events appended to that user's log.
+CREATE TABLE chat_previews (
+    user_id              UUID        NOT NULL,
+    chat_id              UUID        NOT NULL REFERENCES chats (id) ON DELETE CASCADE,
+    last_message_id      UUID,
+    last_read_message_id UUID,
+    unread_count         INTEGER     NOT NULL DEFAULT 0,
+    last_activity_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, chat_id)
+);
+
+CREATE INDEX chat_previews_activity_idx ON chat_previews (user_id, last_activity_at DESC, chat_id DESC);
+
+INSERT INTO chat_previews (user_id, chat_id, last_message_id, last_activity_at)
+SELECT cm.user_id, cm.chat_id, lm.id, COALESCE(lm.created_at, cm.joined_at)
+FROM chat_members cm
+LEFT JOIN LATERAL (
+    SELECT m.id, m.created_at
+    FROM messages m
+    WHERE m.chat_id = cm.chat_id
+    ORDER BY m.id DESC
+    LIMIT 1
+) lm ON true;
@@ -0,0 +1,5 @@
+-- The read cursor of a member only moves forward: it is the newest message
+-- the member has read.
+ALTER TABLE chat_members
+    ADD COLUMN last_read_message_id UUID,
+    ADD COLUMN last_read_at         TIMESTAMPTZ;
@@ -0,0 +1,9 @@
+-- user_snapshots keeps the latest materialized state of every user. The
+-- state reflects the user's events up to and including event_id, so the
+-- events before it can be compacted.
+CREATE TABLE user_snapshots (
+    user_id    UUID PRIMARY KEY,
+    event_id   UUID        NOT NULL,
+    state      JSONB       NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
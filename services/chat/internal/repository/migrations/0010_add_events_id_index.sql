@@ -0,0 +1,3 @@
+-- Retention deletes events by age across all users; event IDs are UUIDv7,
+-- so an index on id finds the oldest ones without a full scan.
+CREATE INDEX events_id_idx ON events (id);
@@ -0,0 +1,10 @@
+-- A user reacts to a message at most once with each emoji.
+CREATE TABLE message_reactions (
+    message_id UUID        NOT NULL REFERENCES messages (id) ON DELETE CASCADE,
+    user_id    UUID        NOT NULL,
+    emoji      TEXT        NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (message_id, user_id, emoji)
+);
+
+CREATE INDEX message_reactions_message_id_emoji_idx ON message_reactions (message_id, emoji);
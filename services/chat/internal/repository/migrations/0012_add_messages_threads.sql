@@ -0,0 +1,8 @@
+-- A reply points at the root of its thread; the root keeps a summary of
+-- its replies so that the history shows it without loading the thread.
+ALTER TABLE messages
+    ADD COLUMN thread_root_id UUID REFERENCES messages (id) ON DELETE CASCADE,
+    ADD COLUMN reply_count    INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN last_reply_at  TIMESTAMPTZ;
+
+CREATE INDEX messages_thread_root_id_id_idx ON messages (thread_root_id, id) WHERE thread_root_id IS NOT NULL;
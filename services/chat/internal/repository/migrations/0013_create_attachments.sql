@@ -0,0 +1,14 @@
+-- The bytes of an attachment live in the blob store under its id.
+CREATE TABLE attachments (
+    id          UUID PRIMARY KEY,
+    chat_id     UUID        NOT NULL REFERENCES chats (id) ON DELETE CASCADE,
+    uploader_id UUID        NOT NULL,
+    size        BIGINT      NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL
+);
+
+-- The bytes each user has uploaded, checked against the upload quota.
+CREATE TABLE attachment_usage (
+    user_id    UUID   PRIMARY KEY,
+    used_bytes BIGINT NOT NULL
+);
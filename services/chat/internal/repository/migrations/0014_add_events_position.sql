@@ -0,0 +1,26 @@
+-- Event IDs are generated before the transaction that stores them commits,
+-- so a reader paging by id can pass an event that commits later with a
+-- smaller one. The position (tx_id, seq) orders events by the transaction
+-- that stored them instead, and readers only page through transactions
+-- older than the oldest one still running, which can no longer add events.
+ALTER TABLE events
+    ADD COLUMN tx_id BIGINT,
+    ADD COLUMN seq   BIGINT;
+
+CREATE SEQUENCE events_seq OWNED BY events.seq;
+
+-- The events stored so far are long committed; they keep their id order.
+UPDATE events e
+SET tx_id = 0, seq = ordered.n
+FROM (SELECT user_id, id, row_number() OVER (ORDER BY id) AS n FROM events) AS ordered
+WHERE e.user_id = ordered.user_id AND e.id = ordered.id;
+
+SELECT setval('events_seq', COALESCE((SELECT max(seq) FROM events), 0) + 1, false);
+
+ALTER TABLE events
+    ALTER COLUMN tx_id SET DEFAULT pg_current_xact_id()::text::bigint,
+    ALTER COLUMN tx_id SET NOT NULL,
+    ALTER COLUMN seq SET DEFAULT nextval('events_seq'),
+    ALTER COLUMN seq SET NOT NULL;
+
+CREATE INDEX events_user_id_position_idx ON events (user_id, tx_id, seq);
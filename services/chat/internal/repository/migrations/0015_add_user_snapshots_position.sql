@@ -0,0 +1,18 @@
+-- A snapshot reflects the user's log up to a position (tx_id, seq) rather
+-- than up to an event ID; see 0014_add_events_position.sql.
+ALTER TABLE user_snapshots
+    ADD COLUMN tx_id BIGINT,
+    ADD COLUMN seq   BIGINT;
+
+UPDATE user_snapshots s
+SET tx_id = e.tx_id, seq = e.seq
+FROM events e
+WHERE e.user_id = s.user_id AND e.id = s.event_id;
+
+-- Snapshots whose event is gone cannot be placed in the log; the worker
+-- takes them again.
+DELETE FROM user_snapshots WHERE tx_id IS NULL;
+
+ALTER TABLE user_snapshots
+    ALTER COLUMN tx_id SET NOT NULL,
+    ALTER COLUMN seq SET NOT NULL;
@@ -0,0 +1,17 @@
+-- A SendMessage idempotency key is scoped to the chat, so that the same key
+-- sent to another chat creates a new message there instead of returning the
+-- message of the first chat.
+ALTER TABLE idempotency_keys ADD COLUMN chat_id UUID;
+
+UPDATE idempotency_keys k
+SET chat_id = m.chat_id
+FROM messages m
+WHERE m.id = k.message_id;
+
+-- Keys of deleted messages have nothing left to return.
+DELETE FROM idempotency_keys WHERE chat_id IS NULL;
+
+ALTER TABLE idempotency_keys
+    ALTER COLUMN chat_id SET NOT NULL,
+    DROP CONSTRAINT idempotency_keys_pkey,
+    ADD PRIMARY KEY (sender_id, chat_id, idempotency_key);
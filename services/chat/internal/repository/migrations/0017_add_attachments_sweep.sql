@@ -0,0 +1,9 @@
+-- Attachments of a deleted chat stay until the retention worker sweeps them,
+-- so that their blobs are removed and their quota is freed.
+ALTER TABLE attachments DROP CONSTRAINT attachments_chat_id_fkey;
+
+CREATE INDEX attachments_created_at_idx ON attachments (created_at);
+
+-- Finds the messages that refer to an attachment.
+CREATE INDEX messages_attachment_id_idx ON messages ((content->>'AttachmentID'))
+    WHERE content->>'AttachmentID' IS NOT NULL;
@@ -0,0 +1,6 @@
+-- CollapseSupersededEvents looks for a later event of the same user about
+-- the same message or the same reader; without these indexes each lookup
+-- scans all of the user's events.
+CREATE INDEX events_message_id_idx ON events (user_id, (payload->>'MessageID'), type);
+
+CREATE INDEX events_reader_id_idx ON events (user_id, chat_id, type, (payload->>'UserID'));
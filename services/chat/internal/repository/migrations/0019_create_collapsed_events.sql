@@ -0,0 +1,12 @@
+-- CollapseSupersededEvents deletes events a client may still hold as its
+-- cursor. Their positions stay here so that such a client resumes after
+-- them instead of starting over; they expire with the events of their age.
+CREATE TABLE collapsed_events (
+    user_id UUID   NOT NULL,
+    id      UUID   NOT NULL,
+    tx_id   BIGINT NOT NULL,
+    seq     BIGINT NOT NULL,
+    PRIMARY KEY (user_id, id)
+);
+
+CREATE INDEX collapsed_events_id_idx ON collapsed_events (id);
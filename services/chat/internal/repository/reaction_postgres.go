@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+type ReactionRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewReactionRepository(pool *pgxpool.Pool) *ReactionRepository {
+	return &ReactionRepository{pool: pool}
+}
+
+// Add stores the reaction and reports whether the user had not reacted
+// with the emoji yet.
+func (r *ReactionRepository) Add(ctx context.Context, reaction models.Reaction) (bool, error) {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO message_reactions (message_id, user_id, emoji, created_at)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT DO NOTHING`,
+		reaction.MessageID, reaction.UserID, reaction.Emoji, reaction.CreatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("insert reaction: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Remove deletes the reaction and reports whether there was one.
+func (r *ReactionRepository) Remove(ctx context.Context, messageID, userID uuid.UUID, emoji string) (bool, error) {
+	tag, err := postgres.Conn(ctx, r.pool).Exec(ctx,
+		"DELETE FROM message_reactions WHERE message_id = $1 AND user_id = $2 AND emoji = $3",
+		messageID, userID, emoji,
+	)
+	if err != nil {
+		return false, fmt.Errorf("delete reaction: %w", err)
+	}
+
+	return tag.RowsAffected() > 0, nil
+}
+
+// Count returns how many users reacted to the message with the emoji.
+func (r *ReactionRepository) Count(ctx context.Context, messageID uuid.UUID, emoji string) (int32, error) {
+	var count int32
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx,
+		"SELECT count(*) FROM message_reactions WHERE message_id = $1 AND emoji = $2",
+		messageID, emoji,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count reactions: %w", err)
+	}
+
+	return count, nil
+}
+
+// CountEmoji returns how many distinct emoji the message has.
+func (r *ReactionRepository) CountEmoji(ctx context.Context, messageID uuid.UUID) (int32, error) {
+	var count int32
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx,
+		"SELECT count(DISTINCT emoji) FROM message_reactions WHERE message_id = $1",
+		messageID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count reaction emoji: %w", err)
+	}
+
+	return count, nil
+}
+
+// Summaries returns the reactions of the messages as seen by the viewer,
+// keyed by message and ordered by when each emoji was first added.
+func (r *ReactionRepository) Summaries(
+	ctx context.Context,
+	viewerID uuid.UUID,
+	messageIDs []uuid.UUID,
+) (map[uuid.UUID][]models.ReactionSummary, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT message_id, emoji, count(*), bool_or(user_id = $2)
+		FROM message_reactions
+		WHERE message_id = ANY($1)
+		GROUP BY message_id, emoji
+		ORDER BY message_id, min(created_at), emoji`,
+		messageIDs, viewerID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select reactions: %w", err)
+	}
+
+	summaries := make(map[uuid.UUID][]models.ReactionSummary)
+	var (
+		messageID uuid.UUID
+		summary   models.ReactionSummary
+	)
+	_, err = pgx.ForEachRow(rows, []any{&messageID, &summary.Emoji, &summary.Count, &summary.ReactedByMe}, func() error {
+		summaries[messageID] = append(summaries[messageID], summary)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan reactions: %w", err)
+	}
+
+	return summaries, nil
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestReactionRepository_AddRemoveSummaries(t *testing.T) {
+	pool := newTestPool(t)
+	messages := NewMessageRepository(pool)
+	reactions := NewReactionRepository(pool)
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	message := models.Message{
+		ID:        uuid.Must(uuid.NewV7()),
+		ChatID:    createTestChat(t, pool, alice, bob),
+		SenderID:  alice,
+		Content:   models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	require.NoError(t, messages.Create(ctx, message))
+
+	react := func(userID uuid.UUID, emoji string, at time.Time) bool {
+		t.Helper()
+		added, err := reactions.Add(ctx, models.Reaction{MessageID: message.ID, UserID: userID, Emoji: emoji, CreatedAt: at})
+		require.NoError(t, err)
+		return added
+	}
+
+	assert.True(t, react(alice, "👍", now))
+	assert.False(t, react(alice, "👍", now), "a user reacts once with an emoji")
+	assert.True(t, react(bob, "👍", now.Add(time.Second)))
+	assert.True(t, react(bob, "🎉", now.Add(2*time.Second)))
+
+	count, err := reactions.Count(ctx, message.ID, "👍")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), count)
+
+	distinct, err := reactions.CountEmoji(ctx, message.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), distinct)
+
+	summaries, err := reactions.Summaries(ctx, alice, []uuid.UUID{message.ID, uuid.New()})
+	require.NoError(t, err)
+	assert.Equal(t, map[uuid.UUID][]models.ReactionSummary{
+		message.ID: {
+			{Emoji: "👍", Count: 2, ReactedByMe: true},
+			{Emoji: "🎉", Count: 1},
+		},
+	}, summaries)
+
+	removed, err := reactions.Remove(ctx, message.ID, bob, "🎉")
+	require.NoError(t, err)
+	assert.True(t, removed)
+
+	removed, err = reactions.Remove(ctx, message.ID, bob, "🎉")
+	require.NoError(t, err)
+	assert.False(t, removed)
+}
@@ -0,0 +1,192 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+// ReadModelRepository keeps the per-user chat previews. Apply it to the
+// events in the transaction that appends them, so the previews never lag
+// behind the event log.
+type ReadModelRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewReadModelRepository(pool *pgxpool.Pool) *ReadModelRepository {
+	return &ReadModelRepository{pool: pool}
+}
+
+// Apply projects events onto the previews of the users they are addressed to.
+func (r *ReadModelRepository) Apply(ctx context.Context, events ...models.Event) error {
+	batch := &pgx.Batch{}
+
+	for _, event := range events {
+		if event.ChatID == nil {
+			continue
+		}
+
+		switch p := event.Payload.(type) {
+		case models.MessageNewPayload:
+			// A thread reply is shown in its thread, not as the chat's last
+			// message, and does not count as unread in the chat.
+			if p.ThreadRootID != nil {
+				continue
+			}
+			unread := 1
+			if p.SenderID == event.UserID {
+				unread = 0
+			}
+			batch.Queue(`
+				INSERT INTO chat_previews (user_id, chat_id, last_message_id, unread_count, last_activity_at)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (user_id, chat_id) DO UPDATE
+				SET last_message_id = excluded.last_message_id,
+					unread_count = chat_previews.unread_count + excluded.unread_count,
+					last_activity_at = greatest(chat_previews.last_activity_at, excluded.last_activity_at)`,
+				event.UserID, *event.ChatID, p.MessageID, unread, p.CreatedAt,
+			)
+
+		case models.ReadReceiptPayload:
+			if p.UserID != event.UserID {
+				continue
+			}
+			batch.Queue(`
+				UPDATE chat_previews
+				SET last_read_message_id = $3,
+					unread_count = (
+						SELECT count(*)
+						FROM messages m
+						WHERE m.chat_id = $2 AND m.id > $3 AND m.sender_id <> $1
+							AND m.thread_root_id IS NULL AND m.deleted_at IS NULL
+					)
+				WHERE user_id = $1 AND chat_id = $2
+					AND (last_read_message_id IS NULL OR last_read_message_id < $3)`,
+				event.UserID, *event.ChatID, p.MessageID,
+			)
+
+		case models.MessageDeletedPayload:
+			batch.Queue(`
+				UPDATE chat_previews p
+				SET unread_count = greatest(p.unread_count - 1, 0)
+				FROM messages m
+				WHERE p.user_id = $1 AND p.chat_id = $2 AND m.id = $3 AND m.sender_id <> $1
+					AND m.thread_root_id IS NULL
+					AND (p.last_read_message_id IS NULL OR p.last_read_message_id < $3)`,
+				event.UserID, *event.ChatID, p.MessageID,
+			)
+
+		case models.SystemNotificationPayload:
+			batch.Queue(`
+				INSERT INTO chat_previews (user_id, chat_id, last_activity_at)
+				VALUES ($1, $2, $3)
+				ON CONFLICT (user_id, chat_id) DO UPDATE
+				SET last_activity_at = greatest(chat_previews.last_activity_at, excluded.last_activity_at)`,
+				event.UserID, *event.ChatID, event.CreatedAt,
+			)
+		}
+	}
+
+	if batch.Len() == 0 {
+		return nil
+	}
+
+	if err := postgres.Conn(ctx, r.pool).SendBatch(ctx, batch).Close(); err != nil {
+		return fmt.Errorf("apply events to chat previews: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveChat drops the preview of a chat the user is no longer a member of.
+func (r *ReadModelRepository) RemoveChat(ctx context.Context, userID, chatID uuid.UUID) error {
+	_, err := postgres.Conn(ctx, r.pool).Exec(ctx,
+		"DELETE FROM chat_previews WHERE user_id = $1 AND chat_id = $2",
+		userID, chatID,
+	)
+	if err != nil {
+		return fmt.Errorf("delete chat preview: %w", err)
+	}
+
+	return nil
+}
+
+// ListChats returns up to limit previews of the user's chats, most recently
+// active first, that come after the given position and were active after
+// activeAfter. A zero beforeActivity starts from the most recent chat.
+func (r *ReadModelRepository) ListChats(
+	ctx context.Context,
+	userID uuid.UUID,
+	activeAfter time.Time,
+	beforeActivity time.Time,
+	beforeChatID uuid.UUID,
+	limit int32,
+) ([]models.ChatPreview, error) {
+	var before *time.Time
+	if !beforeActivity.IsZero() {
+		before = &beforeActivity
+	}
+
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, `
+		SELECT c.id, c.name, c.type, p.unread_count, p.last_activity_at,
+			m.id, m.sender_id, m.content, m.created_at, m.updated_at, m.deleted_at
+		FROM chat_previews p
+		JOIN chats c ON c.id = p.chat_id
+		LEFT JOIN messages m ON m.id = p.last_message_id
+		WHERE p.user_id = $1
+			AND ($2::timestamptz IS NULL OR (p.last_activity_at, p.chat_id) < ($2, $3))
+			AND p.last_activity_at > $5
+		ORDER BY p.last_activity_at DESC, p.chat_id DESC
+		LIMIT $4`,
+		userID, before, beforeChatID, limit, activeAfter,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select chat previews: %w", err)
+	}
+
+	previews, err := pgx.CollectRows(rows, scanChatPreview)
+	if err != nil {
+		return nil, fmt.Errorf("scan chat previews: %w", err)
+	}
+
+	return previews, nil
+}
+
+func scanChatPreview(row pgx.CollectableRow) (models.ChatPreview, error) {
+	var (
+		p         models.ChatPreview
+		messageID *uuid.UUID
+		senderID  *uuid.UUID
+		content   []byte
+		createdAt *time.Time
+		updatedAt *time.Time
+		deletedAt *time.Time
+	)
+	err := row.Scan(&p.ID, &p.Name, &p.Type, &p.UnreadCount, &p.UpdatedAt,
+		&messageID, &senderID, &content, &createdAt, &updatedAt, &deletedAt)
+	if err != nil || messageID == nil {
+		return p, err
+	}
+
+	p.LastMessage = &models.Message{
+		ID:        *messageID,
+		ChatID:    p.ID,
+		SenderID:  *senderID,
+		CreatedAt: *createdAt,
+		UpdatedAt: *updatedAt,
+		DeletedAt: deletedAt,
+	}
+	if err := json.Unmarshal(content, &p.LastMessage.Content); err != nil {
+		return p, fmt.Errorf("decode message %s content: %w", messageID, err)
+	}
+
+	return p, nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestReadModelRepository_ListChats(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewReadModelRepository(pool)
+	events := NewEventRepository(pool)
+	messages := NewMessageRepository(pool)
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+
+	quiet := createTestChat(t, pool, alice, bob)
+	added, err := events.AppendToChat(ctx, quiet, models.EventTypeSystem, models.SystemNotificationPayload{Text: "added"})
+	require.NoError(t, err)
+	require.NoError(t, repo.Apply(ctx, added...))
+
+	busy := createTestChat(t, pool, alice, bob)
+	message := models.Message{
+		ID:        uuid.Must(uuid.NewV7()),
+		ChatID:    busy,
+		SenderID:  bob,
+		Content:   models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+		CreatedAt: now.Add(time.Minute),
+		UpdatedAt: now.Add(time.Minute),
+	}
+	require.NoError(t, messages.Create(ctx, message))
+	sent, err := events.AppendToChat(ctx, busy, models.EventTypeMessageNew, models.MessageNewPayload{
+		MessageID: message.ID,
+		ChatID:    busy,
+		SenderID:  bob,
+		Content:   message.Content,
+		CreatedAt: message.CreatedAt,
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Apply(ctx, sent...))
+
+	page, err := repo.ListChats(ctx, alice, time.Time{}, time.Time{}, uuid.Nil, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, busy, page[0].ID)
+	assert.Equal(t, int32(1), page[0].UnreadCount)
+	require.NotNil(t, page[0].LastMessage)
+	assert.Equal(t, message.ID, page[0].LastMessage.ID)
+	assert.Equal(t, message.Content, page[0].LastMessage.Content)
+
+	page, err = repo.ListChats(ctx, alice, time.Time{}, page[0].UpdatedAt, page[0].ID, 10)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Equal(t, quiet, page[0].ID)
+	assert.Nil(t, page[0].LastMessage)
+
+	newer, err := repo.ListChats(ctx, alice, now.Add(time.Second), time.Time{}, uuid.Nil, 10)
+	require.NoError(t, err)
+	require.Len(t, newer, 1, "only the chats active after the given time")
+	assert.Equal(t, busy, newer[0].ID)
+
+	senderPage, err := repo.ListChats(ctx, bob, time.Time{}, time.Time{}, uuid.Nil, 1)
+	require.NoError(t, err)
+	require.Len(t, senderPage, 1)
+	assert.Zero(t, senderPage[0].UnreadCount, "own messages are not unread")
+
+	read, err := events.AppendToChat(ctx, busy, models.EventTypeReadReceipt, models.ReadReceiptPayload{
+		ChatID:    busy,
+		UserID:    alice,
+		MessageID: message.ID,
+		ReadAt:    now,
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Apply(ctx, read...))
+
+	page, err = repo.ListChats(ctx, alice, time.Time{}, time.Time{}, uuid.Nil, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Zero(t, page[0].UnreadCount)
+}
+
+func TestReadModelRepository_ThreadReplyKeepsPreview(t *testing.T) {
+	pool := newTestPool(t)
+	repo := NewReadModelRepository(pool)
+	events := NewEventRepository(pool)
+	messages := NewMessageRepository(pool)
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	chatID := createTestChat(t, pool, alice, bob)
+
+	send := func(message models.Message) {
+		t.Helper()
+		require.NoError(t, messages.Create(ctx, message))
+		sent, err := events.AppendToChat(ctx, chatID, models.EventTypeMessageNew, models.MessageNewPayload{
+			MessageID:    message.ID,
+			ChatID:       chatID,
+			SenderID:     message.SenderID,
+			Content:      message.Content,
+			CreatedAt:    message.CreatedAt,
+			ThreadRootID: message.ThreadRootID,
+		})
+		require.NoError(t, err)
+		require.NoError(t, repo.Apply(ctx, sent...))
+	}
+
+	root := models.Message{
+		ID:        uuid.Must(uuid.NewV7()),
+		ChatID:    chatID,
+		SenderID:  bob,
+		Content:   models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("root")},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	send(root)
+	send(models.Message{
+		ID:           uuid.Must(uuid.NewV7()),
+		ChatID:       chatID,
+		SenderID:     bob,
+		Content:      models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("reply")},
+		CreatedAt:    now.Add(time.Minute),
+		UpdatedAt:    now.Add(time.Minute),
+		ThreadRootID: &root.ID,
+	})
+
+	page, err := repo.ListChats(ctx, alice, time.Time{}, time.Time{}, uuid.Nil, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	require.NotNil(t, page[0].LastMessage)
+	assert.Equal(t, root.ID, page[0].LastMessage.ID, "a thread reply is not the chat's last message")
+	assert.Equal(t, int32(1), page[0].UnreadCount, "a thread reply is not unread in the chat")
+
+	read, err := events.AppendToChat(ctx, chatID, models.EventTypeReadReceipt, models.ReadReceiptPayload{
+		ChatID:    chatID,
+		UserID:    alice,
+		MessageID: root.ID,
+		ReadAt:    now,
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Apply(ctx, read...))
+
+	page, err = repo.ListChats(ctx, alice, time.Time{}, time.Time{}, uuid.Nil, 1)
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+	assert.Zero(t, page[0].UnreadCount, "the recount skips thread replies too")
+}
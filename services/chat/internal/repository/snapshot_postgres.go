@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/BeInBloom/grpc-chat/pkg/postgres"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+// SnapshotRepository materializes and keeps the latest snapshot of every
+// user.
+type SnapshotRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewSnapshotRepository(pool *pgxpool.Pool) *SnapshotRepository {
+	return &SnapshotRepository{pool: pool}
+}
+
+// Latest returns the newest snapshot of the user.
+func (r *SnapshotRepository) Latest(ctx context.Context, userID uuid.UUID) (models.Snapshot, error) {
+	var (
+		snapshot models.Snapshot
+		state    []byte
+	)
+	err := postgres.Conn(ctx, r.pool).QueryRow(ctx,
+		"SELECT user_id, event_id, tx_id, seq, state, created_at FROM user_snapshots WHERE user_id = $1",
+		userID,
+	).Scan(&snapshot.UserID, &snapshot.EventID, &snapshot.Position.TxID, &snapshot.Position.Seq, &state, &snapshot.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return models.Snapshot{}, ErrSnapshotNotFound
+		}
+		return models.Snapshot{}, fmt.Errorf("select snapshot: %w", err)
+	}
+
+	payload, err := models.DecodePayload(models.EventTypeSnapshot, state)
+	if err != nil {
+		return models.Snapshot{}, fmt.Errorf("snapshot of %s: %w", userID, err)
+	}
+	snapshot.State = payload.(models.SnapshotPayload)
+
+	return snapshot, nil
+}
+
+// Save stores the snapshot unless the user already has a newer one.
+func (r *SnapshotRepository) Save(ctx context.Context, snapshot models.Snapshot) error {
+	state, err := models.EncodePayload(models.EventTypeSnapshot, snapshot.State)
+	if err != nil {
+		return err
+	}
+
+	_, err = postgres.Conn(ctx, r.pool).Exec(ctx, `
+		INSERT INTO user_snapshots (user_id, event_id, tx_id, seq, state, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id) DO UPDATE
+		SET event_id = EXCLUDED.event_id, tx_id = EXCLUDED.tx_id, seq = EXCLUDED.seq,
+		    state = EXCLUDED.state, created_at = EXCLUDED.created_at
+		WHERE (user_snapshots.tx_id, user_snapshots.seq) < (EXCLUDED.tx_id, EXCLUDED.seq)`,
+		snapshot.UserID, snapshot.EventID, snapshot.Position.TxID, snapshot.Position.Seq, state, snapshot.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("upsert snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ListStale returns up to limit users with at least minEvents events after
+// their latest snapshot, or without one. It walks the distinct users of the
+// log with a skip scan and counts the events of each only up to minEvents,
+// both on events_user_id_position_idx, so a round reads at most minEvents
+// index entries per user rather than the whole log.
+func (r *SnapshotRepository) ListStale(ctx context.Context, minEvents, limit int32) ([]uuid.UUID, error) {
+	rows, err := postgres.Conn(ctx, r.pool).Query(ctx, `
+		WITH RECURSIVE users AS (
+			(SELECT user_id FROM events ORDER BY user_id LIMIT 1)
+			UNION ALL
+			SELECT (SELECT e.user_id FROM events e WHERE e.user_id > u.user_id ORDER BY e.user_id LIMIT 1)
+			FROM users u
+			WHERE u.user_id IS NOT NULL
+		)
+		SELECT u.user_id
+		FROM users u
+		LEFT JOIN user_snapshots s ON s.user_id = u.user_id
+		CROSS JOIN LATERAL (
+			SELECT count(*) AS n
+			FROM (
+				SELECT 1
+				FROM events e
+				WHERE e.user_id = u.user_id
+				  AND (e.tx_id, e.seq) > (COALESCE(s.tx_id, 0), COALESCE(s.seq, 0))
+				LIMIT GREATEST($1, 1)
+			) AS after_snapshot
+		) AS backlog
+		WHERE u.user_id IS NOT NULL AND backlog.n >= GREATEST($1, 1)
+		LIMIT $2`,
+		minEvents, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select stale snapshots: %w", err)
+	}
+
+	users, err := pgx.CollectRows(rows, pgx.RowTo[uuid.UUID])
+	if err != nil {
+		return nil, fmt.Errorf("scan stale snapshots: %w", err)
+	}
+
+	return users, nil
+}
+
+// Build materializes the current state of the user. It reads the state and
+// the position of the newest event below the visibility horizon in one
+// repeatable read transaction, so the state reflects every event up to
+// that position. Events of transactions at or past the horizon come after
+// the position even if the state already reflects them, so they may be
+// replayed on top of it. A user without an event below the horizon gets a
+// zero snapshot.
+func (r *SnapshotRepository) Build(ctx context.Context, userID uuid.UUID) (models.Snapshot, error) {
+	tx, err := r.pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return models.Snapshot{}, fmt.Errorf("begin snapshot transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback(context.WithoutCancel(ctx))
+	}()
+
+	snapshot := models.Snapshot{UserID: userID, CreatedAt: time.Now().UTC()}
+
+	err = tx.QueryRow(ctx, `
+		SELECT tx_id, seq
+		FROM events
+		WHERE user_id = $1 AND tx_id < `+visibleHorizon+`
+		ORDER BY tx_id DESC, seq DESC
+		LIMIT 1`,
+		userID,
+	).Scan(&snapshot.Position.TxID, &snapshot.Position.Seq)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.Snapshot{UserID: userID}, nil
+	}
+	if err != nil {
+		return models.Snapshot{}, fmt.Errorf("select last event: %w", err)
+	}
+
+	snapshot.EventID, err = uuid.NewV7()
+	if err != nil {
+		return models.Snapshot{}, fmt.Errorf("generate snapshot id: %w", err)
+	}
+
+	chats, err := snapshotChats(ctx, tx, userID)
+	if err != nil {
+		return models.Snapshot{}, err
+	}
+	if err := snapshotMembers(ctx, tx, userID, chats); err != nil {
+		return models.Snapshot{}, err
+	}
+	snapshot.State.Chats = chats
+
+	return snapshot, nil
+}
+
+// snapshotChats returns the chats of the user, most recently active first.
+func snapshotChats(ctx context.Context, tx pgx.Tx, userID uuid.UUID) ([]models.ChatSnapshot, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT c.id, c.name, c.type, c.created_at, c.updated_at,
+		       p.last_message_id, COALESCE(p.unread_count, 0), COALESCE(p.last_activity_at, c.updated_at)
+		FROM chat_members cm
+		JOIN chats c ON c.id = cm.chat_id
+		LEFT JOIN chat_previews p ON p.user_id = cm.user_id AND p.chat_id = cm.chat_id
+		WHERE cm.user_id = $1
+		ORDER BY 8 DESC, c.id DESC`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select snapshot chats: %w", err)
+	}
+
+	chats, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (models.ChatSnapshot, error) {
+		var (
+			c             models.ChatSnapshot
+			lastMessageID *uuid.UUID
+		)
+		err := row.Scan(&c.Chat.ID, &c.Chat.Name, &c.Chat.Type, &c.Chat.CreatedAt, &c.Chat.UpdatedAt,
+			&lastMessageID, &c.UnreadCount, &c.LastActivityAt)
+		if lastMessageID != nil {
+			c.LastMessageID = *lastMessageID
+		}
+		return c, err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scan snapshot chats: %w", err)
+	}
+
+	return chats, nil
+}
+
+// snapshotMembers fills in the members and read cursors of the chats.
+func snapshotMembers(ctx context.Context, tx pgx.Tx, userID uuid.UUID, chats []models.ChatSnapshot) error {
+	rows, err := tx.Query(ctx, `
+		SELECT m.chat_id, m.user_id, m.role, m.joined_at, m.last_read_message_id, m.last_read_at
+		FROM chat_members m
+		WHERE m.chat_id IN (SELECT chat_id FROM chat_members WHERE user_id = $1)
+		ORDER BY m.joined_at, m.user_id`,
+		userID,
+	)
+	if err != nil {
+		return fmt.Errorf("select snapshot members: %w", err)
+	}
+
+	byChat := make(map[uuid.UUID]*models.ChatSnapshot, len(chats))
+	for i := range chats {
+		byChat[chats[i].Chat.ID] = &chats[i]
+	}
+
+	var (
+		member    models.ChatMember
+		messageID *uuid.UUID
+		readAt    *time.Time
+	)
+	_, err = pgx.ForEachRow(rows,
+		[]any{&member.ChatID, &member.UserID, &member.Role, &member.JoinedAt, &messageID, &readAt},
+		func() error {
+			chat, ok := byChat[member.ChatID]
+			if !ok {
+				return nil
+			}
+			chat.Chat.Members = append(chat.Chat.Members, member)
+			if messageID != nil && readAt != nil {
+				chat.ReadStates = append(chat.ReadStates, models.ReadState{
+					UserID:            member.UserID,
+					LastReadMessageID: *messageID,
+					ReadAt:            *readAt,
+				})
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("scan snapshot members: %w", err)
+	}
+
+	return nil
+}
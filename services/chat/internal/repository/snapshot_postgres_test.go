@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func TestSnapshotRepository_BuildSaveLatest(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	snapshots := NewSnapshotRepository(pool)
+	ctx := context.Background()
+
+	alice, bob := uuid.New(), uuid.New()
+	chatID := createTestChat(t, pool, alice, bob)
+
+	_, err := snapshots.Latest(ctx, alice)
+	require.ErrorIs(t, err, ErrSnapshotNotFound)
+
+	_, err = events.AppendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "hi"})
+	require.NoError(t, err)
+	logged, err := events.GetUserEvents(ctx, alice, models.EventPosition{}, 10)
+	require.NoError(t, err)
+	require.Len(t, logged, 1)
+
+	built, err := snapshots.Build(ctx, alice)
+	require.NoError(t, err)
+	require.Len(t, built.State.Chats, 1)
+	assert.Equal(t, chatID, built.State.Chats[0].Chat.ID)
+	assert.Len(t, built.State.Chats[0].Chat.Members, 2)
+
+	assert.Equal(t, logged[0].Position, built.Position)
+	assert.NotEqual(t, uuid.Nil, built.EventID)
+
+	require.NoError(t, snapshots.Save(ctx, built))
+
+	got, err := snapshots.Latest(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, built.EventID, got.EventID)
+	assert.Equal(t, built.Position, got.Position)
+	assert.Equal(t, built.State.Chats[0].Chat.ID, got.State.Chats[0].Chat.ID)
+
+	older := built
+	older.EventID = uuid.Must(uuid.NewV7())
+	older.Position = models.EventPosition{}
+	older.State = models.SnapshotPayload{}
+	require.NoError(t, snapshots.Save(ctx, older))
+
+	got, err = snapshots.Latest(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, built.EventID, got.EventID, "an older snapshot does not replace a newer one")
+}
+
+func TestSnapshotRepository_ListStale(t *testing.T) {
+	pool := newTestPool(t)
+	events := NewEventRepository(pool)
+	snapshots := NewSnapshotRepository(pool)
+	ctx := context.Background()
+
+	alice, bob := uuid.New(), uuid.New()
+	chatID := createTestChat(t, pool, alice)
+	otherChatID := createTestChat(t, pool, bob)
+
+	for range 3 {
+		_, err := events.AppendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "hi"})
+		require.NoError(t, err)
+	}
+	_, err := events.AppendToChat(ctx, otherChatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "hi"})
+	require.NoError(t, err)
+
+	stale, err := snapshots.ListStale(ctx, 3, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{alice}, stale, "bob has too few events")
+
+	for _, userID := range []uuid.UUID{alice, bob} {
+		built, err := snapshots.Build(ctx, userID)
+		require.NoError(t, err)
+		require.NoError(t, snapshots.Save(ctx, built))
+	}
+
+	stale, err = snapshots.ListStale(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Empty(t, stale)
+
+	_, err = events.AppendToChat(ctx, otherChatID, models.EventTypeSystem, models.SystemNotificationPayload{Text: "hi"})
+	require.NoError(t, err)
+
+	stale, err = snapshots.ListStale(ctx, 1, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{bob}, stale, "only the events after the snapshot count")
+}
@@ -0,0 +1,205 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: worker.go
+//
+// Generated by this command:
+//
+//	mockgen -source=worker.go -destination=mocks/mock_worker.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockeventLog is a mock of eventLog interface.
+type MockeventLog struct {
+	ctrl     *gomock.Controller
+	recorder *MockeventLogMockRecorder
+	isgomock struct{}
+}
+
+// MockeventLogMockRecorder is the mock recorder for MockeventLog.
+type MockeventLogMockRecorder struct {
+	mock *MockeventLog
+}
+
+// NewMockeventLog creates a new mock instance.
+func NewMockeventLog(ctrl *gomock.Controller) *MockeventLog {
+	mock := &MockeventLog{ctrl: ctrl}
+	mock.recorder = &MockeventLogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockeventLog) EXPECT() *MockeventLogMockRecorder {
+	return m.recorder
+}
+
+// CollapseSupersededEvents mocks base method.
+func (m *MockeventLog) CollapseSupersededEvents(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CollapseSupersededEvents", ctx, horizon, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CollapseSupersededEvents indicates an expected call of CollapseSupersededEvents.
+func (mr *MockeventLogMockRecorder) CollapseSupersededEvents(ctx, horizon, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CollapseSupersededEvents", reflect.TypeOf((*MockeventLog)(nil).CollapseSupersededEvents), ctx, horizon, limit)
+}
+
+// DeleteCollapsedBefore mocks base method.
+func (m *MockeventLog) DeleteCollapsedBefore(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCollapsedBefore", ctx, horizon, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCollapsedBefore indicates an expected call of DeleteCollapsedBefore.
+func (mr *MockeventLogMockRecorder) DeleteCollapsedBefore(ctx, horizon, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCollapsedBefore", reflect.TypeOf((*MockeventLog)(nil).DeleteCollapsedBefore), ctx, horizon, limit)
+}
+
+// DeleteEventsBefore mocks base method.
+func (m *MockeventLog) DeleteEventsBefore(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEventsBefore", ctx, horizon, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEventsBefore indicates an expected call of DeleteEventsBefore.
+func (mr *MockeventLogMockRecorder) DeleteEventsBefore(ctx, horizon, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEventsBefore", reflect.TypeOf((*MockeventLog)(nil).DeleteEventsBefore), ctx, horizon, limit)
+}
+
+// MockidempotencyKeys is a mock of idempotencyKeys interface.
+type MockidempotencyKeys struct {
+	ctrl     *gomock.Controller
+	recorder *MockidempotencyKeysMockRecorder
+	isgomock struct{}
+}
+
+// MockidempotencyKeysMockRecorder is the mock recorder for MockidempotencyKeys.
+type MockidempotencyKeysMockRecorder struct {
+	mock *MockidempotencyKeys
+}
+
+// NewMockidempotencyKeys creates a new mock instance.
+func NewMockidempotencyKeys(ctrl *gomock.Controller) *MockidempotencyKeys {
+	mock := &MockidempotencyKeys{ctrl: ctrl}
+	mock.recorder = &MockidempotencyKeysMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockidempotencyKeys) EXPECT() *MockidempotencyKeysMockRecorder {
+	return m.recorder
+}
+
+// DeleteKeysBefore mocks base method.
+func (m *MockidempotencyKeys) DeleteKeysBefore(ctx context.Context, before time.Time, limit int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteKeysBefore", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteKeysBefore indicates an expected call of DeleteKeysBefore.
+func (mr *MockidempotencyKeysMockRecorder) DeleteKeysBefore(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteKeysBefore", reflect.TypeOf((*MockidempotencyKeys)(nil).DeleteKeysBefore), ctx, before, limit)
+}
+
+// Mockattachments is a mock of attachments interface.
+type Mockattachments struct {
+	ctrl     *gomock.Controller
+	recorder *MockattachmentsMockRecorder
+	isgomock struct{}
+}
+
+// MockattachmentsMockRecorder is the mock recorder for Mockattachments.
+type MockattachmentsMockRecorder struct {
+	mock *Mockattachments
+}
+
+// NewMockattachments creates a new mock instance.
+func NewMockattachments(ctrl *gomock.Controller) *Mockattachments {
+	mock := &Mockattachments{ctrl: ctrl}
+	mock.recorder = &MockattachmentsMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mockattachments) EXPECT() *MockattachmentsMockRecorder {
+	return m.recorder
+}
+
+// DeleteOrphans mocks base method.
+func (m *Mockattachments) DeleteOrphans(ctx context.Context, before time.Time, limit int32) ([]models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteOrphans", ctx, before, limit)
+	ret0, _ := ret[0].([]models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteOrphans indicates an expected call of DeleteOrphans.
+func (mr *MockattachmentsMockRecorder) DeleteOrphans(ctx, before, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteOrphans", reflect.TypeOf((*Mockattachments)(nil).DeleteOrphans), ctx, before, limit)
+}
+
+// MockblobStore is a mock of blobStore interface.
+type MockblobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockblobStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockblobStoreMockRecorder is the mock recorder for MockblobStore.
+type MockblobStoreMockRecorder struct {
+	mock *MockblobStore
+}
+
+// NewMockblobStore creates a new mock instance.
+func NewMockblobStore(ctrl *gomock.Controller) *MockblobStore {
+	mock := &MockblobStore{ctrl: ctrl}
+	mock.recorder = &MockblobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockblobStore) EXPECT() *MockblobStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockblobStore) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockblobStoreMockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockblobStore)(nil).Delete), ctx, key)
+}
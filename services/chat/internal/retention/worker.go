@@ -0,0 +1,223 @@
+package retention
+
+import (
+	"context"
+	"encoding/binary"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+const defaultBatchSize = 1000
+
+//go:generate mockgen -source=worker.go -destination=mocks/mock_worker.go -package=mocks
+
+type eventLog interface {
+	DeleteEventsBefore(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error)
+	CollapseSupersededEvents(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error)
+	DeleteCollapsedBefore(ctx context.Context, horizon uuid.UUID, limit int32) (int64, error)
+}
+
+type idempotencyKeys interface {
+	DeleteKeysBefore(ctx context.Context, before time.Time, limit int32) (int64, error)
+}
+
+type attachments interface {
+	DeleteOrphans(ctx context.Context, before time.Time, limit int32) ([]models.Attachment, error)
+}
+
+type blobStore interface {
+	Delete(ctx context.Context, key string) error
+}
+
+type Config struct {
+	// TTL is how long events stay in the log. Zero keeps them forever.
+	TTL time.Duration
+	// Interval is how often expired and superseded events are deleted.
+	Interval time.Duration
+	// CollapseAfter is the age from which superseded events are collapsed,
+	// so the worker leaves the busy tail of the log alone.
+	CollapseAfter time.Duration
+	// IdempotencyTTL is how long SendMessage idempotency keys are kept. Zero
+	// keeps them forever.
+	IdempotencyTTL time.Duration
+	// OrphanAttachmentsAfter is the age from which attachments no message
+	// refers to are deleted with their blobs. It leaves uploaders time to
+	// send them. Zero keeps them forever.
+	OrphanAttachmentsAfter time.Duration
+	// BatchSize bounds the events deleted by one statement. Zero uses a
+	// default.
+	BatchSize int32
+}
+
+// Worker keeps the event log bounded. It deletes the events older than the
+// TTL and collapses the ones later events supersede. It also deletes the
+// expired idempotency keys and the orphan attachments.
+type Worker struct {
+	events      eventLog
+	keys        idempotencyKeys
+	attachments attachments
+	blobs       blobStore
+	config      Config
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+func NewWorker(
+	events eventLog,
+	keys idempotencyKeys,
+	attachments attachments,
+	blobs blobStore,
+	config Config,
+	logger *slog.Logger,
+) *Worker {
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultBatchSize
+	}
+
+	return &Worker{
+		events:      events,
+		keys:        keys,
+		attachments: attachments,
+		blobs:       blobs,
+		config:      config,
+		logger:      logger.With("layer", "retention worker"),
+		now:         time.Now,
+	}
+}
+
+// OldestRetainedEventID returns the ID every retained event is at least.
+// Events before it may be gone, so a client whose cursor is older may have
+// missed events. It is uuid.Nil without a TTL.
+func (w *Worker) OldestRetainedEventID() uuid.UUID {
+	if w.config.TTL <= 0 {
+		return uuid.Nil
+	}
+
+	return horizonAt(w.now().Add(-w.config.TTL))
+}
+
+// Run cleans the log every interval until ctx is done. A failed round is
+// logged and retried on the next tick.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				w.logger.Error("retention round", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// RunOnce deletes the expired events and the positions of the expired
+// collapsed ones, then collapses the superseded events, then deletes the
+// expired idempotency keys and the orphan attachments, batch by batch until
+// none are left.
+func (w *Worker) RunOnce(ctx context.Context) error {
+	if w.config.TTL > 0 {
+		expired, err := drain(ctx, w.config.BatchSize, w.events.DeleteEventsBefore, w.OldestRetainedEventID())
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			w.logger.Info("deleted expired events", slog.Int64("count", expired))
+		}
+
+		// A cursor on a collapsed event is older than the TTL now, too.
+		if _, err := drain(ctx, w.config.BatchSize, w.events.DeleteCollapsedBefore, w.OldestRetainedEventID()); err != nil {
+			return err
+		}
+	}
+
+	collapsed, err := drain(ctx, w.config.BatchSize, w.events.CollapseSupersededEvents, horizonAt(w.now().Add(-w.config.CollapseAfter)))
+	if err != nil {
+		return err
+	}
+	if collapsed > 0 {
+		w.logger.Info("collapsed superseded events", slog.Int64("count", collapsed))
+	}
+
+	if w.config.IdempotencyTTL > 0 {
+		expired, err := drain(ctx, w.config.BatchSize, w.keys.DeleteKeysBefore, w.now().Add(-w.config.IdempotencyTTL))
+		if err != nil {
+			return err
+		}
+		if expired > 0 {
+			w.logger.Info("deleted expired idempotency keys", slog.Int64("count", expired))
+		}
+	}
+
+	if w.config.OrphanAttachmentsAfter > 0 {
+		orphans, err := drain(ctx, w.config.BatchSize, w.deleteOrphanAttachments, w.now().Add(-w.config.OrphanAttachmentsAfter))
+		if err != nil {
+			return err
+		}
+		if orphans > 0 {
+			w.logger.Info("deleted orphan attachments", slog.Int64("count", orphans))
+		}
+	}
+
+	return nil
+}
+
+// deleteOrphanAttachments deletes a batch of orphan attachments, then their
+// blobs. A blob that cannot be deleted is logged and left behind, because
+// its attachment is already gone.
+func (w *Worker) deleteOrphanAttachments(ctx context.Context, before time.Time, limit int32) (int64, error) {
+	orphans, err := w.attachments.DeleteOrphans(ctx, before, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, attachment := range orphans {
+		if err := w.blobs.Delete(ctx, attachment.ID.String()); err != nil {
+			w.logger.Error("delete attachment blob",
+				slog.String("key", attachment.ID.String()),
+				slog.String("error", err.Error()))
+		}
+	}
+
+	return int64(len(orphans)), nil
+}
+
+func drain[T any](
+	ctx context.Context,
+	batchSize int32,
+	deleteBatch func(ctx context.Context, horizon T, limit int32) (int64, error),
+	horizon T,
+) (int64, error) {
+	var total int64
+	for {
+		n, err := deleteBatch(ctx, horizon, batchSize)
+		if err != nil {
+			return total, err
+		}
+		total += n
+		if n < int64(batchSize) {
+			return total, nil
+		}
+	}
+}
+
+// horizonAt returns the smallest UUIDv7 of the millisecond t falls in:
+// every event created at or after t has a greater ID, every event created
+// before it a smaller one.
+func horizonAt(t time.Time) uuid.UUID {
+	var id uuid.UUID
+	var ms [8]byte
+	binary.BigEndian.PutUint64(ms[:], uint64(t.UnixMilli()))
+	copy(id[:6], ms[2:])
+	id[6] = 0x70
+	id[8] = 0x80
+
+	return id
+}
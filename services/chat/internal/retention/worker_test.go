@@ -0,0 +1,130 @@
+package retention
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/retention/mocks"
+)
+
+var testNow = time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+type workerMocks struct {
+	events      *mocks.MockeventLog
+	keys        *mocks.MockidempotencyKeys
+	attachments *mocks.Mockattachments
+	blobs       *mocks.MockblobStore
+}
+
+func newTestWorker(t *testing.T, config Config) (*Worker, workerMocks) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	m := workerMocks{
+		events:      mocks.NewMockeventLog(ctrl),
+		keys:        mocks.NewMockidempotencyKeys(ctrl),
+		attachments: mocks.NewMockattachments(ctrl),
+		blobs:       mocks.NewMockblobStore(ctrl),
+	}
+	worker := NewWorker(m.events, m.keys, m.attachments, m.blobs, config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	worker.now = func() time.Time { return testNow }
+
+	return worker, m
+}
+
+func TestWorker_RunOnceDeletesInBatches(t *testing.T) {
+	worker, m := newTestWorker(t, Config{TTL: 24 * time.Hour, CollapseAfter: time.Hour, BatchSize: 10})
+	ctx := context.Background()
+	expired := horizonAt(testNow.Add(-24 * time.Hour))
+	settled := horizonAt(testNow.Add(-time.Hour))
+
+	gomock.InOrder(
+		m.events.EXPECT().DeleteEventsBefore(ctx, expired, int32(10)).Return(int64(10), nil),
+		m.events.EXPECT().DeleteEventsBefore(ctx, expired, int32(10)).Return(int64(4), nil),
+		m.events.EXPECT().DeleteCollapsedBefore(ctx, expired, int32(10)).Return(int64(3), nil),
+		m.events.EXPECT().CollapseSupersededEvents(ctx, settled, int32(10)).Return(int64(0), nil),
+	)
+
+	require.NoError(t, worker.RunOnce(ctx))
+}
+
+func TestWorker_RunOnceWithoutTTLOnlyCollapses(t *testing.T) {
+	worker, m := newTestWorker(t, Config{})
+	ctx := context.Background()
+
+	m.events.EXPECT().CollapseSupersededEvents(ctx, horizonAt(testNow), int32(defaultBatchSize)).Return(int64(2), nil)
+
+	require.NoError(t, worker.RunOnce(ctx))
+	assert.Equal(t, uuid.Nil, worker.OldestRetainedEventID())
+}
+
+func TestWorker_RunOnceDeletesExpiredIdempotencyKeys(t *testing.T) {
+	worker, m := newTestWorker(t, Config{IdempotencyTTL: 24 * time.Hour, BatchSize: 10})
+	ctx := context.Background()
+
+	gomock.InOrder(
+		m.events.EXPECT().CollapseSupersededEvents(ctx, gomock.Any(), int32(10)).Return(int64(0), nil),
+		m.keys.EXPECT().DeleteKeysBefore(ctx, testNow.Add(-24*time.Hour), int32(10)).Return(int64(10), nil),
+		m.keys.EXPECT().DeleteKeysBefore(ctx, testNow.Add(-24*time.Hour), int32(10)).Return(int64(3), nil),
+	)
+
+	require.NoError(t, worker.RunOnce(ctx))
+}
+
+func TestWorker_RunOnceDeletesOrphanAttachments(t *testing.T) {
+	worker, m := newTestWorker(t, Config{OrphanAttachmentsAfter: time.Hour, BatchSize: 2})
+	ctx := context.Background()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+
+	gomock.InOrder(
+		m.events.EXPECT().CollapseSupersededEvents(ctx, gomock.Any(), int32(2)).Return(int64(0), nil),
+		m.attachments.EXPECT().DeleteOrphans(ctx, testNow.Add(-time.Hour), int32(2)).
+			Return([]models.Attachment{{ID: first}, {ID: second}}, nil),
+		m.blobs.EXPECT().Delete(ctx, first.String()).Return(nil),
+		m.blobs.EXPECT().Delete(ctx, second.String()).Return(assert.AnError),
+		m.attachments.EXPECT().DeleteOrphans(ctx, testNow.Add(-time.Hour), int32(2)).
+			Return([]models.Attachment{{ID: third}}, nil),
+		m.blobs.EXPECT().Delete(ctx, third.String()).Return(nil),
+	)
+
+	require.NoError(t, worker.RunOnce(ctx), "a blob left behind does not fail the round")
+}
+
+func TestWorker_RunOnceFails(t *testing.T) {
+	worker, m := newTestWorker(t, Config{TTL: time.Hour})
+	ctx := context.Background()
+
+	m.events.EXPECT().DeleteEventsBefore(ctx, gomock.Any(), gomock.Any()).Return(int64(0), assert.AnError)
+
+	assert.ErrorIs(t, worker.RunOnce(ctx), assert.AnError)
+}
+
+func TestWorker_OldestRetainedEventID(t *testing.T) {
+	worker, _ := newTestWorker(t, Config{TTL: time.Hour})
+
+	got := worker.OldestRetainedEventID()
+
+	assert.Equal(t, uuid.Version(7), got.Version())
+	sec, nsec := got.Time().UnixTime()
+	assert.Equal(t, testNow.Add(-time.Hour), time.Unix(sec, nsec).UTC())
+}
+
+func TestHorizonAt(t *testing.T) {
+	start := time.Now().Add(-time.Millisecond)
+	id := uuid.Must(uuid.NewV7())
+
+	before, after := horizonAt(start), horizonAt(start.Add(time.Second))
+
+	assert.Positive(t, bytes.Compare(id[:], before[:]), "events from the horizon time on are retained")
+	assert.Negative(t, bytes.Compare(id[:], after[:]), "older events are not")
+}
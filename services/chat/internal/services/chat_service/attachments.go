@@ -0,0 +1,185 @@
+package chatservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+// UploadAttachment stores the req.Size bytes read from data as an
+// attachment of the chat. The upload is checked against the size limit and
+// the uploader's quota before the bytes are read, and the quota is charged
+// only once they are stored.
+func (s *ChatService) UploadAttachment(
+	ctx context.Context,
+	req models.UploadAttachmentRequest,
+	data io.Reader,
+) (models.UploadAttachmentResponse, error) {
+	if req.Size <= 0 {
+		return models.UploadAttachmentResponse{}, fmt.Errorf("%w: size must be positive", ErrInvalidAttachment)
+	}
+	if s.config.MaxAttachmentSize > 0 && req.Size > s.config.MaxAttachmentSize {
+		return models.UploadAttachmentResponse{}, fmt.Errorf("%w: an attachment has at most %d bytes",
+			ErrInvalidAttachment, s.config.MaxAttachmentSize)
+	}
+	if err := s.requireMember(ctx, req.ChatID, req.UploaderID); err != nil {
+		return models.UploadAttachmentResponse{}, err
+	}
+
+	quota := s.config.AttachmentQuota
+	if quota <= 0 {
+		quota = math.MaxInt64
+	}
+	used, err := s.attachments.Usage(ctx, req.UploaderID)
+	if err != nil {
+		return models.UploadAttachmentResponse{}, err
+	}
+	if req.Size > quota-used {
+		return models.UploadAttachmentResponse{}, ErrQuotaExceeded
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return models.UploadAttachmentResponse{}, fmt.Errorf("generate attachment id: %w", err)
+	}
+
+	key := id.String()
+	if _, err := s.blobs.Put(ctx, key, &sizedReader{r: data, remaining: req.Size}); err != nil {
+		return models.UploadAttachmentResponse{}, fmt.Errorf("store attachment: %w", err)
+	}
+
+	attachment := models.Attachment{
+		ID:         id,
+		ChatID:     req.ChatID,
+		UploaderID: req.UploaderID,
+		Size:       req.Size,
+		CreatedAt:  s.now().UTC().Truncate(time.Microsecond),
+	}
+	created, err := s.attachments.Create(ctx, attachment, quota)
+	if err == nil && !created {
+		err = ErrQuotaExceeded
+	}
+	if err != nil {
+		s.deleteBlob(key)
+		return models.UploadAttachmentResponse{}, err
+	}
+
+	return models.UploadAttachmentResponse{Attachment: attachment}, nil
+}
+
+// deleteBlob removes the bytes of an attachment that was not stored. It
+// runs without the request context, which may be the reason the upload
+// failed.
+func (s *ChatService) deleteBlob(key string) {
+	if err := s.blobs.Delete(context.Background(), key); err != nil {
+		s.logger.Error("delete attachment blob", slog.String("key", key), slog.String("error", err.Error()))
+	}
+}
+
+// DownloadAttachment opens the requested range of the attachment for a
+// member of its chat. An attachment no message refers to, because it was
+// not sent yet or its message was deleted, is only open to its uploader.
+func (s *ChatService) DownloadAttachment(
+	ctx context.Context,
+	req models.DownloadAttachmentRequest,
+) (models.DownloadAttachmentResponse, error) {
+	attachment, err := s.attachments.Get(ctx, req.AttachmentID)
+	if err != nil {
+		return models.DownloadAttachmentResponse{}, err
+	}
+	if err := s.requireMember(ctx, attachment.ChatID, req.UserID); err != nil {
+		return models.DownloadAttachmentResponse{}, err
+	}
+	if attachment.UploaderID != req.UserID {
+		referenced, err := s.attachments.IsReferenced(ctx, attachment.ID)
+		if err != nil {
+			return models.DownloadAttachmentResponse{}, err
+		}
+		if !referenced {
+			return models.DownloadAttachmentResponse{}, repository.ErrAttachmentNotFound
+		}
+	}
+	if req.Offset < 0 || req.Length < 0 || req.Offset > attachment.Size {
+		return models.DownloadAttachmentResponse{}, ErrInvalidRange
+	}
+
+	length := attachment.Size - req.Offset
+	if req.Length > 0 && req.Length < length {
+		length = req.Length
+	}
+
+	data, err := s.blobs.Open(ctx, attachment.ID.String(), req.Offset, length)
+	if err != nil {
+		return models.DownloadAttachmentResponse{}, fmt.Errorf("open attachment: %w", err)
+	}
+
+	return models.DownloadAttachmentResponse{
+		Attachment: attachment,
+		Offset:     req.Offset,
+		Data:       data,
+	}, nil
+}
+
+// requireAttachment checks that the attachment of the content was uploaded
+// by the sender to the chat of the message, and keeps it from being swept
+// as an orphan before the message is stored.
+func (s *ChatService) requireAttachment(
+	ctx context.Context,
+	content models.MessageContent,
+	chatID, senderID uuid.UUID,
+) error {
+	if content.AttachmentID == nil {
+		return nil
+	}
+
+	attachment, err := s.attachments.GetForShare(ctx, *content.AttachmentID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAttachmentNotFound) {
+			return fmt.Errorf("%w: attachment not found", ErrInvalidAttachment)
+		}
+		return err
+	}
+	if attachment.ChatID != chatID || attachment.UploaderID != senderID {
+		return fmt.Errorf("%w: the attachment was not uploaded by the sender to this chat", ErrInvalidAttachment)
+	}
+
+	return nil
+}
+
+// sizedReader reads exactly remaining bytes from r and fails if r holds
+// fewer or more.
+type sizedReader struct {
+	r         io.Reader
+	remaining int64
+	probe     [1]byte
+}
+
+func (r *sizedReader) Read(p []byte) (int, error) {
+	if r.remaining == 0 {
+		n, err := r.r.Read(r.probe[:])
+		if n > 0 {
+			return 0, fmt.Errorf("%w: more bytes than the declared size", ErrInvalidAttachment)
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err := r.r.Read(p)
+	r.remaining -= int64(n)
+	if errors.Is(err, io.EOF) && r.remaining > 0 {
+		return n, fmt.Errorf("%w: fewer bytes than the declared size", ErrInvalidAttachment)
+	}
+
+	return n, err
+}
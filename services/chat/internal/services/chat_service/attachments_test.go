@@ -0,0 +1,314 @@
+package chatservice
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+const (
+	testMaxAttachmentSize = 100
+	testAttachmentQuota   = 250
+)
+
+func testUploadRequest(size int64) models.UploadAttachmentRequest {
+	return models.UploadAttachmentRequest{ChatID: testChatID, UploaderID: testUserID, Size: size}
+}
+
+// expectPut reads the blob like a store would and keeps its bytes.
+func expectPut(m chatServiceMocks, stored *bytes.Buffer) *gomock.Call {
+	return m.blobs.EXPECT().
+		Put(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ string, r io.Reader) (int64, error) {
+			return io.Copy(stored, r)
+		})
+}
+
+func TestChatService_UploadAttachment(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+	m.attachments.EXPECT().Usage(ctx, testUserID).Return(int64(200), nil)
+	var stored bytes.Buffer
+	expectPut(m, &stored)
+
+	var created models.Attachment
+	m.attachments.EXPECT().
+		Create(ctx, gomock.Any(), int64(testAttachmentQuota)).
+		DoAndReturn(func(_ context.Context, attachment models.Attachment, _ int64) (bool, error) {
+			created = attachment
+			return true, nil
+		})
+
+	resp, err := service.UploadAttachment(ctx, testUploadRequest(5), strings.NewReader("hello"))
+
+	require.NoError(t, err)
+	assert.Equal(t, created, resp.Attachment)
+	assert.Equal(t, "hello", stored.String())
+	assert.Equal(t, testChatID, created.ChatID)
+	assert.Equal(t, testUserID, created.UploaderID)
+	assert.Equal(t, int64(5), created.Size)
+	assert.Equal(t, testNow, created.CreatedAt)
+}
+
+func TestChatService_UploadAttachmentRejects(t *testing.T) {
+	tests := map[string]struct {
+		size int64
+		used int64
+		want error
+	}{
+		"empty":          {size: 0, want: ErrInvalidAttachment},
+		"too large":      {size: testMaxAttachmentSize + 1, want: ErrInvalidAttachment},
+		"quota exceeded": {size: 60, used: 200, want: ErrQuotaExceeded},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+
+			m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil).AnyTimes()
+			m.attachments.EXPECT().Usage(ctx, testUserID).Return(tt.used, nil).AnyTimes()
+
+			_, err := service.UploadAttachment(ctx, testUploadRequest(tt.size), strings.NewReader("hello"))
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestChatService_UploadAttachmentSizeMismatch(t *testing.T) {
+	tests := map[string]string{
+		"fewer bytes": "hell",
+		"more bytes":  "hello!",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+
+			m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+			m.attachments.EXPECT().Usage(ctx, testUserID).Return(int64(0), nil)
+			var stored bytes.Buffer
+			expectPut(m, &stored)
+
+			_, err := service.UploadAttachment(ctx, testUploadRequest(5), strings.NewReader(data))
+
+			assert.ErrorIs(t, err, ErrInvalidAttachment)
+		})
+	}
+}
+
+func TestChatService_UploadAttachmentQuotaRace(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+	m.attachments.EXPECT().Usage(ctx, testUserID).Return(int64(0), nil)
+	var key string
+	m.blobs.EXPECT().
+		Put(ctx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, k string, r io.Reader) (int64, error) {
+			key = k
+			return io.Copy(io.Discard, r)
+		})
+	m.attachments.EXPECT().Create(ctx, gomock.Any(), gomock.Any()).Return(false, nil)
+	m.blobs.EXPECT().
+		Delete(gomock.Any(), gomock.Any()).
+		Do(func(_ context.Context, k string) {
+			assert.Equal(t, key, k, "the bytes of a rejected upload are deleted")
+		})
+
+	_, err := service.UploadAttachment(ctx, testUploadRequest(5), strings.NewReader("hello"))
+
+	assert.ErrorIs(t, err, ErrQuotaExceeded)
+}
+
+func TestChatService_UploadAttachmentNotMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, repository.ErrMemberNotFound)
+
+	_, err := service.UploadAttachment(ctx, testUploadRequest(5), strings.NewReader("hello"))
+
+	assert.ErrorIs(t, err, ErrNotMember)
+}
+
+func testAttachment() models.Attachment {
+	return models.Attachment{ID: uuid.Must(uuid.NewV7()), ChatID: testChatID, UploaderID: testUserID, Size: 10}
+}
+
+func TestChatService_DownloadAttachment(t *testing.T) {
+	tests := map[string]struct {
+		offset, length int64
+		wantLength     int64
+	}{
+		"whole":        {offset: 0, length: 0, wantLength: 10},
+		"range":        {offset: 2, length: 3, wantLength: 3},
+		"to the end":   {offset: 4, length: 0, wantLength: 6},
+		"past the end": {offset: 8, length: 5, wantLength: 2},
+		"empty tail":   {offset: 10, length: 0, wantLength: 0},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+			attachment := testAttachment()
+			data := io.NopCloser(strings.NewReader(""))
+
+			m.attachments.EXPECT().Get(ctx, attachment.ID).Return(attachment, nil)
+			m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+			m.blobs.EXPECT().Open(ctx, attachment.ID.String(), tt.offset, tt.wantLength).Return(data, nil)
+
+			resp, err := service.DownloadAttachment(ctx, models.DownloadAttachmentRequest{
+				AttachmentID: attachment.ID,
+				UserID:       testUserID,
+				Offset:       tt.offset,
+				Length:       tt.length,
+			})
+
+			require.NoError(t, err)
+			assert.Equal(t, attachment, resp.Attachment)
+			assert.Equal(t, tt.offset, resp.Offset)
+			assert.Equal(t, data, resp.Data)
+		})
+	}
+}
+
+func TestChatService_DownloadAttachmentRejects(t *testing.T) {
+	tests := map[string]struct {
+		offset, length int64
+	}{
+		"negative offset": {offset: -1},
+		"negative length": {length: -1},
+		"offset past end": {offset: 11},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+			attachment := testAttachment()
+
+			m.attachments.EXPECT().Get(ctx, attachment.ID).Return(attachment, nil)
+			m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+
+			_, err := service.DownloadAttachment(ctx, models.DownloadAttachmentRequest{
+				AttachmentID: attachment.ID,
+				UserID:       testUserID,
+				Offset:       tt.offset,
+				Length:       tt.length,
+			})
+
+			assert.ErrorIs(t, err, ErrInvalidRange)
+		})
+	}
+}
+
+func TestChatService_DownloadAttachmentNotMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	attachment := testAttachment()
+
+	m.attachments.EXPECT().Get(ctx, attachment.ID).Return(attachment, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, repository.ErrMemberNotFound)
+
+	_, err := service.DownloadAttachment(ctx, models.DownloadAttachmentRequest{AttachmentID: attachment.ID, UserID: testUserID})
+
+	assert.ErrorIs(t, err, ErrNotMember)
+}
+
+func TestChatService_DownloadAttachmentOfOtherMember(t *testing.T) {
+	tests := map[string]struct {
+		referenced bool
+		wantErr    error
+	}{
+		"sent":     {referenced: true},
+		"not sent": {referenced: false, wantErr: repository.ErrAttachmentNotFound},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+			attachment := testAttachment()
+			attachment.UploaderID = uuid.New()
+
+			m.attachments.EXPECT().Get(ctx, attachment.ID).Return(attachment, nil)
+			m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+			m.attachments.EXPECT().IsReferenced(ctx, attachment.ID).Return(tt.referenced, nil)
+			if tt.wantErr == nil {
+				m.blobs.EXPECT().Open(ctx, attachment.ID.String(), int64(0), attachment.Size).
+					Return(io.NopCloser(strings.NewReader("")), nil)
+			}
+
+			_, err := service.DownloadAttachment(ctx, models.DownloadAttachmentRequest{AttachmentID: attachment.ID, UserID: testUserID})
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "a deleted or unsent attachment is only open to its uploader")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestChatService_SendMessageWithAttachmentRejects(t *testing.T) {
+	tests := map[string]func(attachment *models.Attachment){
+		"other chat":     func(attachment *models.Attachment) { attachment.ChatID = uuid.New() },
+		"other uploader": func(attachment *models.Attachment) { attachment.UploaderID = uuid.New() },
+	}
+
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+			attachment := testAttachment()
+			change(&attachment)
+			req := testSendMessageRequest()
+			req.IdempotencyKey = ""
+			req.Content = models.MessageContent{
+				Type:         models.ContentTypeImage,
+				Ciphertext:   []byte("key"),
+				AttachmentID: &attachment.ID,
+			}
+
+			m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+			m.attachments.EXPECT().GetForShare(ctx, attachment.ID).Return(attachment, nil)
+
+			_, err := service.SendMessage(ctx, req)
+
+			assert.ErrorIs(t, err, ErrInvalidAttachment)
+		})
+	}
+}
+
+func TestChatService_SendMessageWithMissingAttachment(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	attachmentID := uuid.New()
+	req := testSendMessageRequest()
+	req.IdempotencyKey = ""
+	req.Content = models.MessageContent{Type: models.ContentTypeFile, Ciphertext: []byte("key"), AttachmentID: &attachmentID}
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+	m.attachments.EXPECT().GetForShare(ctx, attachmentID).Return(models.Attachment{}, repository.ErrAttachmentNotFound)
+
+	_, err := service.SendMessage(ctx, req)
+
+	assert.ErrorIs(t, err, ErrInvalidAttachment)
+}
@@ -0,0 +1,116 @@
+package chatservice
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+// OverflowAction is what a full live buffer does with an event.
+type OverflowAction int
+
+const (
+	// OverflowDisconnect ends the stream; the client resumes from the log.
+	OverflowDisconnect OverflowAction = iota
+	// OverflowDrop loses the event. Only events a client can live without,
+	// such as typing, should be dropped.
+	OverflowDrop
+	// OverflowCoalesce replaces a pending event that the new one supersedes,
+	// such as an older read receipt of the same reader.
+	OverflowCoalesce
+)
+
+// BackpressurePolicy maps event types to what a full live buffer does with
+// them. Types it does not list disconnect the stream.
+type BackpressurePolicy map[models.EventType]OverflowAction
+
+// DefaultBackpressurePolicy drops typing events and coalesces read receipts.
+func DefaultBackpressurePolicy() BackpressurePolicy {
+	return BackpressurePolicy{
+		models.EventTypeTyping:      OverflowDrop,
+		models.EventTypeReadReceipt: OverflowCoalesce,
+	}
+}
+
+// liveBuffer holds the live events a subscriber has not received yet. Once
+// it is full, an incoming event is dropped, replaces the pending event it
+// supersedes, or takes the place of a pending event that may be dropped;
+// when none of that applies push fails and the stream has to end.
+type liveBuffer struct {
+	size   int
+	policy BackpressurePolicy
+	ready  chan struct{}
+
+	mu     sync.Mutex
+	events []models.Event
+}
+
+func newLiveBuffer(size int, policy BackpressurePolicy) *liveBuffer {
+	return &liveBuffer{
+		size:   size,
+		policy: policy,
+		ready:  make(chan struct{}, 1),
+	}
+}
+
+func (b *liveBuffer) push(ev models.Event) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.events) >= b.size {
+		action := b.policy[ev.Type]
+		if action == OverflowDrop {
+			return true
+		}
+
+		i := -1
+		if action == OverflowCoalesce {
+			i = slices.IndexFunc(b.events, func(pending models.Event) bool {
+				return supersedes(ev, pending)
+			})
+		}
+		if i < 0 {
+			i = slices.IndexFunc(b.events, func(pending models.Event) bool {
+				return b.policy[pending.Type] == OverflowDrop
+			})
+		}
+		if i < 0 {
+			return false
+		}
+
+		b.events = slices.Delete(b.events, i, i+1)
+	}
+
+	b.events = append(b.events, ev)
+	select {
+	case b.ready <- struct{}{}:
+	default:
+	}
+
+	return true
+}
+
+// take empties the buffer and returns what it held, oldest first.
+func (b *liveBuffer) take() []models.Event {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	events := b.events
+	b.events = nil
+
+	return events
+}
+
+// supersedes reports whether ev makes the pending event useless to the
+// client. A read receipt replaces the earlier receipt of the same reader in
+// the same chat, since read cursors only move forward.
+func supersedes(ev, pending models.Event) bool {
+	next, ok := ev.Payload.(models.ReadReceiptPayload)
+	if !ok {
+		return false
+	}
+	prev, ok := pending.Payload.(models.ReadReceiptPayload)
+
+	return ok && prev.ChatID == next.ChatID && prev.UserID == next.UserID
+}
@@ -0,0 +1,66 @@
+package chatservice
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func receiptEvent(readerID uuid.UUID) models.Event {
+	return models.Event{
+		ID:      uuid.Must(uuid.NewV7()),
+		ChatID:  &testChatID,
+		Type:    models.EventTypeReadReceipt,
+		Payload: models.ReadReceiptPayload{ChatID: testChatID, UserID: readerID, MessageID: uuid.Must(uuid.NewV7())},
+	}
+}
+
+func TestLiveBuffer_DropsTyping(t *testing.T) {
+	buffer := newLiveBuffer(2, DefaultBackpressurePolicy())
+	events := newEvents(2, models.EventTypeMessageNew)
+
+	require.True(t, buffer.push(events[0]))
+	require.True(t, buffer.push(events[1]))
+	require.True(t, buffer.push(newEvents(1, models.EventTypeTyping)[0]))
+
+	assert.Equal(t, events, buffer.take())
+}
+
+func TestLiveBuffer_CoalescesReadReceipts(t *testing.T) {
+	buffer := newLiveBuffer(3, DefaultBackpressurePolicy())
+	message := newEvents(1, models.EventTypeMessageNew)[0]
+	first, other := receiptEvent(testAdminID), receiptEvent(testMemberID)
+	second := receiptEvent(testAdminID)
+
+	require.True(t, buffer.push(first))
+	require.True(t, buffer.push(message))
+	require.True(t, buffer.push(other))
+	require.True(t, buffer.push(second))
+
+	assert.Equal(t, []models.Event{message, other, second}, buffer.take(), "the newer receipt goes last")
+}
+
+func TestLiveBuffer_EvictsTypingForDurableEvents(t *testing.T) {
+	buffer := newLiveBuffer(2, DefaultBackpressurePolicy())
+	typing := newEvents(1, models.EventTypeTyping)[0]
+	events := newEvents(3, models.EventTypeMessageNew)
+
+	require.True(t, buffer.push(typing))
+	require.True(t, buffer.push(events[0]))
+	require.True(t, buffer.push(events[1]))
+	assert.False(t, buffer.push(events[2]), "nothing is left to drop")
+
+	assert.Equal(t, events[:2], buffer.take())
+}
+
+func TestLiveBuffer_DisconnectPolicy(t *testing.T) {
+	buffer := newLiveBuffer(1, BackpressurePolicy{})
+
+	require.True(t, buffer.push(receiptEvent(testAdminID)))
+	assert.False(t, buffer.push(receiptEvent(testAdminID)))
+	assert.False(t, buffer.push(newEvents(1, models.EventTypeTyping)[0]))
+}
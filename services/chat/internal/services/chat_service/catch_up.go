@@ -0,0 +1,164 @@
+package chatservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+const resyncText = "too many events were missed to replay them; reload the chats and their history"
+
+// catchUpFrom returns the position the replay of a subscription starts
+// from and the ID of the last event the client has from before it. A client
+// without a cursor starts at the head of the user's log. When the events
+// after the client's cursor were compacted or are too many to replay, the
+// client gets the user's snapshot and the replay starts after it; without a
+// snapshot with few enough events after it, the client is told to resync
+// and starts at the head instead.
+//
+// The ID returned is always one the client can resume from: a stored
+// event, the snapshot, or uuid.Nil while the user's log is empty.
+func (s *ChatService) catchUpFrom(
+	ctx context.Context,
+	req models.SubscribeRequest,
+	send func(models.Event) error,
+) (models.EventPosition, uuid.UUID, error) {
+	if req.LastEventID == uuid.Nil {
+		return s.userHead(ctx, req.Handle.UserID)
+	}
+
+	snapshot, hasSnapshot, err := s.latestSnapshot(ctx, req.Handle.UserID)
+	if err != nil {
+		return models.EventPosition{}, uuid.Nil, err
+	}
+
+	cursor, known, err := s.cursorPosition(ctx, req, snapshot)
+	if err != nil {
+		return models.EventPosition{}, uuid.Nil, err
+	}
+
+	behind := !known
+	if known {
+		behind, err = s.tooFarBehind(ctx, req, cursor)
+		if err != nil {
+			return models.EventPosition{}, uuid.Nil, err
+		}
+	}
+	if !behind {
+		return cursor, req.LastEventID, nil
+	}
+
+	// The client starts from the snapshot unless it is past it already, or
+	// the snapshot is too far behind itself.
+	if hasSnapshot && (!known || snapshot.Position.After(cursor)) {
+		stale, err := s.tooFarBehind(ctx, req, snapshot.Position)
+		if err != nil {
+			return models.EventPosition{}, uuid.Nil, err
+		}
+		if !stale {
+			if err := send(snapshot.Event()); err != nil {
+				return models.EventPosition{}, uuid.Nil, err
+			}
+			return snapshot.Position, snapshot.EventID, nil
+		}
+	}
+
+	head, headID, err := s.userHead(ctx, req.Handle.UserID)
+	if err != nil {
+		return models.EventPosition{}, uuid.Nil, err
+	}
+
+	// The notification is not stored, so it has no ID to resume from.
+	err = send(models.Event{
+		UserID: req.Handle.UserID,
+		Type:   models.EventTypeSystem,
+		Payload: models.SystemNotificationPayload{
+			Text:           resyncText,
+			Level:          models.SystemNotificationLevelWarning,
+			ResyncRequired: true,
+		},
+		CreatedAt: s.now().UTC(),
+	})
+	if err != nil {
+		return models.EventPosition{}, uuid.Nil, err
+	}
+
+	return head, headID, nil
+}
+
+// userHead returns the position and the ID of the last event of the user
+// that the log returns now. The events after it are the ones a client
+// starting now has not seen.
+func (s *ChatService) userHead(ctx context.Context, userID uuid.UUID) (models.EventPosition, uuid.UUID, error) {
+	position, id, err := s.eventStore.UserHead(ctx, userID)
+	if err != nil {
+		return models.EventPosition{}, uuid.Nil, fmt.Errorf("find head of log: %w", err)
+	}
+
+	return position, id, nil
+}
+
+// latestSnapshot returns the user's snapshot, and false when the user has
+// none.
+func (s *ChatService) latestSnapshot(ctx context.Context, userID uuid.UUID) (models.Snapshot, bool, error) {
+	snapshot, err := s.snapshotter.Latest(ctx, userID)
+	if errors.Is(err, repository.ErrSnapshotNotFound) {
+		return models.Snapshot{}, false, nil
+	}
+	if err != nil {
+		return models.Snapshot{}, false, fmt.Errorf("load snapshot: %w", err)
+	}
+
+	return snapshot, true, nil
+}
+
+// cursorPosition finds the client's cursor in the user's log, or in the
+// snapshot the client was last sent. It reports false when the cursor is
+// gone from the log or older than the retention horizon, so the events
+// after it may be gone too.
+func (s *ChatService) cursorPosition(
+	ctx context.Context,
+	req models.SubscribeRequest,
+	snapshot models.Snapshot,
+) (models.EventPosition, bool, error) {
+	if req.LastEventID == snapshot.EventID {
+		return snapshot.Position, true, nil
+	}
+	if IsAfter(s.retention.OldestRetainedEventID(), req.LastEventID) {
+		return models.EventPosition{}, false, nil
+	}
+
+	position, err := s.eventStore.GetEventPosition(ctx, req.Handle.UserID, req.LastEventID)
+	if errors.Is(err, repository.ErrEventNotFound) {
+		return models.EventPosition{}, false, nil
+	}
+	if err != nil {
+		return models.EventPosition{}, false, fmt.Errorf("find cursor: %w", err)
+	}
+
+	return position, true, nil
+}
+
+// tooFarBehind reports whether the events after the position, the client's
+// cursor or a snapshot, are too many to replay.
+func (s *ChatService) tooFarBehind(
+	ctx context.Context,
+	req models.SubscribeRequest,
+	cursor models.EventPosition,
+) (bool, error) {
+	if s.config.CatchUpLimit <= 0 {
+		return false, nil
+	}
+
+	backlog, err := s.eventStore.CountUserEvents(ctx, req.Handle.UserID, cursor, s.config.CatchUpLimit+1)
+	if err != nil {
+		return false, fmt.Errorf("count missed events: %w", err)
+	}
+
+	return backlog > s.config.CatchUpLimit, nil
+}
@@ -0,0 +1,308 @@
+package chatservice
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+// subscribeUntil collects the events of a subscription up to the one with
+// the given ID.
+func subscribeUntil(t *testing.T, service *ChatService, lastEventID, untilID uuid.UUID) []models.Event {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []models.Event
+	err := service.Subscribe(ctx, subscribeReq(lastEventID), func(e models.Event) error {
+		got = append(got, e)
+		if e.ID == untilID {
+			cancel()
+		}
+		return nil
+	})
+	requireStreamEnded(t, err, context.Canceled, untilID)
+
+	return got
+}
+
+func requireResync(t *testing.T, e models.Event) {
+	t.Helper()
+
+	require.Equal(t, models.EventTypeSystem, e.Type)
+	payload, ok := e.Payload.(models.SystemNotificationPayload)
+	require.True(t, ok)
+	assert.True(t, payload.ResyncRequired)
+}
+
+func TestChatService_SubscribeBacklogOverLimit(t *testing.T) {
+	history := newEvents(12, models.EventTypeMessageNew)
+	store := &fakeEventStore{events: history}
+	publisher := newFakePublisher(history[5])
+	service := newStreamingServiceWith(store, publisher, Config{CatchUpLimit: 10})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		got   []models.Event
+		fresh models.Event
+	)
+	err := service.Subscribe(ctx, subscribeReq(history[0].ID), func(e models.Event) error {
+		got = append(got, e)
+		if len(got) == 1 {
+			fresh = newEvents(1, models.EventTypeMessageNew)[0]
+			store.add(fresh)
+			publisher.live <- fresh
+		} else {
+			cancel()
+		}
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, fresh.ID)
+	require.Len(t, got, 2, "neither the backlog nor the live events it covers are sent")
+	requireResync(t, got[0])
+	assert.Equal(t, fresh, got[1])
+}
+
+func TestChatService_SubscribeBacklogWithinLimit(t *testing.T) {
+	history := newEvents(11, models.EventTypeMessageNew)
+	service := newStreamingServiceWith(&fakeEventStore{events: history}, newFakePublisher(), Config{CatchUpLimit: 10})
+
+	got := subscribeUntil(t, service, history[0].ID, history[10].ID)
+
+	assert.Equal(t, history[1:], got)
+}
+
+func TestChatService_SubscribeWithoutCursor(t *testing.T) {
+	history := newEvents(20, models.EventTypeMessageNew)
+	fresh := newEvents(1, models.EventTypeMessageNew)[0]
+	store := &fakeEventStore{events: history}
+	publisher := newFakePublisher()
+	service := newStreamingServiceWith(store, publisher, Config{CatchUpLimit: 10})
+
+	// The event goes live once the stream has read the log.
+	go func() {
+		for store.pageCount() < 1 {
+			time.Sleep(time.Millisecond)
+		}
+		store.add(fresh)
+		publisher.live <- fresh
+	}()
+
+	got := subscribeUntil(t, service, uuid.Nil, fresh.ID)
+
+	assert.Equal(t, []models.Event{fresh}, got, "a client without a cursor starts at the head of a long log")
+}
+
+func TestChatService_SubscribeAfterResync(t *testing.T) {
+	cursor := newEvents(1, models.EventTypeMessageNew)[0]
+	history := newEvents(2, models.EventTypeMessageNew)
+	store := &fakeEventStore{events: history}
+	service := newStreamingService(store, newFakePublisher())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []models.Event
+	err := service.Subscribe(ctx, subscribeReq(cursor.ID), func(e models.Event) error {
+		got = append(got, e)
+		cancel()
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, history[1].ID)
+	require.Len(t, got, 1)
+	requireResync(t, got[0])
+	assert.Equal(t, uuid.Nil, got[0].ID, "the notification is not stored and has no ID to resume from")
+
+	fresh := newEvents(1, models.EventTypeMessageNew)[0]
+	store.add(fresh)
+
+	got = subscribeUntil(t, service, history[1].ID, fresh.ID)
+	assert.Equal(t, []models.Event{fresh}, got, "a client resumes after a resync without another one")
+}
+
+func TestChatService_SubscribeAfterTyping(t *testing.T) {
+	history := newEvents(2, models.EventTypeMessageNew)
+	typing := newEvents(1, models.EventTypeTyping)[0]
+	store := &fakeEventStore{events: history}
+	service := newStreamingService(store, newFakePublisher(typing))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	err := service.Subscribe(ctx, subscribeReq(history[0].ID), func(e models.Event) error {
+		if e.ID == typing.ID {
+			cancel()
+		}
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, history[1].ID)
+
+	fresh := newEvents(1, models.EventTypeMessageNew)[0]
+	store.add(fresh)
+
+	got := subscribeUntil(t, service, history[1].ID, fresh.ID)
+	assert.Equal(t, []models.Event{fresh}, got, "a client resumes from the last stored event, not the typing one")
+}
+
+func TestChatService_SubscribeCursorPastRetention(t *testing.T) {
+	history := newEvents(2, models.EventTypeMessageNew)
+	service := newStreamingService(&fakeEventStore{events: history}, newFakePublisher())
+	service.retention = fixedHorizon(history[1].ID)
+
+	var got []models.Event
+	err := service.Subscribe(context.Background(), subscribeReq(history[0].ID), func(e models.Event) error {
+		got = append(got, e)
+		return assert.AnError
+	})
+
+	requireStreamEnded(t, err, assert.AnError, history[0].ID)
+	require.Len(t, got, 1)
+	requireResync(t, got[0])
+}
+
+func TestChatService_SubscribeWithoutRetention(t *testing.T) {
+	history := newEvents(3, models.EventTypeMessageNew)
+	service := New(
+		Deps{EventStore: &fakeEventStore{events: history}, Snapshotter: &fakeSnapshots{}, Publisher: newFakePublisher()},
+		Config{},
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+
+	got := subscribeUntil(t, service, history[0].ID, history[2].ID)
+
+	assert.Equal(t, history[1:], got, "a log without retention keeps every event")
+}
+
+// newSnapshot returns a snapshot taken at the event.
+func newSnapshot(at models.Event) *models.Snapshot {
+	chatID := uuid.New()
+	return &models.Snapshot{
+		UserID:   testUserID,
+		EventID:  uuid.Must(uuid.NewV7()),
+		Position: at.Position,
+		State: models.SnapshotPayload{Chats: []models.ChatSnapshot{{
+			Chat:        models.Chat{ID: chatID, Name: "team", Type: models.ChatTypeGroup},
+			UnreadCount: 2,
+		}}},
+	}
+}
+
+func TestChatService_SubscribeSnapshotAfterCompaction(t *testing.T) {
+	cursor := newEvents(1, models.EventTypeMessageNew)[0]
+	history := newEvents(4, models.EventTypeMessageNew)
+	snapshot := newSnapshot(history[1])
+	service := newStreamingService(&fakeEventStore{events: history}, newFakePublisher())
+	service.snapshotter = &fakeSnapshots{snapshot: snapshot}
+
+	got := subscribeUntil(t, service, cursor.ID, history[3].ID)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, snapshot.Event(), got[0])
+	assert.Equal(t, history[2:], got[1:], "only the events after the snapshot are replayed")
+}
+
+func TestChatService_SubscribeSnapshotWhenFarBehind(t *testing.T) {
+	history := newEvents(11, models.EventTypeMessageNew)
+	snapshot := newSnapshot(history[8])
+	service := newStreamingServiceWith(&fakeEventStore{events: history}, newFakePublisher(), Config{CatchUpLimit: 5})
+	service.snapshotter = &fakeSnapshots{snapshot: snapshot}
+
+	got := subscribeUntil(t, service, history[0].ID, history[10].ID)
+
+	require.Len(t, got, 3)
+	assert.Equal(t, models.EventTypeSnapshot, got[0].Type)
+	assert.Equal(t, history[9:], got[1:])
+}
+
+func TestChatService_SubscribeStaleSnapshot(t *testing.T) {
+	history := newEvents(11, models.EventTypeMessageNew)
+	service := newStreamingServiceWith(&fakeEventStore{events: history}, newFakePublisher(), Config{CatchUpLimit: 5})
+	service.snapshotter = &fakeSnapshots{snapshot: newSnapshot(history[1])}
+
+	var got []models.Event
+	err := service.Subscribe(context.Background(), subscribeReq(history[0].ID), func(e models.Event) error {
+		got = append(got, e)
+		return assert.AnError
+	})
+
+	requireStreamEnded(t, err, assert.AnError, history[0].ID)
+	require.Len(t, got, 1, "a snapshot with too many events after it is not sent")
+	requireResync(t, got[0])
+}
+
+func TestChatService_SubscribeFromSnapshotEvent(t *testing.T) {
+	history := newEvents(4, models.EventTypeMessageNew)
+	snapshot := newSnapshot(history[2])
+	service := newStreamingService(&fakeEventStore{events: history}, newFakePublisher())
+	service.snapshotter = &fakeSnapshots{snapshot: snapshot}
+
+	got := subscribeUntil(t, service, snapshot.EventID, history[3].ID)
+
+	assert.Equal(t, history[3:], got, "a client that has the snapshot resumes after it")
+}
+
+func TestChatService_SubscribeSkipsOlderSnapshot(t *testing.T) {
+	history := newEvents(4, models.EventTypeMessageNew)
+	service := newStreamingService(&fakeEventStore{events: history}, newFakePublisher())
+	service.snapshotter = &fakeSnapshots{snapshot: newSnapshot(history[1])}
+
+	got := subscribeUntil(t, service, history[2].ID, history[3].ID)
+
+	assert.Equal(t, history[3:], got)
+}
+
+func TestChatService_SubscribeSkipsSnapshotWhenLogIsWhole(t *testing.T) {
+	history := newEvents(4, models.EventTypeMessageNew)
+	service := newStreamingService(&fakeEventStore{events: history}, newFakePublisher())
+	service.snapshotter = &fakeSnapshots{snapshot: newSnapshot(history[2])}
+
+	got := subscribeUntil(t, service, history[0].ID, history[3].ID)
+
+	assert.Equal(t, history[1:], got)
+}
+
+func TestChatService_SubscribeUnknownCursor(t *testing.T) {
+	cursor := newEvents(1, models.EventTypeMessageNew)[0]
+	history := newEvents(2, models.EventTypeMessageNew)
+	store := &fakeEventStore{events: history}
+	publisher := newFakePublisher()
+	service := newStreamingService(store, publisher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		got   []models.Event
+		fresh models.Event
+	)
+	err := service.Subscribe(ctx, subscribeReq(cursor.ID), func(e models.Event) error {
+		got = append(got, e)
+		if len(got) == 1 {
+			fresh = newEvents(1, models.EventTypeMessageNew)[0]
+			store.add(fresh)
+			publisher.live <- fresh
+		} else {
+			cancel()
+		}
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, fresh.ID)
+	require.Len(t, got, 2, "the events after a cursor the log no longer has are not replayed")
+	requireResync(t, got[0])
+	assert.Equal(t, fresh, got[1])
+}
@@ -2,60 +2,325 @@ package chatservice
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
 	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
 )
 
 const (
-	lastMessages         = 20
-	historicalBufferSize = 20
-	liveBufferSize       = 100
-	outputBufferSize     = 500
-	errorBuffer          = 2
+	defaultPageSize = 20
+	maxPageSize     = 100
+	historyPageSize = 100
+	liveBufferSize  = 100
 )
 
+//go:generate mockgen -source=chat_service.go -destination=mocks/mock_repository.go -package=mocks
+
 type (
+	txManager interface {
+		Do(ctx context.Context, fn func(ctx context.Context) error) error
+	}
+
 	eventStore interface {
-		GetUserEvents(ctx context.Context, userID uuid.UUID, lastEventID uuid.UUID, limit int32) ([]models.Event, error)
+		GetUserEvents(ctx context.Context, userID uuid.UUID, after models.EventPosition, limit int32) ([]models.Event, error)
+		CountUserEvents(ctx context.Context, userID uuid.UUID, after models.EventPosition, limit int32) (int32, error)
+		GetEventPosition(ctx context.Context, userID, eventID uuid.UUID) (models.EventPosition, error)
+		UserHead(ctx context.Context, userID uuid.UUID) (models.EventPosition, uuid.UUID, error)
+		AppendToChat(ctx context.Context, chatID uuid.UUID, eventType models.EventType, payload any) ([]models.Event, error)
+	}
+
+	messageRepository interface {
+		Create(ctx context.Context, message models.Message) error
+		ListBefore(ctx context.Context, chatID uuid.UUID, beforeID uuid.UUID, limit int32) ([]models.Message, error)
+		ListThreadBefore(ctx context.Context, rootID uuid.UUID, beforeID uuid.UUID, limit int32) ([]models.Message, error)
+		AddThreadReply(ctx context.Context, rootID uuid.UUID, repliedAt time.Time) (int32, error)
+		Get(ctx context.Context, id uuid.UUID) (models.Message, error)
+		GetForUpdate(ctx context.Context, id uuid.UUID) (models.Message, error)
+		UpdateContent(ctx context.Context, id uuid.UUID, content models.MessageContent, updatedAt time.Time) error
+		Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error
+	}
+
+	reactionRepository interface {
+		Add(ctx context.Context, reaction models.Reaction) (bool, error)
+		Remove(ctx context.Context, messageID, userID uuid.UUID, emoji string) (bool, error)
+		Count(ctx context.Context, messageID uuid.UUID, emoji string) (int32, error)
+		CountEmoji(ctx context.Context, messageID uuid.UUID) (int32, error)
+		Summaries(
+			ctx context.Context,
+			viewerID uuid.UUID,
+			messageIDs []uuid.UUID,
+		) (map[uuid.UUID][]models.ReactionSummary, error)
+	}
+
+	attachmentRepository interface {
+		Create(ctx context.Context, attachment models.Attachment, quota int64) (bool, error)
+		Usage(ctx context.Context, userID uuid.UUID) (int64, error)
+		Get(ctx context.Context, id uuid.UUID) (models.Attachment, error)
+		GetForShare(ctx context.Context, id uuid.UUID) (models.Attachment, error)
+		IsReferenced(ctx context.Context, id uuid.UUID) (bool, error)
+	}
+
+	blobStore interface {
+		Put(ctx context.Context, key string, r io.Reader) (int64, error)
+		Open(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error)
+		Delete(ctx context.Context, key string) error
+	}
+
+	chatRepository interface {
+		Create(ctx context.Context, chat models.Chat) (bool, error)
+		Get(ctx context.Context, id uuid.UUID) (models.Chat, error)
+		GetDirect(ctx context.Context, a, b uuid.UUID) (models.Chat, error)
+		GetForUpdate(ctx context.Context, id uuid.UUID) (models.Chat, error)
+	}
+
+	userDirectory interface {
+		MissingUsers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+	}
+
+	memberRepository interface {
+		Get(ctx context.Context, chatID, userID uuid.UUID) (models.ChatMember, error)
+		Add(ctx context.Context, members ...models.ChatMember) error
+		Remove(ctx context.Context, chatID, userID uuid.UUID) error
+		UpdateRole(ctx context.Context, chatID, userID uuid.UUID, role models.MemberRole) error
+		ListUserIDs(ctx context.Context, chatID uuid.UUID) ([]uuid.UUID, error)
+		AdvanceReadCursor(
+			ctx context.Context,
+			chatID, userID, messageID uuid.UUID,
+			readAt time.Time,
+		) (models.ReadState, bool, error)
+		ListReadStates(ctx context.Context, chatID uuid.UUID) ([]models.ReadState, error)
+	}
+
+	idempotencyRepository interface {
+		Claim(
+			ctx context.Context,
+			senderID uuid.UUID,
+			chatID uuid.UUID,
+			key string,
+			result models.IdempotencyResult,
+			notBefore time.Time,
+		) (models.IdempotencyResult, bool, error)
+	}
+
+	cursorCodec interface {
+		Encode(cur cursor.Cursor) string
+		Decode(encoded string, scope uuid.UUID) (cursor.Cursor, error)
 	}
 
-	snapshotter interface{}
+	snapshotter interface {
+		Latest(ctx context.Context, userID uuid.UUID) (models.Snapshot, error)
+	}
+
+	retentionHorizon interface {
+		OldestRetainedEventID() uuid.UUID
+	}
 
 	eventPublisher interface {
-		Subscribe(ctx context.Context, userID uuid.UUID) (<-chan models.Event, error)
-		Unsubscribe(userID uuid.UUID) error
+		Subscribe(ctx context.Context, handle models.SubscriptionHandle) (<-chan models.Event, error)
+		Unsubscribe(handle models.SubscriptionHandle) error
+		Publish(ctx context.Context, events ...models.Event) error
 	}
 
-	readModelRepos interface{}
+	readModelRepos interface {
+		Apply(ctx context.Context, events ...models.Event) error
+		RemoveChat(ctx context.Context, userID, chatID uuid.UUID) error
+		ListChats(
+			ctx context.Context,
+			userID uuid.UUID,
+			activeAfter time.Time,
+			beforeActivity time.Time,
+			beforeChatID uuid.UUID,
+			limit int32,
+		) ([]models.ChatPreview, error)
+	}
 )
 
+// Deps are the stores and brokers ChatService works with.
+type Deps struct {
+	TxManager   txManager
+	EventStore  eventStore
+	Chats       chatRepository
+	Messages    messageRepository
+	Reactions   reactionRepository
+	Attachments attachmentRepository
+	Blobs       blobStore
+	Members     memberRepository
+	Idempotency idempotencyRepository
+	Users       userDirectory
+	Cursors     cursorCodec
+	Snapshotter snapshotter
+	Retention   retentionHorizon
+	Publisher   eventPublisher
+	ReadModel   readModelRepos
+}
+
+type Config struct {
+	// IdempotencyTTL is how long a SendMessage idempotency key is remembered.
+	IdempotencyTTL time.Duration
+	// EditWindow is how long after sending a message its sender can edit it.
+	EditWindow time.Duration
+	// MaxAttachmentSize is the largest attachment a user can upload; zero
+	// does not limit it.
+	MaxAttachmentSize int64
+	// AttachmentQuota is how many bytes of attachments a user can upload in
+	// total; zero does not limit it.
+	AttachmentQuota int64
+	// TypingThrottle is the shortest interval between two typing starts of
+	// a user in a chat that are sent to the other members.
+	TypingThrottle time.Duration
+	// TypingExpiry is how long a user is shown as typing without a new
+	// SetTyping call.
+	TypingExpiry time.Duration
+	// CatchUpLimit is the most stored events Connect replays; a client
+	// further behind is told to resync. Zero replays any backlog.
+	CatchUpLimit int32
+	// LiveBufferSize is the number of live events kept for a stream that
+	// replays history or sends slowly. Zero uses a default.
+	LiveBufferSize int
+	// Backpressure decides what happens to the events of a stream whose
+	// live buffer is full. Nil uses DefaultBackpressurePolicy.
+	Backpressure BackpressurePolicy
+}
+
 type ChatService struct {
+	tx          txManager
 	eventStore  eventStore
+	chats       chatRepository
+	messages    messageRepository
+	reactions   reactionRepository
+	attachments attachmentRepository
+	blobs       blobStore
+	members     memberRepository
+	idempotency idempotencyRepository
+	users       userDirectory
+	cursors     cursorCodec
 	snapshotter snapshotter
+	retention   retentionHorizon
 	publisher   eventPublisher
 	readModel   readModelRepos
+	typing      *typingTracker
+	config      Config
+	logger      *slog.Logger
+	now         func() time.Time
+}
+
+func New(deps Deps, config Config, logger *slog.Logger) *ChatService {
+	if config.LiveBufferSize <= 0 {
+		config.LiveBufferSize = liveBufferSize
+	}
+	if config.Backpressure == nil {
+		config.Backpressure = DefaultBackpressurePolicy()
+	}
+	if deps.Retention == nil {
+		deps.Retention = keepAllEvents{}
+	}
+
+	s := &ChatService{
+		tx:          deps.TxManager,
+		eventStore:  deps.EventStore,
+		chats:       deps.Chats,
+		messages:    deps.Messages,
+		reactions:   deps.Reactions,
+		attachments: deps.Attachments,
+		blobs:       deps.Blobs,
+		members:     deps.Members,
+		idempotency: deps.Idempotency,
+		users:       deps.Users,
+		cursors:     deps.Cursors,
+		snapshotter: deps.Snapshotter,
+		retention:   deps.Retention,
+		publisher:   deps.Publisher,
+		readModel:   deps.ReadModel,
+		config:      config,
+		logger:      logger.With("layer", "chat service"),
+		now:         time.Now,
+	}
+	s.typing = newTypingTracker(config.TypingThrottle, config.TypingExpiry, func(key typingKey, recipients []uuid.UUID) {
+		s.publishTyping(context.Background(), key, false, recipients)
+	})
+
+	return s
+}
+
+// keepAllEvents is the retention horizon of a log that never deletes
+// events.
+type keepAllEvents struct{}
+
+func (keepAllEvents) OldestRetainedEventID() uuid.UUID {
+	return uuid.Nil
+}
+
+// publish hands committed events to the broker. The events are already in
+// the event log, so a failed publish only delays delivery until the
+// subscribers resume from it.
+func (s *ChatService) publish(ctx context.Context, events []models.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	if err := s.publisher.Publish(ctx, events...); err != nil {
+		s.logger.Error("publish events", slog.String("error", err.Error()))
+	}
+}
+
+// appendToChat appends an event for every member of the chat and projects
+// it onto their chat previews. Call it inside a transaction.
+func (s *ChatService) appendToChat(
+	ctx context.Context,
+	chatID uuid.UUID,
+	eventType models.EventType,
+	payload any,
+) ([]models.Event, error) {
+	events, err := s.eventStore.AppendToChat(ctx, chatID, eventType, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.readModel.Apply(ctx, events...); err != nil {
+		return nil, err
+	}
+
+	return events, nil
 }
 
+// Subscribe streams the user's events to send: first the stored events
+// after req.LastEventID, led by the user's snapshot or a resync notification
+// when they cannot all be replayed, then the live ones. It blocks until ctx
+// is done, the subscription fails or send returns an error, and reports why
+// with a *StreamEndedError.
 func (s *ChatService) Subscribe(
 	ctx context.Context,
 	req models.SubscribeRequest,
-) (<-chan models.Event, error) {
-	ctx, cancel := context.WithCancel(ctx)
-	channels := &subscriptionChannels{
-		historical: make(chan models.Event, historicalBufferSize),
-		out:        make(chan models.Event, outputBufferSize),
-		err:        make(chan error, errorBuffer),
+	send func(models.Event) error,
+) error {
+	ctx, coop := NewStreamCooperator(ctx, s.config.LiveBufferSize, s.config.Backpressure)
+	defer coop.Close(nil)
+
+	live, err := s.publisher.Subscribe(ctx, req.Handle)
+	if err != nil {
+		return fmt.Errorf("subscribe to live events: %w", err)
+	}
+
+	coop.StartBackgroundProducer(ctx, live, func() {
+		if err := s.publisher.Unsubscribe(req.Handle); err != nil {
+			s.logger.Error("unsubscribe", slog.String("error", err.Error()))
+		}
+	})
+
+	after, lastEventID, err := s.catchUpFrom(ctx, req, send)
+	if err != nil {
+		return &StreamEndedError{Cause: err, LastEventID: req.LastEventID}
 	}
 
-	subCtx := &subscribeContext{
-		ctx:      ctx,
-		req:      req,
-		channels: channels,
-		service:  s,
-		cancel:   cancel,
+	fetchPage := func(ctx context.Context, after models.EventPosition, limit int32) ([]models.Event, error) {
+		return s.eventStore.GetUserEvents(ctx, req.Handle.UserID, after, limit)
 	}
 
-	return subCtx.execute()
+	return coop.ServeStream(ctx, after, lastEventID, fetchPage, historyPageSize, send)
 }
@@ -0,0 +1,89 @@
+package chatservice
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/services/chat_service/mocks"
+)
+
+var (
+	testNow     = time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+	testCursors = cursor.NewCodec([]byte("test secret"))
+)
+
+type chatServiceMocks struct {
+	chats       *mocks.MockchatRepository
+	users       *mocks.MockuserDirectory
+	events      *mocks.MockeventStore
+	messages    *mocks.MockmessageRepository
+	reactions   *mocks.MockreactionRepository
+	attachments *mocks.MockattachmentRepository
+	blobs       *mocks.MockblobStore
+	members     *mocks.MockmemberRepository
+	idempotency *mocks.MockidempotencyRepository
+	publisher   *mocks.MockeventPublisher
+	readModel   *mocks.MockreadModelRepos
+}
+
+func newTestChatService(t *testing.T) (*ChatService, chatServiceMocks) {
+	ctrl := gomock.NewController(t)
+
+	m := chatServiceMocks{
+		chats:       mocks.NewMockchatRepository(ctrl),
+		users:       mocks.NewMockuserDirectory(ctrl),
+		events:      mocks.NewMockeventStore(ctrl),
+		messages:    mocks.NewMockmessageRepository(ctrl),
+		reactions:   mocks.NewMockreactionRepository(ctrl),
+		attachments: mocks.NewMockattachmentRepository(ctrl),
+		blobs:       mocks.NewMockblobStore(ctrl),
+		members:     mocks.NewMockmemberRepository(ctrl),
+		idempotency: mocks.NewMockidempotencyRepository(ctrl),
+		publisher:   mocks.NewMockeventPublisher(ctrl),
+		readModel:   mocks.NewMockreadModelRepos(ctrl),
+	}
+
+	tx := mocks.NewMocktxManager(ctrl)
+	tx.EXPECT().
+		Do(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(ctx)
+		}).
+		AnyTimes()
+
+	service := New(
+		Deps{
+			TxManager:   tx,
+			EventStore:  m.events,
+			Chats:       m.chats,
+			Users:       m.users,
+			Messages:    m.messages,
+			Reactions:   m.reactions,
+			Attachments: m.attachments,
+			Blobs:       m.blobs,
+			Members:     m.members,
+			Idempotency: m.idempotency,
+			Cursors:     testCursors,
+			Publisher:   m.publisher,
+			ReadModel:   m.readModel,
+		},
+		Config{
+			IdempotencyTTL:    time.Hour,
+			EditWindow:        time.Hour,
+			MaxAttachmentSize: testMaxAttachmentSize,
+			AttachmentQuota:   testAttachmentQuota,
+			TypingThrottle:    3 * time.Second,
+			TypingExpiry:      time.Minute,
+		},
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+	service.now = func() time.Time { return testNow }
+
+	return service, m
+}
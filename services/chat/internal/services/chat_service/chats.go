@@ -0,0 +1,229 @@
+package chatservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+const maxGroupMembers = 500
+
+// CreateChat creates a chat owned by the caller and notifies every member.
+// A direct chat is unique per pair of users: asking for it again returns
+// the existing one.
+func (s *ChatService) CreateChat(
+	ctx context.Context,
+	req models.CreateChatRequest,
+) (models.CreateChatResponse, error) {
+	memberIDs, err := chatMemberIDs(req)
+	if err != nil {
+		return models.CreateChatResponse{}, err
+	}
+
+	if req.Type == models.ChatTypeDirect {
+		existing, err := s.chats.GetDirect(ctx, memberIDs[0], memberIDs[1])
+		if err == nil {
+			return models.CreateChatResponse{Chat: existing}, nil
+		}
+		if !errors.Is(err, repository.ErrChatNotFound) {
+			return models.CreateChatResponse{}, err
+		}
+	}
+
+	missing, err := s.users.MissingUsers(ctx, memberIDs[1:])
+	if err != nil {
+		return models.CreateChatResponse{}, err
+	}
+	if len(missing) > 0 {
+		return models.CreateChatResponse{}, fmt.Errorf("%w: %s", ErrUserNotFound, missing[0])
+	}
+
+	chat, err := s.newChat(req, memberIDs)
+	if err != nil {
+		return models.CreateChatResponse{}, err
+	}
+
+	var events []models.Event
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		created, err := s.chats.Create(ctx, chat)
+		if err != nil {
+			return err
+		}
+		if !created {
+			chat, err = s.chats.GetDirect(ctx, memberIDs[0], memberIDs[1])
+			return err
+		}
+
+		events, err = s.appendToChat(ctx, chat.ID, models.EventTypeSystem, models.SystemNotificationPayload{
+			Text:  addedToChatText(chat),
+			Level: models.SystemNotificationLevelInfo,
+		})
+		return err
+	})
+	if err != nil {
+		return models.CreateChatResponse{}, err
+	}
+
+	s.publish(ctx, events)
+
+	return models.CreateChatResponse{Chat: chat}, nil
+}
+
+// ListChats pages through the caller's chats, most recently active first.
+// The next cursor is empty on the last page.
+//
+// Activity changes while a client pages, so the pages go through windows
+// of activity time. The first window ends when the listing starts, and its
+// order does not change as chats become active: a chat active since moves
+// out of the window. Once a window is listed, the next one holds the chats
+// active after it, if there are any. Every chat is listed, and a chat that
+// became active while the client paged is listed again with its new
+// activity.
+func (s *ChatService) ListChats(
+	ctx context.Context,
+	req models.ListChatsRequest,
+) (models.ListChatsResponse, error) {
+	pageSize, err := clampPageSize(req.PageSize)
+	if err != nil {
+		return models.ListChatsResponse{}, err
+	}
+
+	now := s.now().UTC().Truncate(time.Microsecond)
+	window := chatsWindow(req.UserID, time.Time{}, now)
+	if req.Cursor != "" {
+		window, err = s.cursors.Decode(req.Cursor, req.UserID)
+		if err != nil {
+			return models.ListChatsResponse{}, err
+		}
+		if window.Direction != cursor.Backward || window.Until.IsZero() {
+			return models.ListChatsResponse{}, cursor.ErrInvalidCursor
+		}
+	}
+
+	chats, err := s.readModel.ListChats(ctx, req.UserID, window.Since, window.Time, window.ID, pageSize+1)
+	if err != nil {
+		return models.ListChatsResponse{}, err
+	}
+
+	var resp models.ListChatsResponse
+	if len(chats) > int(pageSize) {
+		chats = chats[:pageSize]
+		last := chats[len(chats)-1]
+		window.ID, window.Time = last.ID, last.UpdatedAt
+		resp.NextCursor = s.cursors.Encode(window)
+		resp.Chats = chats
+
+		return resp, nil
+	}
+	resp.Chats = chats
+
+	// The window is listed; the next one ends with the newest activity, which
+	// a clock ahead of this one may have put after now.
+	newest, err := s.readModel.ListChats(ctx, req.UserID, window.Until, time.Time{}, uuid.Nil, 1)
+	if err != nil {
+		return models.ListChatsResponse{}, err
+	}
+	if len(newest) > 0 {
+		resp.NextCursor = s.cursors.Encode(chatsWindow(req.UserID, window.Until, laterOf(now, newest[0].UpdatedAt)))
+	}
+
+	return resp, nil
+}
+
+// chatsWindow returns a cursor at the start of the window of chats active
+// after since and up to until.
+func chatsWindow(userID uuid.UUID, since, until time.Time) cursor.Cursor {
+	return cursor.Cursor{
+		Direction: cursor.Backward,
+		Scope:     userID,
+		ID:        uuid.Max,
+		Time:      until,
+		Since:     since,
+		Until:     until,
+	}
+}
+
+func laterOf(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
+
+func (s *ChatService) newChat(req models.CreateChatRequest, memberIDs []uuid.UUID) (models.Chat, error) {
+	chatID, err := uuid.NewV7()
+	if err != nil {
+		return models.Chat{}, fmt.Errorf("generate chat id: %w", err)
+	}
+
+	now := s.now().UTC().Truncate(time.Microsecond)
+	chat := models.Chat{
+		ID:        chatID,
+		Name:      req.Name,
+		Type:      req.Type,
+		Members:   make([]models.ChatMember, 0, len(memberIDs)),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	for _, userID := range memberIDs {
+		role := models.MemberRoleMember
+		if userID == req.OwnerID {
+			role = models.MemberRoleOwner
+		}
+		chat.Members = append(chat.Members, models.ChatMember{
+			ChatID:   chatID,
+			UserID:   userID,
+			Role:     role,
+			JoinedAt: now,
+		})
+	}
+
+	return chat, nil
+}
+
+// chatMemberIDs validates the request and returns the distinct members with
+// the owner first.
+func chatMemberIDs(req models.CreateChatRequest) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{req.OwnerID}
+	seen := map[uuid.UUID]bool{req.OwnerID: true}
+	for _, id := range req.MemberIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	switch req.Type {
+	case models.ChatTypeDirect:
+		if len(ids) != 2 {
+			return nil, fmt.Errorf("%w: a direct chat has exactly two members", ErrInvalidChat)
+		}
+	case models.ChatTypeGroup:
+		if req.Name == "" {
+			return nil, fmt.Errorf("%w: a group chat needs a name", ErrInvalidChat)
+		}
+		if len(ids) > maxGroupMembers {
+			return nil, fmt.Errorf("%w: a group chat has at most %d members", ErrInvalidChat, maxGroupMembers)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unsupported chat type", ErrInvalidChat)
+	}
+
+	return ids, nil
+}
+
+func addedToChatText(chat models.Chat) string {
+	if chat.Name == "" {
+		return "You were added to a new chat"
+	}
+
+	return fmt.Sprintf("You were added to %q", chat.Name)
+}
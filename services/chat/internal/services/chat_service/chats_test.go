@@ -0,0 +1,241 @@
+package chatservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+func TestChatService_CreateChatGroup(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+
+	m.users.EXPECT().
+		MissingUsers(ctx, []uuid.UUID{alice, bob}).
+		Return(nil, nil)
+
+	var stored models.Chat
+	m.chats.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, chat models.Chat) (bool, error) {
+			stored = chat
+			return true, nil
+		})
+
+	events := []models.Event{{ID: uuid.Must(uuid.NewV7())}, {ID: uuid.Must(uuid.NewV7())}}
+	m.events.EXPECT().
+		AppendToChat(ctx, gomock.Any(), models.EventTypeSystem, gomock.Any()).
+		Return(events, nil)
+	m.readModel.EXPECT().Apply(ctx, events[0], events[1]).Return(nil)
+	m.publisher.EXPECT().
+		Publish(ctx, events[0], events[1]).
+		Return(nil)
+
+	resp, err := service.CreateChat(ctx, models.CreateChatRequest{
+		OwnerID:   testUserID,
+		Name:      "team",
+		Type:      models.ChatTypeGroup,
+		MemberIDs: []uuid.UUID{alice, testUserID, bob, alice},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, stored, resp.Chat)
+	assert.Equal(t, "team", resp.Chat.Name)
+	require.Len(t, resp.Chat.Members, 3)
+
+	roles := map[uuid.UUID]models.MemberRole{}
+	for _, member := range resp.Chat.Members {
+		assert.Equal(t, resp.Chat.ID, member.ChatID)
+		roles[member.UserID] = member.Role
+	}
+	assert.Equal(t, map[uuid.UUID]models.MemberRole{
+		testUserID: models.MemberRoleOwner,
+		alice:      models.MemberRoleMember,
+		bob:        models.MemberRoleMember,
+	}, roles)
+}
+
+func TestChatService_CreateChatExistingDirect(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	other := uuid.New()
+	existing := models.Chat{ID: uuid.New(), Type: models.ChatTypeDirect}
+
+	m.chats.EXPECT().
+		GetDirect(ctx, testUserID, other).
+		Return(existing, nil)
+
+	resp, err := service.CreateChat(ctx, models.CreateChatRequest{
+		OwnerID:   testUserID,
+		Type:      models.ChatTypeDirect,
+		MemberIDs: []uuid.UUID{other},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, existing, resp.Chat)
+}
+
+func TestChatService_CreateChatDirectCreatedConcurrently(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	other := uuid.New()
+	existing := models.Chat{ID: uuid.New(), Type: models.ChatTypeDirect}
+
+	gomock.InOrder(
+		m.chats.EXPECT().GetDirect(ctx, testUserID, other).Return(models.Chat{}, repository.ErrChatNotFound),
+		m.users.EXPECT().MissingUsers(ctx, []uuid.UUID{other}).Return(nil, nil),
+		m.chats.EXPECT().Create(ctx, gomock.Any()).Return(false, nil),
+		m.chats.EXPECT().GetDirect(ctx, testUserID, other).Return(existing, nil),
+	)
+
+	resp, err := service.CreateChat(ctx, models.CreateChatRequest{
+		OwnerID:   testUserID,
+		Type:      models.ChatTypeDirect,
+		MemberIDs: []uuid.UUID{other, testUserID},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, existing, resp.Chat)
+}
+
+func TestChatService_CreateChatInvalid(t *testing.T) {
+	tooMany := make([]uuid.UUID, maxGroupMembers)
+	for i := range tooMany {
+		tooMany[i] = uuid.New()
+	}
+
+	tests := map[string]models.CreateChatRequest{
+		"direct with self":         {Type: models.ChatTypeDirect, MemberIDs: []uuid.UUID{testUserID}},
+		"direct with three":        {Type: models.ChatTypeDirect, MemberIDs: []uuid.UUID{uuid.New(), uuid.New()}},
+		"group without name":       {Type: models.ChatTypeGroup, MemberIDs: []uuid.UUID{uuid.New()}},
+		"group above member limit": {Type: models.ChatTypeGroup, Name: "team", MemberIDs: tooMany},
+		"unspecified type":         {Name: "team"},
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, _ := newTestChatService(t)
+			req.OwnerID = testUserID
+
+			_, err := service.CreateChat(context.Background(), req)
+
+			assert.ErrorIs(t, err, ErrInvalidChat)
+		})
+	}
+}
+
+func TestChatService_CreateChatUnknownMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	unknown := uuid.New()
+
+	m.users.EXPECT().
+		MissingUsers(ctx, []uuid.UUID{unknown}).
+		Return([]uuid.UUID{unknown}, nil)
+
+	_, err := service.CreateChat(ctx, models.CreateChatRequest{
+		OwnerID:   testUserID,
+		Name:      "team",
+		Type:      models.ChatTypeGroup,
+		MemberIDs: []uuid.UUID{unknown},
+	})
+
+	assert.ErrorIs(t, err, ErrUserNotFound)
+}
+
+func TestChatService_ListChats(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	page := []models.ChatPreview{
+		{ID: uuid.New(), UpdatedAt: testNow},
+		{ID: uuid.New(), UpdatedAt: testNow.Add(-time.Minute), UnreadCount: 2},
+		{ID: uuid.New(), UpdatedAt: testNow.Add(-time.Hour)},
+	}
+
+	m.readModel.EXPECT().
+		ListChats(ctx, testUserID, time.Time{}, testNow, uuid.Max, int32(3)).
+		Return(page, nil)
+
+	resp, err := service.ListChats(ctx, models.ListChatsRequest{UserID: testUserID, PageSize: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, page[:2], resp.Chats)
+	require.NotEmpty(t, resp.NextCursor)
+
+	gomock.InOrder(
+		m.readModel.EXPECT().
+			ListChats(ctx, testUserID, time.Time{}, page[1].UpdatedAt, page[1].ID, int32(3)).
+			Return(page[2:], nil),
+		m.readModel.EXPECT().
+			ListChats(ctx, testUserID, testNow, time.Time{}, uuid.Nil, int32(1)).
+			Return(nil, nil),
+	)
+
+	resp, err = service.ListChats(ctx, models.ListChatsRequest{
+		UserID:   testUserID,
+		PageSize: 2,
+		Cursor:   resp.NextCursor,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, page[2:], resp.Chats)
+	assert.Empty(t, resp.NextCursor, "no cursor on the last page")
+}
+
+func TestChatService_ListChatsActiveWhilePaging(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	listed := models.ChatPreview{ID: uuid.New(), UpdatedAt: testNow.Add(-time.Hour)}
+	// The chat was active after the listing started, by a clock ahead of
+	// this one.
+	active := models.ChatPreview{ID: uuid.New(), UpdatedAt: testNow.Add(time.Minute)}
+
+	gomock.InOrder(
+		m.readModel.EXPECT().
+			ListChats(ctx, testUserID, time.Time{}, testNow, uuid.Max, int32(2)).
+			Return([]models.ChatPreview{listed}, nil),
+		m.readModel.EXPECT().
+			ListChats(ctx, testUserID, testNow, time.Time{}, uuid.Nil, int32(1)).
+			Return([]models.ChatPreview{active}, nil),
+	)
+
+	resp, err := service.ListChats(ctx, models.ListChatsRequest{UserID: testUserID, PageSize: 1})
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.ChatPreview{listed}, resp.Chats)
+	require.NotEmpty(t, resp.NextCursor, "the chats active since are on the next page")
+
+	gomock.InOrder(
+		m.readModel.EXPECT().
+			ListChats(ctx, testUserID, testNow, active.UpdatedAt, uuid.Max, int32(2)).
+			Return([]models.ChatPreview{active}, nil),
+		m.readModel.EXPECT().
+			ListChats(ctx, testUserID, active.UpdatedAt, time.Time{}, uuid.Nil, int32(1)).
+			Return(nil, nil),
+	)
+
+	resp, err = service.ListChats(ctx, models.ListChatsRequest{UserID: testUserID, PageSize: 1, Cursor: resp.NextCursor})
+
+	require.NoError(t, err)
+	assert.Equal(t, []models.ChatPreview{active}, resp.Chats)
+	assert.Empty(t, resp.NextCursor)
+}
+
+func TestChatService_ListChatsRejectsForeignCursor(t *testing.T) {
+	service, _ := newTestChatService(t)
+	foreign := testCursors.Encode(cursor.Cursor{Direction: cursor.Backward, Scope: uuid.New(), ID: uuid.New(), Until: testNow})
+
+	_, err := service.ListChats(context.Background(), models.ListChatsRequest{UserID: testUserID, Cursor: foreign})
+
+	assert.ErrorIs(t, err, cursor.ErrInvalidCursor)
+}
@@ -4,40 +4,85 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"sync"
+	"time"
 
-	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
 	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
 )
 
+// Causes a subscription ends with besides the cancellation of its context.
 var (
-	ErrBufferOverflow    = errors.New("live channel buffer overflow: client consumption is too slow")
-	ErrBrokerSubClosed   = errors.New("global broker subscription channel closed")
-	ErrLiveChannelClosed = errors.New("live channel closed unexpectedly")
+	ErrBufferOverflow  = errors.New("live channel buffer overflow: client consumption is too slow")
+	ErrBrokerSubClosed = errors.New("global broker subscription channel closed")
 )
 
+// StreamEndedError is returned when a subscription stops. Cause tells why,
+// and LastEventID is the last stored event the client received, from which
+// it resumes.
+type StreamEndedError struct {
+	Cause       error
+	LastEventID uuid.UUID
+}
+
+func (e *StreamEndedError) Error() string {
+	return fmt.Sprintf("stream ended after event %s: %v", e.LastEventID, e.Cause)
+}
+
+func (e *StreamEndedError) Unwrap() error {
+	return e.Cause
+}
+
+// historyPager returns up to limit stored events that follow the after
+// position, in log order.
+type historyPager func(ctx context.Context, after models.EventPosition, limit int32) ([]models.Event, error)
+
+// A stream reads the log again for a live event the log does not return
+// yet after pendingRetryMinDelay, and waits twice as long after each read
+// that still misses it, up to pendingRetryMaxDelay. The wait ends with the
+// oldest transaction of the cluster, which may run for long.
+const (
+	pendingRetryMinDelay = 50 * time.Millisecond
+	pendingRetryMaxDelay = 2 * time.Second
+)
+
+// StreamCooperator joins the stored history of a subscriber with its live
+// events. The live subscription is opened first and buffered while the
+// history is replayed, so no event falls into the gap between the two.
+// Stored events are only ever sent from the log, in log order; a live one
+// just makes the stream read the log again.
 type StreamCooperator struct {
-	liveChan   chan models.Event
-	cancel     context.CancelCauseFunc
-	wg         sync.WaitGroup
-	isLiveMode bool
+	live   *liveBuffer
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
 }
 
-func NewStreamCooperator(parentCtx context.Context, bufferSize int) (context.Context, *StreamCooperator) {
+func NewStreamCooperator(
+	parentCtx context.Context,
+	bufferSize int,
+	policy BackpressurePolicy,
+) (context.Context, *StreamCooperator) {
 	ctx, cancel := context.WithCancelCause(parentCtx)
 
 	return ctx, &StreamCooperator{
-		liveChan:   make(chan models.Event, bufferSize),
-		cancel:     cancel,
-		isLiveMode: false,
+		live:   newLiveBuffer(bufferSize, policy),
+		cancel: cancel,
 	}
 }
 
+// Close cancels the stream with the cause and waits for the producer to
+// unsubscribe.
 func (c *StreamCooperator) Close(cause error) {
 	c.cancel(cause)
 	c.wg.Wait()
 }
 
+// StartBackgroundProducer moves live events into the cooperator's buffer.
+// It cancels the stream when the backpressure policy gives up on a full
+// buffer or the broker closes the subscription, and unsubscribes once it
+// stops.
 func (c *StreamCooperator) StartBackgroundProducer(ctx context.Context, subChan <-chan models.Event, unsubscribe func()) {
 	c.wg.Go(func() {
 		defer unsubscribe()
@@ -53,9 +98,7 @@ func (c *StreamCooperator) StartBackgroundProducer(ctx context.Context, subChan
 					return
 				}
 
-				select {
-				case c.liveChan <- event:
-				default:
+				if !c.live.push(event) {
 					c.cancel(ErrBufferOverflow)
 					return
 				}
@@ -64,49 +107,114 @@ func (c *StreamCooperator) StartBackgroundProducer(ctx context.Context, subChan
 	})
 }
 
+// ServeStream replays the history after the position page by page until a
+// short page shows it caught up, then sends the buffered and following
+// ephemeral live events, and reads the log again for the stored ones.
+// lastEventID is the last event the client has from before the position.
+// It runs until ctx is done or send fails and always returns a
+// *StreamEndedError.
 func (c *StreamCooperator) ServeStream(
 	ctx context.Context,
-	fetchHistory func(ctx context.Context) ([]models.Event, error),
+	after models.EventPosition,
+	lastEventID uuid.UUID,
+	fetchPage historyPager,
+	pageSize int32,
 	send func(models.Event) error,
 ) error {
-	history, err := fetchHistory(ctx)
-	if err != nil {
-		return err
+	delivered := lastEventID
+	end := func(cause error) error {
+		return &StreamEndedError{Cause: cause, LastEventID: delivered}
 	}
 
-	var cursor uuid.UUID
-	if len(history) > 0 {
-		cursor = history[len(history)-1].ID
+	replay := func() error {
+		for {
+			page, err := fetchPage(ctx, after, pageSize)
+			if err != nil {
+				if ctx.Err() != nil {
+					return context.Cause(ctx)
+				}
+				return fmt.Errorf("replay history: %w", err)
+			}
 
-		for _, ev := range history {
-			if err := send(ev); err != nil {
-				return err
+			for _, ev := range page {
+				if ctx.Err() != nil {
+					return context.Cause(ctx)
+				}
+				if err := send(ev); err != nil {
+					return err
+				}
+				after = ev.Position
+				delivered = ev.ID
+			}
+
+			if len(page) < int(pageSize) {
+				return nil
 			}
 		}
 	}
 
+	if err := replay(); err != nil {
+		return end(err)
+	}
+
+	// A live stored event the log does not return yet may still be past its
+	// visibility horizon. It stays pending, and the log is read again, less
+	// and less often, until it is read past the event.
+	pending := make(map[uuid.UUID]models.EventPosition)
+	retryDelay := pendingRetryMinDelay
+	retry := time.NewTimer(retryDelay)
+	retry.Stop()
+	defer retry.Stop()
+
 	for {
+		// The log is read again only for a new stored event or a retry, not
+		// for every ephemeral one.
+		readLog := false
+
 		select {
 		case <-ctx.Done():
-			return context.Cause(ctx)
+			return end(context.Cause(ctx))
 
-		case ev, ok := <-c.liveChan:
-			if !ok {
-				return ErrLiveChannelClosed
-			}
+		case <-retry.C:
+			readLog = true
 
-			if !c.isLiveMode {
-				if !IsAfter(ev.ID, cursor) {
+		case <-c.live.ready:
+			for _, ev := range c.live.take() {
+				if ctx.Err() != nil {
+					return end(context.Cause(ctx))
+				}
+				if !ev.Type.IsEphemeral() {
+					if ev.Position.After(after) {
+						pending[ev.ID] = ev.Position
+						readLog = true
+					}
 					continue
 				}
 
-				c.isLiveMode = true
+				if err := send(ev); err != nil {
+					return end(err)
+				}
 			}
+		}
 
-			if err := send(ev); err != nil {
-				return err
+		if !readLog || len(pending) == 0 {
+			continue
+		}
+		if err := replay(); err != nil {
+			return end(err)
+		}
+		for id, position := range pending {
+			if !position.After(after) {
+				delete(pending, id)
 			}
 		}
+		if len(pending) == 0 {
+			retry.Stop()
+			retryDelay = pendingRetryMinDelay
+			continue
+		}
+		retry.Reset(retryDelay)
+		retryDelay = min(2*retryDelay, pendingRetryMaxDelay)
 	}
 }
 
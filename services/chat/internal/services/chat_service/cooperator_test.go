@@ -0,0 +1,443 @@
+package chatservice
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+// fakeEventStore serves GetUserEvents from a slice in log order.
+type fakeEventStore struct {
+	eventStore
+
+	mu     sync.Mutex
+	events []models.Event
+	pages  int
+	err    error
+}
+
+// add appends events to the log.
+func (s *fakeEventStore) add(events ...models.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, events...)
+}
+
+// pageCount returns how many pages were read.
+func (s *fakeEventStore) pageCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.pages
+}
+
+func (s *fakeEventStore) GetUserEvents(
+	_ context.Context,
+	_ uuid.UUID,
+	after models.EventPosition,
+	limit int32,
+) ([]models.Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pages++
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	var page []models.Event
+	for _, e := range s.events {
+		if e.Position.After(after) && len(page) < int(limit) {
+			page = append(page, e)
+		}
+	}
+
+	return page, nil
+}
+
+func (s *fakeEventStore) CountUserEvents(
+	ctx context.Context,
+	userID uuid.UUID,
+	after models.EventPosition,
+	limit int32,
+) (int32, error) {
+	page, err := s.GetUserEvents(ctx, userID, after, limit)
+	return int32(len(page)), err
+}
+
+func (s *fakeEventStore) GetEventPosition(_ context.Context, _, eventID uuid.UUID) (models.EventPosition, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return models.EventPosition{}, s.err
+	}
+	for _, e := range s.events {
+		if e.ID == eventID {
+			return e.Position, nil
+		}
+	}
+
+	return models.EventPosition{}, repository.ErrEventNotFound
+}
+
+func (s *fakeEventStore) UserHead(context.Context, uuid.UUID) (models.EventPosition, uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil || len(s.events) == 0 {
+		return models.EventPosition{}, uuid.Nil, s.err
+	}
+
+	last := s.events[len(s.events)-1]
+	return last.Position, last.ID, nil
+}
+
+// fakeSnapshots holds at most one snapshot, of any user.
+type fakeSnapshots struct {
+	snapshot *models.Snapshot
+}
+
+func (s *fakeSnapshots) Latest(context.Context, uuid.UUID) (models.Snapshot, error) {
+	if s.snapshot == nil {
+		return models.Snapshot{}, repository.ErrSnapshotNotFound
+	}
+
+	return *s.snapshot, nil
+}
+
+// fixedHorizon is a retention horizon that does not move.
+type fixedHorizon uuid.UUID
+
+func (h fixedHorizon) OldestRetainedEventID() uuid.UUID {
+	return uuid.UUID(h)
+}
+
+// fakePublisher hands out one live channel that the test fills up front.
+type fakePublisher struct {
+	eventPublisher
+
+	live         chan models.Event
+	subscribeErr error
+	unsubscribed chan struct{}
+	once         sync.Once
+}
+
+func newFakePublisher(buffered ...models.Event) *fakePublisher {
+	p := &fakePublisher{
+		live:         make(chan models.Event, 2*liveBufferSize),
+		unsubscribed: make(chan struct{}),
+	}
+	for _, e := range buffered {
+		p.live <- e
+	}
+
+	return p
+}
+
+func (p *fakePublisher) Subscribe(context.Context, models.SubscriptionHandle) (<-chan models.Event, error) {
+	return p.live, p.subscribeErr
+}
+
+func (p *fakePublisher) Unsubscribe(models.SubscriptionHandle) error {
+	p.once.Do(func() { close(p.unsubscribed) })
+	return nil
+}
+
+func (p *fakePublisher) isUnsubscribed() bool {
+	select {
+	case <-p.unsubscribed:
+		return true
+	default:
+		return false
+	}
+}
+
+func newStreamingService(store *fakeEventStore, publisher *fakePublisher) *ChatService {
+	return newStreamingServiceWith(store, publisher, Config{})
+}
+
+func newStreamingServiceWith(store *fakeEventStore, publisher *fakePublisher, config Config) *ChatService {
+	return New(
+		Deps{EventStore: store, Snapshotter: &fakeSnapshots{}, Retention: fixedHorizon(uuid.Nil), Publisher: publisher},
+		config,
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+	)
+}
+
+// lastSeq hands out the positions of the events the tests make, so that
+// events made later come later in the log.
+var lastSeq atomic.Int64
+
+func newEvents(n int, eventType models.EventType) []models.Event {
+	events := make([]models.Event, n)
+	for i := range events {
+		events[i] = models.Event{
+			ID:       uuid.Must(uuid.NewV7()),
+			UserID:   testUserID,
+			Type:     eventType,
+			Position: models.EventPosition{Seq: lastSeq.Add(1)},
+		}
+	}
+
+	return events
+}
+
+func subscribeReq(lastEventID uuid.UUID) models.SubscribeRequest {
+	return models.SubscribeRequest{
+		Handle:      models.NewSubscriptionHandle(testUserID, uuid.New()),
+		LastEventID: lastEventID,
+	}
+}
+
+func requireStreamEnded(t *testing.T, err error, cause error, lastEventID uuid.UUID) {
+	t.Helper()
+
+	var ended *StreamEndedError
+	require.ErrorAs(t, err, &ended)
+	assert.ErrorIs(t, ended, cause)
+	assert.Equal(t, lastEventID, ended.LastEventID)
+}
+
+func TestChatService_SubscribeReplaysPagesThenLive(t *testing.T) {
+	history := newEvents(2*historyPageSize+40, models.EventTypeMessageNew)
+	typing := newEvents(1, models.EventTypeTyping)[0]
+	fresh := newEvents(1, models.EventTypeMessageNew)[0]
+
+	store := &fakeEventStore{events: history}
+	publisher := newFakePublisher(history[150], history[len(history)-1], typing, fresh)
+	service := newStreamingService(store, publisher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []models.Event
+	err := service.Subscribe(ctx, subscribeReq(history[9].ID), func(e models.Event) error {
+		got = append(got, e)
+		switch e.ID {
+		case typing.ID:
+			store.add(fresh)
+		case fresh.ID:
+			cancel()
+		}
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, fresh.ID)
+	assert.Equal(t, 4, store.pages, "the replay takes three pages and the live events one more")
+
+	want := append(append([]models.Event{}, history[10:]...), typing, fresh)
+	assert.Equal(t, want, got, "the live events the replay covered are dropped")
+	assert.True(t, publisher.isUnsubscribed())
+}
+
+func TestChatService_SubscribeLiveEventCommittedLate(t *testing.T) {
+	// The late event got its ID before the history but committed after it.
+	late := newEvents(1, models.EventTypeMessageNew)[0]
+	history := newEvents(4, models.EventTypeMessageNew)
+	late.Position = newEvents(1, models.EventTypeMessageNew)[0].Position
+
+	store := &fakeEventStore{events: history}
+	publisher := newFakePublisher()
+	service := newStreamingService(store, publisher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []models.Event
+	err := service.Subscribe(ctx, subscribeReq(history[0].ID), func(e models.Event) error {
+		got = append(got, e)
+		switch e.ID {
+		case history[3].ID:
+			store.add(late)
+			publisher.live <- late
+		case late.ID:
+			cancel()
+		}
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, late.ID)
+	assert.Equal(t, append(history[1:], late), got, "a live event is not dropped for its ID")
+}
+
+func TestChatService_SubscribeLiveEventAheadOfLog(t *testing.T) {
+	history := newEvents(2, models.EventTypeMessageNew)
+	fresh := newEvents(1, models.EventTypeMessageNew)[0]
+
+	store := &fakeEventStore{events: history}
+	publisher := newFakePublisher(fresh)
+	service := newStreamingService(store, publisher)
+
+	// The log returns the event only a few reads after it went live.
+	go func() {
+		for store.pageCount() < 3 {
+			time.Sleep(time.Millisecond)
+		}
+		store.add(fresh)
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var got []models.Event
+	err := service.Subscribe(ctx, subscribeReq(history[0].ID), func(e models.Event) error {
+		got = append(got, e)
+		if e.ID == fresh.ID {
+			cancel()
+		}
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, fresh.ID)
+	assert.Equal(t, append(history[1:], fresh), got)
+}
+
+func TestChatService_SubscribePendingEventIgnoresTyping(t *testing.T) {
+	history := newEvents(2, models.EventTypeMessageNew)
+	fresh := newEvents(1, models.EventTypeMessageNew)[0]
+	typing := newEvents(100, models.EventTypeTyping)
+
+	store := &fakeEventStore{events: history}
+	publisher := newFakePublisher(fresh, typing[0])
+	service := newStreamingService(store, publisher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var sent int
+	err := service.Subscribe(ctx, subscribeReq(history[0].ID), func(e models.Event) error {
+		switch {
+		case e.ID == fresh.ID:
+			cancel()
+		case e.Type == models.EventTypeTyping:
+			// Each typing event goes live on its own while fresh is pending.
+			sent++
+			if sent < len(typing) {
+				publisher.live <- typing[sent]
+			} else {
+				store.add(fresh)
+			}
+		}
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, fresh.ID)
+	assert.Less(t, store.pageCount(), 20, "typing events do not make the stream read the log")
+}
+
+func TestChatService_SubscribeOverflow(t *testing.T) {
+	history := newEvents(2, models.EventTypeMessageNew)
+	publisher := newFakePublisher(newEvents(liveBufferSize+1, models.EventTypeMessageNew)...)
+	service := newStreamingService(&fakeEventStore{events: history}, publisher)
+
+	err := service.Subscribe(context.Background(), subscribeReq(history[0].ID), func(models.Event) error {
+		<-publisher.unsubscribed
+		return nil
+	})
+
+	requireStreamEnded(t, err, ErrBufferOverflow, history[1].ID)
+}
+
+func TestChatService_SubscribeSurvivesTypingFlood(t *testing.T) {
+	history := newEvents(2, models.EventTypeMessageNew)
+	flood := newEvents(2*liveBufferSize-1, models.EventTypeTyping)
+	fresh := newEvents(1, models.EventTypeMessageNew)[0]
+	store := &fakeEventStore{events: history}
+	publisher := newFakePublisher(append(flood, fresh)...)
+	service := newStreamingService(store, publisher)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var typing int
+	err := service.Subscribe(ctx, subscribeReq(history[0].ID), func(e models.Event) error {
+		switch {
+		case e.ID == history[1].ID:
+			// Hold the replay until the producer has gone through the flood.
+			for len(publisher.live) > 0 {
+				runtime.Gosched()
+			}
+			store.add(fresh)
+		case e.ID == fresh.ID:
+			cancel()
+		case e.Type == models.EventTypeTyping:
+			typing++
+		}
+		return nil
+	})
+
+	requireStreamEnded(t, err, context.Canceled, fresh.ID)
+	assert.Less(t, typing, len(flood), "typing events are dropped from the full buffer")
+}
+
+func TestChatService_SubscribeBrokerClosed(t *testing.T) {
+	publisher := newFakePublisher()
+	close(publisher.live)
+	service := newStreamingService(&fakeEventStore{}, publisher)
+
+	err := service.Subscribe(context.Background(), subscribeReq(uuid.Nil), func(models.Event) error {
+		return nil
+	})
+
+	requireStreamEnded(t, err, ErrBrokerSubClosed, uuid.Nil)
+}
+
+func TestChatService_SubscribeFailures(t *testing.T) {
+	lastEventID := uuid.Must(uuid.NewV7())
+	failure := errors.New("connection refused")
+
+	t.Run("store", func(t *testing.T) {
+		publisher := newFakePublisher()
+		service := newStreamingService(&fakeEventStore{err: failure}, publisher)
+
+		err := service.Subscribe(context.Background(), subscribeReq(lastEventID), func(models.Event) error {
+			return nil
+		})
+
+		requireStreamEnded(t, err, failure, lastEventID)
+		assert.True(t, publisher.isUnsubscribed())
+	})
+
+	t.Run("send", func(t *testing.T) {
+		history := newEvents(3, models.EventTypeMessageNew)
+		service := newStreamingService(&fakeEventStore{events: history}, newFakePublisher())
+
+		err := service.Subscribe(context.Background(), subscribeReq(history[0].ID), func(e models.Event) error {
+			if e.ID == history[2].ID {
+				return failure
+			}
+			return nil
+		})
+
+		requireStreamEnded(t, err, failure, history[1].ID)
+	})
+
+	t.Run("publisher", func(t *testing.T) {
+		publisher := newFakePublisher()
+		publisher.subscribeErr = failure
+		store := &fakeEventStore{}
+		service := newStreamingService(store, publisher)
+
+		err := service.Subscribe(context.Background(), subscribeReq(lastEventID), func(models.Event) error {
+			return nil
+		})
+
+		assert.ErrorIs(t, err, failure)
+		assert.Zero(t, store.pages, "no replay without a live subscription")
+	})
+}
@@ -0,0 +1,19 @@
+package chatservice
+
+import "errors"
+
+var (
+	ErrNotMember         = errors.New("user is not a member of the chat")
+	ErrInvalidPageSize   = errors.New("page size must not be negative")
+	ErrInvalidChat       = errors.New("invalid chat")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrForbidden         = errors.New("chat role does not allow this")
+	ErrInvalidRole       = errors.New("invalid member role")
+	ErrOwnerMustStay     = errors.New("the owner cannot leave before transferring ownership")
+	ErrEditWindowEnded   = errors.New("the message can no longer be edited")
+	ErrTooManyReactions  = errors.New("too many reactions")
+	ErrInvalidThread     = errors.New("invalid thread")
+	ErrInvalidAttachment = errors.New("invalid attachment")
+	ErrInvalidRange      = errors.New("range is outside the attachment")
+	ErrQuotaExceeded     = errors.New("attachment quota exceeded")
+)
@@ -0,0 +1,304 @@
+package chatservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+// AddMembers adds users to a group chat. Only admins and the owner can add
+// members; users already in the chat are skipped.
+func (s *ChatService) AddMembers(
+	ctx context.Context,
+	req models.AddMembersRequest,
+) (models.AddMembersResponse, error) {
+	if len(req.MemberIDs) == 0 {
+		return models.AddMembersResponse{}, nil
+	}
+
+	missing, err := s.users.MissingUsers(ctx, req.MemberIDs)
+	if err != nil {
+		return models.AddMembersResponse{}, err
+	}
+	if len(missing) > 0 {
+		return models.AddMembersResponse{}, fmt.Errorf("%w: %s", ErrUserNotFound, missing[0])
+	}
+
+	var (
+		added  []models.ChatMember
+		events []models.Event
+	)
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		chat, caller, err := s.lockChat(ctx, req.ChatID, req.UserID)
+		if err != nil {
+			return err
+		}
+		if caller.Role < models.MemberRoleAdmin {
+			return ErrForbidden
+		}
+
+		now := s.now().UTC().Truncate(time.Microsecond)
+		for _, id := range req.MemberIDs {
+			if _, ok := findMember(chat, id); ok {
+				continue
+			}
+			member := models.ChatMember{ChatID: chat.ID, UserID: id, Role: models.MemberRoleMember, JoinedAt: now}
+			chat.Members = append(chat.Members, member)
+			added = append(added, member)
+		}
+		if len(added) == 0 {
+			return nil
+		}
+		if len(chat.Members) > maxGroupMembers {
+			return fmt.Errorf("%w: a group chat has at most %d members", ErrInvalidChat, maxGroupMembers)
+		}
+
+		if err := s.members.Add(ctx, added...); err != nil {
+			return err
+		}
+
+		events, err = s.appendToChat(ctx, chat.ID, models.EventTypeSystem, models.SystemNotificationPayload{
+			Text:  membersAddedText(req.UserID, added),
+			Level: models.SystemNotificationLevelInfo,
+		})
+		return err
+	})
+	if err != nil {
+		return models.AddMembersResponse{}, err
+	}
+
+	s.publish(ctx, events)
+
+	return models.AddMembersResponse{Members: added}, nil
+}
+
+// RemoveMember removes another member from a group chat. Admins can remove
+// members, the owner can also remove admins, and nobody removes the owner.
+func (s *ChatService) RemoveMember(ctx context.Context, req models.RemoveMemberRequest) error {
+	if req.MemberID == req.UserID {
+		return fmt.Errorf("%w: use LeaveChat to leave a chat", ErrInvalidChat)
+	}
+
+	var events []models.Event
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		chat, caller, err := s.lockChat(ctx, req.ChatID, req.UserID)
+		if err != nil {
+			return err
+		}
+
+		target, ok := findMember(chat, req.MemberID)
+		if !ok {
+			return repository.ErrMemberNotFound
+		}
+		if caller.Role < models.MemberRoleAdmin || target.Role >= caller.Role {
+			return ErrForbidden
+		}
+
+		events, err = s.removeMember(ctx, chat.ID, target.UserID, fmt.Sprintf("%s removed %s", req.UserID, target.UserID))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, events)
+
+	return nil
+}
+
+// LeaveChat removes the caller from a group chat. The owner has to
+// transfer ownership first unless nobody else is left.
+func (s *ChatService) LeaveChat(ctx context.Context, req models.LeaveChatRequest) error {
+	var events []models.Event
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		chat, caller, err := s.lockChat(ctx, req.ChatID, req.UserID)
+		if err != nil {
+			return err
+		}
+		if caller.Role == models.MemberRoleOwner && len(chat.Members) > 1 {
+			return ErrOwnerMustStay
+		}
+
+		events, err = s.removeMember(ctx, chat.ID, caller.UserID, fmt.Sprintf("%s left the chat", caller.UserID))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, events)
+
+	return nil
+}
+
+// UpdateMemberRole promotes a member to admin or demotes an admin. Only the
+// owner can change roles.
+func (s *ChatService) UpdateMemberRole(
+	ctx context.Context,
+	req models.UpdateMemberRoleRequest,
+) (models.UpdateMemberRoleResponse, error) {
+	if req.Role != models.MemberRoleMember && req.Role != models.MemberRoleAdmin {
+		return models.UpdateMemberRoleResponse{}, fmt.Errorf("%w: use TransferOwnership to change the owner", ErrInvalidRole)
+	}
+
+	var (
+		target models.ChatMember
+		events []models.Event
+	)
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		chat, caller, err := s.lockChat(ctx, req.ChatID, req.UserID)
+		if err != nil {
+			return err
+		}
+		if caller.Role != models.MemberRoleOwner {
+			return ErrForbidden
+		}
+
+		var ok bool
+		target, ok = findMember(chat, req.MemberID)
+		if !ok {
+			return repository.ErrMemberNotFound
+		}
+		if target.Role == models.MemberRoleOwner {
+			return fmt.Errorf("%w: use TransferOwnership to change the owner", ErrInvalidRole)
+		}
+		if target.Role == req.Role {
+			return nil
+		}
+
+		if err := s.members.UpdateRole(ctx, chat.ID, target.UserID, req.Role); err != nil {
+			return err
+		}
+		target.Role = req.Role
+
+		events, err = s.appendToChat(ctx, chat.ID, models.EventTypeSystem, models.SystemNotificationPayload{
+			Text:  fmt.Sprintf("%s is now %s", target.UserID, roleName(target.Role)),
+			Level: models.SystemNotificationLevelInfo,
+		})
+		return err
+	})
+	if err != nil {
+		return models.UpdateMemberRoleResponse{}, err
+	}
+
+	s.publish(ctx, events)
+
+	return models.UpdateMemberRoleResponse{Member: target}, nil
+}
+
+// TransferOwnership makes another member the owner. The previous owner
+// stays in the chat as an admin.
+func (s *ChatService) TransferOwnership(ctx context.Context, req models.TransferOwnershipRequest) error {
+	if req.NewOwnerID == req.UserID {
+		return fmt.Errorf("%w: the caller already owns the chat", ErrInvalidRole)
+	}
+
+	var events []models.Event
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		chat, caller, err := s.lockChat(ctx, req.ChatID, req.UserID)
+		if err != nil {
+			return err
+		}
+		if caller.Role != models.MemberRoleOwner {
+			return ErrForbidden
+		}
+		if _, ok := findMember(chat, req.NewOwnerID); !ok {
+			return repository.ErrMemberNotFound
+		}
+
+		if err := s.members.UpdateRole(ctx, chat.ID, req.NewOwnerID, models.MemberRoleOwner); err != nil {
+			return err
+		}
+		if err := s.members.UpdateRole(ctx, chat.ID, caller.UserID, models.MemberRoleAdmin); err != nil {
+			return err
+		}
+
+		events, err = s.appendToChat(ctx, chat.ID, models.EventTypeSystem, models.SystemNotificationPayload{
+			Text:  fmt.Sprintf("%s is now the owner", req.NewOwnerID),
+			Level: models.SystemNotificationLevelInfo,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, events)
+
+	return nil
+}
+
+// lockChat locks a group chat for a membership change and returns it with
+// the caller's membership.
+func (s *ChatService) lockChat(ctx context.Context, chatID, userID uuid.UUID) (models.Chat, models.ChatMember, error) {
+	chat, err := s.chats.GetForUpdate(ctx, chatID)
+	if err != nil {
+		return models.Chat{}, models.ChatMember{}, err
+	}
+
+	caller, ok := findMember(chat, userID)
+	if !ok {
+		return models.Chat{}, models.ChatMember{}, ErrNotMember
+	}
+	if chat.Type != models.ChatTypeGroup {
+		return models.Chat{}, models.ChatMember{}, fmt.Errorf("%w: members of a direct chat cannot change", ErrInvalidChat)
+	}
+
+	return chat, caller, nil
+}
+
+// removeMember tells the chat, the removed user included, and then takes
+// the user out of it, so that no later event of the chat reaches them.
+func (s *ChatService) removeMember(ctx context.Context, chatID, userID uuid.UUID, text string) ([]models.Event, error) {
+	events, err := s.appendToChat(ctx, chatID, models.EventTypeSystem, models.SystemNotificationPayload{
+		Text:  text,
+		Level: models.SystemNotificationLevelInfo,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.members.Remove(ctx, chatID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := s.readModel.RemoveChat(ctx, userID, chatID); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func findMember(chat models.Chat, userID uuid.UUID) (models.ChatMember, bool) {
+	for _, m := range chat.Members {
+		if m.UserID == userID {
+			return m, true
+		}
+	}
+
+	return models.ChatMember{}, false
+}
+
+func membersAddedText(by uuid.UUID, added []models.ChatMember) string {
+	if len(added) == 1 {
+		return fmt.Sprintf("%s added %s", by, added[0].UserID)
+	}
+
+	return fmt.Sprintf("%s added %d members", by, len(added))
+}
+
+func roleName(role models.MemberRole) string {
+	switch role {
+	case models.MemberRoleOwner:
+		return "the owner"
+	case models.MemberRoleAdmin:
+		return "an admin"
+	default:
+		return "a member"
+	}
+}
@@ -0,0 +1,235 @@
+package chatservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+var (
+	testAdminID  = uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	testMemberID = uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+)
+
+// testGroup is owned by testUserID with one admin and one member.
+func testGroup() models.Chat {
+	return models.Chat{
+		ID:   testChatID,
+		Name: "team",
+		Type: models.ChatTypeGroup,
+		Members: []models.ChatMember{
+			{ChatID: testChatID, UserID: testUserID, Role: models.MemberRoleOwner},
+			{ChatID: testChatID, UserID: testAdminID, Role: models.MemberRoleAdmin},
+			{ChatID: testChatID, UserID: testMemberID, Role: models.MemberRoleMember},
+		},
+	}
+}
+
+func expectSystemEvent(ctx context.Context, m chatServiceMocks) {
+	events := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeSystem}}
+	m.events.EXPECT().AppendToChat(ctx, testChatID, models.EventTypeSystem, gomock.Any()).Return(events, nil)
+	m.readModel.EXPECT().Apply(ctx, events[0]).Return(nil)
+	m.publisher.EXPECT().Publish(ctx, events[0]).Return(nil)
+}
+
+func TestChatService_AddMembers(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	newcomer := uuid.New()
+
+	m.users.EXPECT().MissingUsers(ctx, []uuid.UUID{newcomer, testMemberID, newcomer}).Return(nil, nil)
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+	m.members.EXPECT().
+		Add(ctx, models.ChatMember{ChatID: testChatID, UserID: newcomer, Role: models.MemberRoleMember, JoinedAt: testNow}).
+		Return(nil)
+	expectSystemEvent(ctx, m)
+
+	resp, err := service.AddMembers(ctx, models.AddMembersRequest{
+		ChatID:    testChatID,
+		UserID:    testAdminID,
+		MemberIDs: []uuid.UUID{newcomer, testMemberID, newcomer},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Members, 1, "existing members and duplicates are skipped")
+	assert.Equal(t, newcomer, resp.Members[0].UserID)
+}
+
+func TestChatService_AddMembersForbidden(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.users.EXPECT().MissingUsers(ctx, gomock.Any()).Return(nil, nil)
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+
+	_, err := service.AddMembers(ctx, models.AddMembersRequest{
+		ChatID:    testChatID,
+		UserID:    testMemberID,
+		MemberIDs: []uuid.UUID{uuid.New()},
+	})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestChatService_AddMembersDirectChat(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	chat := testGroup()
+	chat.Type = models.ChatTypeDirect
+
+	m.users.EXPECT().MissingUsers(ctx, gomock.Any()).Return(nil, nil)
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(chat, nil)
+
+	_, err := service.AddMembers(ctx, models.AddMembersRequest{
+		ChatID:    testChatID,
+		UserID:    testUserID,
+		MemberIDs: []uuid.UUID{uuid.New()},
+	})
+
+	assert.ErrorIs(t, err, ErrInvalidChat)
+}
+
+func TestChatService_RemoveMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+	gomock.InOrder(
+		m.events.EXPECT().
+			AppendToChat(ctx, testChatID, models.EventTypeSystem, gomock.Any()).
+			Return([]models.Event{{ID: uuid.Must(uuid.NewV7())}}, nil),
+		m.readModel.EXPECT().Apply(ctx, gomock.Any()).Return(nil),
+		m.members.EXPECT().Remove(ctx, testChatID, testMemberID).Return(nil),
+		m.readModel.EXPECT().RemoveChat(ctx, testMemberID, testChatID).Return(nil),
+		m.publisher.EXPECT().Publish(ctx, gomock.Any()).Return(nil),
+	)
+
+	err := service.RemoveMember(ctx, models.RemoveMemberRequest{
+		ChatID:   testChatID,
+		UserID:   testAdminID,
+		MemberID: testMemberID,
+	})
+
+	require.NoError(t, err)
+}
+
+func TestChatService_RemoveMemberRejects(t *testing.T) {
+	tests := map[string]struct {
+		caller, target uuid.UUID
+		err            error
+	}{
+		"member removes member": {caller: testMemberID, target: testAdminID, err: ErrForbidden},
+		"admin removes owner":   {caller: testAdminID, target: testUserID, err: ErrForbidden},
+		"unknown member":        {caller: testUserID, target: uuid.New(), err: repository.ErrMemberNotFound},
+		"not a member":          {caller: uuid.New(), target: testMemberID, err: ErrNotMember},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+
+			m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+
+			err := service.RemoveMember(ctx, models.RemoveMemberRequest{
+				ChatID:   testChatID,
+				UserID:   tt.caller,
+				MemberID: tt.target,
+			})
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestChatService_LeaveChat(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+	m.members.EXPECT().Remove(ctx, testChatID, testAdminID).Return(nil)
+	m.readModel.EXPECT().RemoveChat(ctx, testAdminID, testChatID).Return(nil)
+	expectSystemEvent(ctx, m)
+
+	err := service.LeaveChat(ctx, models.LeaveChatRequest{ChatID: testChatID, UserID: testAdminID})
+
+	require.NoError(t, err)
+}
+
+func TestChatService_LeaveChatOwner(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+
+	err := service.LeaveChat(ctx, models.LeaveChatRequest{ChatID: testChatID, UserID: testUserID})
+
+	assert.ErrorIs(t, err, ErrOwnerMustStay)
+}
+
+func TestChatService_UpdateMemberRole(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+	m.members.EXPECT().UpdateRole(ctx, testChatID, testMemberID, models.MemberRoleAdmin).Return(nil)
+	expectSystemEvent(ctx, m)
+
+	resp, err := service.UpdateMemberRole(ctx, models.UpdateMemberRoleRequest{
+		ChatID:   testChatID,
+		UserID:   testUserID,
+		MemberID: testMemberID,
+		Role:     models.MemberRoleAdmin,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.MemberRoleAdmin, resp.Member.Role)
+}
+
+func TestChatService_UpdateMemberRoleRejects(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	_, err := service.UpdateMemberRole(ctx, models.UpdateMemberRoleRequest{
+		ChatID:   testChatID,
+		UserID:   testUserID,
+		MemberID: testMemberID,
+		Role:     models.MemberRoleOwner,
+	})
+	assert.ErrorIs(t, err, ErrInvalidRole)
+
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+
+	_, err = service.UpdateMemberRole(ctx, models.UpdateMemberRoleRequest{
+		ChatID:   testChatID,
+		UserID:   testAdminID,
+		MemberID: testMemberID,
+		Role:     models.MemberRoleAdmin,
+	})
+	assert.ErrorIs(t, err, ErrForbidden, "only the owner promotes to admin")
+}
+
+func TestChatService_TransferOwnership(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.chats.EXPECT().GetForUpdate(ctx, testChatID).Return(testGroup(), nil)
+	m.members.EXPECT().UpdateRole(ctx, testChatID, testMemberID, models.MemberRoleOwner).Return(nil)
+	m.members.EXPECT().UpdateRole(ctx, testChatID, testUserID, models.MemberRoleAdmin).Return(nil)
+	expectSystemEvent(ctx, m)
+
+	err := service.TransferOwnership(ctx, models.TransferOwnershipRequest{
+		ChatID:     testChatID,
+		UserID:     testUserID,
+		NewOwnerID: testMemberID,
+	})
+
+	require.NoError(t, err)
+}
@@ -0,0 +1,411 @@
+package chatservice
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+// SendMessage stores the message and a MESSAGE_NEW event for every member
+// in one transaction. A retry with the same idempotency key within the
+// configured TTL returns the original message instead of a new one. A reply
+// in a thread also updates the summary of the thread's root and sends a
+// THREAD_UPDATED.
+func (s *ChatService) SendMessage(
+	ctx context.Context,
+	req models.SendMessageRequest,
+) (models.SendMessageResponse, error) {
+	now := s.now().UTC().Truncate(time.Microsecond)
+
+	messageID, err := uuid.NewV7()
+	if err != nil {
+		return models.SendMessageResponse{}, fmt.Errorf("generate message id: %w", err)
+	}
+
+	message := models.Message{
+		ID:           messageID,
+		ChatID:       req.ChatID,
+		SenderID:     req.SenderID,
+		Content:      req.Content,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		ThreadRootID: req.ThreadRootID,
+	}
+	result := models.IdempotencyResult{MessageID: messageID, CreatedAt: now}
+
+	var events []models.Event
+	err = s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.requireMember(ctx, req.ChatID, req.SenderID); err != nil {
+			return err
+		}
+
+		if req.IdempotencyKey != "" {
+			claimed, ok, err := s.idempotency.Claim(ctx, req.SenderID, req.ChatID, req.IdempotencyKey, result, now.Add(-s.config.IdempotencyTTL))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				result = claimed
+				return nil
+			}
+		}
+
+		if message.ThreadRootID != nil {
+			if err := s.requireThreadRoot(ctx, *message.ThreadRootID, message.ChatID); err != nil {
+				return err
+			}
+		}
+		if err := s.requireAttachment(ctx, message.Content, message.ChatID, message.SenderID); err != nil {
+			return err
+		}
+
+		if err := s.messages.Create(ctx, message); err != nil {
+			return err
+		}
+
+		events, err = s.appendToChat(ctx, req.ChatID, models.EventTypeMessageNew, models.MessageNewPayload{
+			MessageID:    message.ID,
+			ChatID:       message.ChatID,
+			SenderID:     message.SenderID,
+			Content:      message.Content,
+			CreatedAt:    message.CreatedAt,
+			ThreadRootID: message.ThreadRootID,
+		})
+		if err != nil || message.ThreadRootID == nil {
+			return err
+		}
+
+		threadEvents, err := s.addThreadReply(ctx, message)
+		events = append(events, threadEvents...)
+		return err
+	})
+	if err != nil {
+		return models.SendMessageResponse{}, err
+	}
+
+	s.publish(ctx, events)
+
+	return models.SendMessageResponse{
+		MessageID: result.MessageID,
+		CreatedAt: result.CreatedAt,
+	}, nil
+}
+
+// EditMessage replaces the content of a message. Only its sender can edit
+// it, and only within the configured edit window.
+func (s *ChatService) EditMessage(
+	ctx context.Context,
+	req models.EditMessageRequest,
+) (models.EditMessageResponse, error) {
+	now := s.now().UTC().Truncate(time.Microsecond)
+
+	var (
+		message models.Message
+		events  []models.Event
+	)
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		var err error
+		message, err = s.messages.GetForUpdate(ctx, req.MessageID)
+		if err != nil {
+			return err
+		}
+		if message.DeletedAt != nil {
+			return repository.ErrMessageNotFound
+		}
+		if err := s.requireMember(ctx, message.ChatID, req.UserID); err != nil {
+			return err
+		}
+		if message.SenderID != req.UserID {
+			return ErrForbidden
+		}
+		if now.Sub(message.CreatedAt) > s.config.EditWindow {
+			return ErrEditWindowEnded
+		}
+		if err := s.requireAttachment(ctx, req.Content, message.ChatID, req.UserID); err != nil {
+			return err
+		}
+
+		if err := s.messages.UpdateContent(ctx, message.ID, req.Content, now); err != nil {
+			return err
+		}
+		message.Content = req.Content
+		message.UpdatedAt = now
+
+		events, err = s.appendToChat(ctx, message.ChatID, models.EventTypeMessageUpdated, models.MessageUpdatedPayload{
+			MessageID:  message.ID,
+			ChatID:     message.ChatID,
+			NewContent: message.Content,
+			UpdatedAt:  now,
+		})
+		return err
+	})
+	if err != nil {
+		return models.EditMessageResponse{}, err
+	}
+
+	s.publish(ctx, events)
+
+	return models.EditMessageResponse{Message: message}, nil
+}
+
+// DeleteMessage erases the content of a message and leaves a tombstone in
+// the history. The sender or a chat admin can delete it; deleting it again
+// is a no-op.
+func (s *ChatService) DeleteMessage(ctx context.Context, req models.DeleteMessageRequest) error {
+	now := s.now().UTC().Truncate(time.Microsecond)
+
+	var events []models.Event
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		message, err := s.messages.GetForUpdate(ctx, req.MessageID)
+		if err != nil {
+			return err
+		}
+
+		member, err := s.members.Get(ctx, message.ChatID, req.UserID)
+		if err != nil {
+			if errors.Is(err, repository.ErrMemberNotFound) {
+				return ErrNotMember
+			}
+			return err
+		}
+		if message.SenderID != req.UserID && member.Role < models.MemberRoleAdmin {
+			return ErrForbidden
+		}
+		if message.DeletedAt != nil {
+			return nil
+		}
+
+		if err := s.messages.Delete(ctx, message.ID, now); err != nil {
+			return err
+		}
+
+		events, err = s.appendToChat(ctx, message.ChatID, models.EventTypeMessageDeleted, models.MessageDeletedPayload{
+			MessageID: message.ID,
+			ChatID:    message.ChatID,
+			DeletedAt: now,
+		})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	s.publish(ctx, events)
+
+	return nil
+}
+
+// requireThreadRoot locks the message a reply is posted under and checks
+// that it can hold a thread: it is in the chat, not deleted and not a reply
+// itself.
+func (s *ChatService) requireThreadRoot(ctx context.Context, rootID, chatID uuid.UUID) error {
+	root, err := s.messages.GetForUpdate(ctx, rootID)
+	if err != nil {
+		if errors.Is(err, repository.ErrMessageNotFound) {
+			return fmt.Errorf("%w: root message not found", ErrInvalidThread)
+		}
+		return err
+	}
+
+	switch {
+	case root.ChatID != chatID:
+		return fmt.Errorf("%w: root message is in another chat", ErrInvalidThread)
+	case root.DeletedAt != nil:
+		return fmt.Errorf("%w: root message is deleted", ErrInvalidThread)
+	case root.ThreadRootID != nil:
+		return fmt.Errorf("%w: a reply cannot start a thread", ErrInvalidThread)
+	}
+
+	return nil
+}
+
+// addThreadReply counts the reply on its root and appends the
+// THREAD_UPDATED event.
+func (s *ChatService) addThreadReply(ctx context.Context, reply models.Message) ([]models.Event, error) {
+	count, err := s.messages.AddThreadReply(ctx, *reply.ThreadRootID, reply.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.appendToChat(ctx, reply.ChatID, models.EventTypeThreadUpdated, models.ThreadUpdatedPayload{
+		ChatID:        reply.ChatID,
+		RootMessageID: *reply.ThreadRootID,
+		ReplyCount:    count,
+		LastReplyID:   reply.ID,
+		LastReplyAt:   reply.CreatedAt,
+	})
+}
+
+func (s *ChatService) requireMember(ctx context.Context, chatID, userID uuid.UUID) error {
+	if _, err := s.members.Get(ctx, chatID, userID); err != nil {
+		if errors.Is(err, repository.ErrMemberNotFound) {
+			return ErrNotMember
+		}
+		return err
+	}
+
+	return nil
+}
+
+// GetHistory pages backwards through the chat, newest message first. The
+// next cursor is empty once the page reaches the first message of the chat.
+func (s *ChatService) GetHistory(
+	ctx context.Context,
+	req models.GetHistoryRequest,
+) (models.GetHistoryResponse, error) {
+	pageSize, err := clampPageSize(req.PageSize)
+	if err != nil {
+		return models.GetHistoryResponse{}, err
+	}
+
+	var before uuid.UUID
+	if req.Cursor != "" {
+		cur, err := s.cursors.Decode(req.Cursor, req.ChatID)
+		if err != nil {
+			return models.GetHistoryResponse{}, err
+		}
+		if cur.Direction != cursor.Backward {
+			return models.GetHistoryResponse{}, cursor.ErrInvalidCursor
+		}
+		before = cur.ID
+	}
+
+	if err := s.requireMember(ctx, req.ChatID, req.UserID); err != nil {
+		return models.GetHistoryResponse{}, err
+	}
+
+	messages, err := s.messages.ListBefore(ctx, req.ChatID, before, pageSize+1)
+	if err != nil {
+		return models.GetHistoryResponse{}, err
+	}
+
+	var resp models.GetHistoryResponse
+	if len(messages) > int(pageSize) {
+		messages = messages[:pageSize]
+		resp.NextCursor = s.cursors.Encode(cursor.Cursor{
+			Direction: cursor.Backward,
+			Scope:     req.ChatID,
+			ID:        messages[len(messages)-1].ID,
+		})
+	}
+
+	resp.Messages, err = s.withReactions(ctx, req.UserID, messages)
+	if err != nil {
+		return models.GetHistoryResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// GetThread returns the root message and pages backwards through its
+// replies, newest first. The next cursor is empty once the page reaches the
+// first reply.
+func (s *ChatService) GetThread(
+	ctx context.Context,
+	req models.GetThreadRequest,
+) (models.GetThreadResponse, error) {
+	pageSize, err := clampPageSize(req.PageSize)
+	if err != nil {
+		return models.GetThreadResponse{}, err
+	}
+
+	var before uuid.UUID
+	if req.Cursor != "" {
+		cur, err := s.cursors.Decode(req.Cursor, req.RootMessageID)
+		if err != nil {
+			return models.GetThreadResponse{}, err
+		}
+		if cur.Direction != cursor.Backward {
+			return models.GetThreadResponse{}, cursor.ErrInvalidCursor
+		}
+		before = cur.ID
+	}
+
+	root, err := s.messages.Get(ctx, req.RootMessageID)
+	if err != nil {
+		return models.GetThreadResponse{}, err
+	}
+	if err := s.requireMember(ctx, root.ChatID, req.UserID); err != nil {
+		return models.GetThreadResponse{}, err
+	}
+	if root.ThreadRootID != nil {
+		return models.GetThreadResponse{}, fmt.Errorf("%w: the message is a reply", ErrInvalidThread)
+	}
+
+	replies, err := s.messages.ListThreadBefore(ctx, root.ID, before, pageSize+1)
+	if err != nil {
+		return models.GetThreadResponse{}, err
+	}
+
+	var resp models.GetThreadResponse
+	if len(replies) > int(pageSize) {
+		replies = replies[:pageSize]
+		resp.NextCursor = s.cursors.Encode(cursor.Cursor{
+			Direction: cursor.Backward,
+			Scope:     root.ID,
+			ID:        replies[len(replies)-1].ID,
+		})
+	}
+
+	messages, err := s.withReactions(ctx, req.UserID, append([]models.Message{root}, replies...))
+	if err != nil {
+		return models.GetThreadResponse{}, err
+	}
+	resp.Root, resp.Replies = messages[0], messages[1:]
+
+	return resp, nil
+}
+
+// withReactions returns a copy of the messages with their reaction
+// summaries as the viewer sees them. Deleted messages have none.
+func (s *ChatService) withReactions(
+	ctx context.Context,
+	viewerID uuid.UUID,
+	messages []models.Message,
+) ([]models.Message, error) {
+	ids := make([]uuid.UUID, 0, len(messages))
+	for _, m := range messages {
+		if m.DeletedAt == nil {
+			ids = append(ids, m.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return messages, nil
+	}
+
+	summaries, err := s.reactions.Summaries(ctx, viewerID, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Message, len(messages))
+	for i, m := range messages {
+		if m.DeletedAt == nil {
+			m.Reactions = summaries[m.ID]
+		}
+		result[i] = m
+	}
+
+	return result, nil
+}
+
+// clampPageSize applies the default to an unset page size and caps it.
+func clampPageSize(pageSize int32) (int32, error) {
+	switch {
+	case pageSize < 0:
+		return 0, ErrInvalidPageSize
+	case pageSize == 0:
+		return defaultPageSize, nil
+	case pageSize > maxPageSize:
+		return maxPageSize, nil
+	default:
+		return pageSize, nil
+	}
+}
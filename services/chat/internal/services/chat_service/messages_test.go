@@ -0,0 +1,534 @@
+package chatservice
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+var (
+	testChatID = uuid.MustParse("01890a5d-ac96-774b-bcce-b302099a8057")
+	testUserID = uuid.MustParse("550e8400-e29b-41d4-a716-446655440000")
+)
+
+func testSendMessageRequest() models.SendMessageRequest {
+	return models.SendMessageRequest{
+		ChatID:         testChatID,
+		SenderID:       testUserID,
+		IdempotencyKey: "key-1",
+		Content:        models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+	}
+}
+
+func TestChatService_SendMessage(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	req := testSendMessageRequest()
+
+	m.members.EXPECT().
+		Get(ctx, testChatID, testUserID).
+		Return(models.ChatMember{ChatID: testChatID, UserID: testUserID}, nil)
+	m.idempotency.EXPECT().
+		Claim(ctx, testUserID, testChatID, "key-1", gomock.Any(), testNow.Add(-time.Hour)).
+		DoAndReturn(func(_ context.Context, _, _ uuid.UUID, _ string, result models.IdempotencyResult, _ time.Time) (models.IdempotencyResult, bool, error) {
+			return result, true, nil
+		})
+
+	var stored models.Message
+	m.messages.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, message models.Message) error {
+			stored = message
+			return nil
+		})
+
+	events := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeMessageNew}}
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeMessageNew, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ models.EventType, payload any) ([]models.Event, error) {
+			p, ok := payload.(models.MessageNewPayload)
+			require.True(t, ok)
+			assert.Equal(t, stored.ID, p.MessageID)
+			assert.Equal(t, req.Content, p.Content)
+			return events, nil
+		})
+	m.readModel.EXPECT().Apply(ctx, events[0]).Return(nil)
+	m.publisher.EXPECT().
+		Publish(ctx, events[0]).
+		Return(nil)
+
+	resp, err := service.SendMessage(ctx, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, stored.ID, resp.MessageID)
+	assert.Equal(t, testNow, resp.CreatedAt)
+	assert.Equal(t, testUserID, stored.SenderID)
+	assert.Equal(t, uuid.Version(7), stored.ID.Version(), "message ids are UUIDv7")
+}
+
+func TestChatService_SendMessageRetry(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	original := models.IdempotencyResult{MessageID: uuid.Must(uuid.NewV7()), CreatedAt: testNow.Add(-time.Minute)}
+
+	m.members.EXPECT().
+		Get(ctx, testChatID, testUserID).
+		Return(models.ChatMember{}, nil)
+	m.idempotency.EXPECT().
+		Claim(ctx, testUserID, testChatID, "key-1", gomock.Any(), gomock.Any()).
+		Return(original, false, nil)
+
+	resp, err := service.SendMessage(ctx, testSendMessageRequest())
+
+	require.NoError(t, err)
+	assert.Equal(t, original.MessageID, resp.MessageID)
+	assert.Equal(t, original.CreatedAt, resp.CreatedAt)
+}
+
+func TestChatService_SendMessageNotMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.members.EXPECT().
+		Get(ctx, testChatID, testUserID).
+		Return(models.ChatMember{}, repository.ErrMemberNotFound)
+
+	_, err := service.SendMessage(ctx, testSendMessageRequest())
+
+	assert.ErrorIs(t, err, ErrNotMember)
+}
+
+func TestChatService_SendMessagePublishFailure(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	req := testSendMessageRequest()
+	req.IdempotencyKey = ""
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+	m.messages.EXPECT().Create(ctx, gomock.Any()).Return(nil)
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeMessageNew, gomock.Any()).
+		Return([]models.Event{{ID: uuid.Must(uuid.NewV7())}}, nil)
+	m.readModel.EXPECT().Apply(ctx, gomock.Any()).Return(nil)
+	m.publisher.EXPECT().
+		Publish(ctx, gomock.Any()).
+		Return(errors.New("broker is down"))
+
+	_, err := service.SendMessage(ctx, req)
+
+	assert.NoError(t, err, "committed messages are delivered from the event log")
+}
+
+// testSentMessage was sent by testUserID ten minutes before testNow.
+func testSentMessage() models.Message {
+	return models.Message{
+		ID:        uuid.Must(uuid.NewV7()),
+		ChatID:    testChatID,
+		SenderID:  testUserID,
+		Content:   models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hi")},
+		CreatedAt: testNow.Add(-10 * time.Minute),
+		UpdatedAt: testNow.Add(-10 * time.Minute),
+	}
+}
+
+func TestChatService_EditMessage(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+	content := models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("hello")}
+
+	m.messages.EXPECT().GetForUpdate(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+	m.messages.EXPECT().UpdateContent(ctx, message.ID, content, testNow).Return(nil)
+
+	events := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeMessageUpdated}}
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeMessageUpdated, models.MessageUpdatedPayload{
+			MessageID:  message.ID,
+			ChatID:     testChatID,
+			NewContent: content,
+			UpdatedAt:  testNow,
+		}).
+		Return(events, nil)
+	m.readModel.EXPECT().Apply(ctx, events[0]).Return(nil)
+	m.publisher.EXPECT().Publish(ctx, events[0]).Return(nil)
+
+	resp, err := service.EditMessage(ctx, models.EditMessageRequest{MessageID: message.ID, UserID: testUserID, Content: content})
+
+	require.NoError(t, err)
+	assert.Equal(t, content, resp.Message.Content)
+	assert.Equal(t, testNow, resp.Message.UpdatedAt)
+}
+
+func TestChatService_EditMessageRejects(t *testing.T) {
+	deleted := testSentMessage()
+	deletedAt := testNow.Add(-time.Minute)
+	deleted.DeletedAt = &deletedAt
+
+	old := testSentMessage()
+	old.CreatedAt = testNow.Add(-2 * time.Hour)
+
+	tests := map[string]struct {
+		message models.Message
+		userID  uuid.UUID
+		err     error
+	}{
+		"not the sender":    {message: testSentMessage(), userID: testAdminID, err: ErrForbidden},
+		"edit window ended": {message: old, userID: testUserID, err: ErrEditWindowEnded},
+		"deleted message":   {message: deleted, userID: testUserID, err: repository.ErrMessageNotFound},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+
+			m.messages.EXPECT().GetForUpdate(ctx, tt.message.ID).Return(tt.message, nil)
+			m.members.EXPECT().Get(ctx, testChatID, tt.userID).Return(models.ChatMember{}, nil).AnyTimes()
+
+			_, err := service.EditMessage(ctx, models.EditMessageRequest{
+				MessageID: tt.message.ID,
+				UserID:    tt.userID,
+				Content:   models.MessageContent{Type: models.ContentTypeText, Ciphertext: []byte("x")},
+			})
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestChatService_DeleteMessageByAdmin(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+
+	m.messages.EXPECT().GetForUpdate(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testAdminID).Return(models.ChatMember{Role: models.MemberRoleAdmin}, nil)
+	m.messages.EXPECT().Delete(ctx, message.ID, testNow).Return(nil)
+
+	events := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeMessageDeleted}}
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeMessageDeleted, models.MessageDeletedPayload{
+			MessageID: message.ID,
+			ChatID:    testChatID,
+			DeletedAt: testNow,
+		}).
+		Return(events, nil)
+	m.readModel.EXPECT().Apply(ctx, events[0]).Return(nil)
+	m.publisher.EXPECT().Publish(ctx, events[0]).Return(nil)
+
+	err := service.DeleteMessage(ctx, models.DeleteMessageRequest{MessageID: message.ID, UserID: testAdminID})
+
+	require.NoError(t, err)
+}
+
+func TestChatService_DeleteMessageByOtherMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+
+	m.messages.EXPECT().GetForUpdate(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{Role: models.MemberRoleMember}, nil)
+
+	err := service.DeleteMessage(ctx, models.DeleteMessageRequest{MessageID: message.ID, UserID: testMemberID})
+
+	assert.ErrorIs(t, err, ErrForbidden)
+}
+
+func TestChatService_DeleteMessageTwice(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+	message.DeletedAt = &testNow
+
+	m.messages.EXPECT().GetForUpdate(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+
+	err := service.DeleteMessage(ctx, models.DeleteMessageRequest{MessageID: message.ID, UserID: testUserID})
+
+	require.NoError(t, err, "deleting a tombstone is a no-op")
+}
+
+func testMessages(n int) []models.Message {
+	messages := make([]models.Message, n)
+	for i := range messages {
+		messages[i] = models.Message{ID: uuid.Must(uuid.NewV7()), ChatID: testChatID}
+	}
+
+	return messages
+}
+
+func TestChatService_GetHistory(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	page := testMessages(3)
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil).Times(2)
+	m.messages.EXPECT().
+		ListBefore(ctx, testChatID, uuid.Nil, int32(3)).
+		Return(page, nil)
+	thumbsUp := []models.ReactionSummary{{Emoji: "👍", Count: 2, ReactedByMe: true}}
+	m.reactions.EXPECT().
+		Summaries(ctx, testUserID, []uuid.UUID{page[0].ID, page[1].ID}).
+		Return(map[uuid.UUID][]models.ReactionSummary{page[0].ID: thumbsUp}, nil)
+
+	resp, err := service.GetHistory(ctx, models.GetHistoryRequest{ChatID: testChatID, UserID: testUserID, PageSize: 2})
+
+	require.NoError(t, err)
+	require.Len(t, resp.Messages, 2)
+	assert.Equal(t, page[0].ID, resp.Messages[0].ID)
+	assert.Equal(t, thumbsUp, resp.Messages[0].Reactions)
+	assert.Equal(t, page[1], resp.Messages[1])
+	require.NotEmpty(t, resp.NextCursor)
+
+	m.messages.EXPECT().
+		ListBefore(ctx, testChatID, page[1].ID, int32(3)).
+		Return(page[2:], nil)
+	m.reactions.EXPECT().Summaries(ctx, testUserID, []uuid.UUID{page[2].ID}).Return(nil, nil)
+
+	resp, err = service.GetHistory(ctx, models.GetHistoryRequest{
+		ChatID:   testChatID,
+		UserID:   testUserID,
+		PageSize: 2,
+		Cursor:   resp.NextCursor,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, page[2:], resp.Messages)
+	assert.Empty(t, resp.NextCursor, "no cursor at the start of the chat")
+}
+
+func TestChatService_GetHistoryPageSize(t *testing.T) {
+	tests := map[string]struct {
+		pageSize int32
+		limit    int32
+	}{
+		"default": {pageSize: 0, limit: defaultPageSize + 1},
+		"capped":  {pageSize: 1000, limit: maxPageSize + 1},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+
+			m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+			m.messages.EXPECT().ListBefore(ctx, testChatID, uuid.Nil, tt.limit).Return(nil, nil)
+
+			_, err := service.GetHistory(ctx, models.GetHistoryRequest{ChatID: testChatID, UserID: testUserID, PageSize: tt.pageSize})
+
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestChatService_GetHistoryRejects(t *testing.T) {
+	otherChatCursor := testCursors.Encode(cursor.Cursor{Direction: cursor.Backward, Scope: uuid.New(), ID: uuid.New()})
+	forwardCursor := testCursors.Encode(cursor.Cursor{Direction: cursor.Forward, Scope: testChatID, ID: uuid.New()})
+
+	tests := map[string]struct {
+		req  models.GetHistoryRequest
+		want error
+	}{
+		"negative page size": {req: models.GetHistoryRequest{PageSize: -1}, want: ErrInvalidPageSize},
+		"forged cursor":      {req: models.GetHistoryRequest{Cursor: uuid.NewString()}, want: cursor.ErrInvalidCursor},
+		"other chat cursor":  {req: models.GetHistoryRequest{Cursor: otherChatCursor}, want: cursor.ErrInvalidCursor},
+		"forward cursor":     {req: models.GetHistoryRequest{Cursor: forwardCursor}, want: cursor.ErrInvalidCursor},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, _ := newTestChatService(t)
+			tt.req.ChatID = testChatID
+			tt.req.UserID = testUserID
+
+			_, err := service.GetHistory(context.Background(), tt.req)
+
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+}
+
+func TestChatService_GetHistoryNotMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, repository.ErrMemberNotFound)
+
+	_, err := service.GetHistory(ctx, models.GetHistoryRequest{ChatID: testChatID, UserID: testUserID})
+
+	assert.ErrorIs(t, err, ErrNotMember)
+}
+
+func TestChatService_SendMessageToThread(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	root := testSentMessage()
+	req := testSendMessageRequest()
+	req.IdempotencyKey = ""
+	req.ThreadRootID = &root.ID
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+	m.messages.EXPECT().GetForUpdate(ctx, root.ID).Return(root, nil)
+
+	var stored models.Message
+	m.messages.EXPECT().
+		Create(ctx, gomock.Any()).
+		DoAndReturn(func(_ context.Context, message models.Message) error {
+			stored = message
+			return nil
+		})
+	newEvents := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeMessageNew}}
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeMessageNew, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ models.EventType, payload any) ([]models.Event, error) {
+			assert.Equal(t, &root.ID, payload.(models.MessageNewPayload).ThreadRootID)
+			return newEvents, nil
+		})
+	m.messages.EXPECT().AddThreadReply(ctx, root.ID, testNow).Return(int32(3), nil)
+	threadEvents := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeThreadUpdated}}
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeThreadUpdated, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ uuid.UUID, _ models.EventType, payload any) ([]models.Event, error) {
+			assert.Equal(t, models.ThreadUpdatedPayload{
+				ChatID:        testChatID,
+				RootMessageID: root.ID,
+				ReplyCount:    3,
+				LastReplyID:   stored.ID,
+				LastReplyAt:   testNow,
+			}, payload)
+			return threadEvents, nil
+		})
+	m.readModel.EXPECT().Apply(ctx, newEvents[0]).Return(nil)
+	m.readModel.EXPECT().Apply(ctx, threadEvents[0]).Return(nil)
+	m.publisher.EXPECT().Publish(ctx, newEvents[0], threadEvents[0]).Return(nil)
+
+	resp, err := service.SendMessage(ctx, req)
+
+	require.NoError(t, err)
+	assert.Equal(t, stored.ID, resp.MessageID)
+	assert.Equal(t, &root.ID, stored.ThreadRootID)
+}
+
+func TestChatService_SendMessageToThreadRejects(t *testing.T) {
+	deletedAt := testNow
+	replyTo := uuid.New()
+
+	tests := map[string]func(root *models.Message){
+		"other chat": func(root *models.Message) { root.ChatID = uuid.New() },
+		"deleted":    func(root *models.Message) { root.DeletedAt = &deletedAt },
+		"reply":      func(root *models.Message) { root.ThreadRootID = &replyTo },
+	}
+
+	for name, change := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+			root := testSentMessage()
+			change(&root)
+			req := testSendMessageRequest()
+			req.IdempotencyKey = ""
+			req.ThreadRootID = &root.ID
+
+			m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+			m.messages.EXPECT().GetForUpdate(ctx, root.ID).Return(root, nil)
+
+			_, err := service.SendMessage(ctx, req)
+
+			assert.ErrorIs(t, err, ErrInvalidThread)
+		})
+	}
+}
+
+func TestChatService_SendMessageToMissingThread(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	rootID := uuid.New()
+	req := testSendMessageRequest()
+	req.IdempotencyKey = ""
+	req.ThreadRootID = &rootID
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+	m.messages.EXPECT().GetForUpdate(ctx, rootID).Return(models.Message{}, repository.ErrMessageNotFound)
+
+	_, err := service.SendMessage(ctx, req)
+
+	assert.ErrorIs(t, err, ErrInvalidThread)
+}
+
+func TestChatService_GetThread(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	root := testSentMessage()
+	replies := testMessages(3)
+
+	m.messages.EXPECT().Get(ctx, root.ID).Return(root, nil).Times(2)
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil).Times(2)
+	m.messages.EXPECT().
+		ListThreadBefore(ctx, root.ID, uuid.Nil, int32(3)).
+		Return(replies, nil)
+	m.reactions.EXPECT().
+		Summaries(ctx, testUserID, []uuid.UUID{root.ID, replies[0].ID, replies[1].ID}).
+		Return(nil, nil)
+
+	resp, err := service.GetThread(ctx, models.GetThreadRequest{RootMessageID: root.ID, UserID: testUserID, PageSize: 2})
+
+	require.NoError(t, err)
+	assert.Equal(t, root, resp.Root)
+	assert.Equal(t, replies[:2], resp.Replies)
+	require.NotEmpty(t, resp.NextCursor)
+
+	m.messages.EXPECT().
+		ListThreadBefore(ctx, root.ID, replies[1].ID, int32(3)).
+		Return(replies[2:], nil)
+	m.reactions.EXPECT().Summaries(ctx, testUserID, []uuid.UUID{root.ID, replies[2].ID}).Return(nil, nil)
+
+	resp, err = service.GetThread(ctx, models.GetThreadRequest{
+		RootMessageID: root.ID,
+		UserID:        testUserID,
+		PageSize:      2,
+		Cursor:        resp.NextCursor,
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, replies[2:], resp.Replies)
+	assert.Empty(t, resp.NextCursor, "no cursor at the first reply")
+}
+
+func TestChatService_GetThreadOfReply(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	reply := testSentMessage()
+	rootID := uuid.New()
+	reply.ThreadRootID = &rootID
+
+	m.messages.EXPECT().Get(ctx, reply.ID).Return(reply, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+
+	_, err := service.GetThread(ctx, models.GetThreadRequest{RootMessageID: reply.ID, UserID: testUserID})
+
+	assert.ErrorIs(t, err, ErrInvalidThread)
+}
+
+func TestChatService_GetThreadRejectsOtherCursor(t *testing.T) {
+	service, _ := newTestChatService(t)
+	otherThreadCursor := testCursors.Encode(cursor.Cursor{Direction: cursor.Backward, Scope: uuid.New(), ID: uuid.New()})
+
+	_, err := service.GetThread(context.Background(), models.GetThreadRequest{
+		RootMessageID: uuid.New(),
+		UserID:        testUserID,
+		Cursor:        otherThreadCursor,
+	})
+
+	assert.ErrorIs(t, err, cursor.ErrInvalidCursor)
+}
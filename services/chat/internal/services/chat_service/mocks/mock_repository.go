@@ -0,0 +1,1136 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: chat_service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=chat_service.go -destination=mocks/mock_repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	reflect "reflect"
+	time "time"
+
+	cursor "github.com/BeInBloom/grpc-chat/services/chat/internal/cursor"
+	models "github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MocktxManager is a mock of txManager interface.
+type MocktxManager struct {
+	ctrl     *gomock.Controller
+	recorder *MocktxManagerMockRecorder
+	isgomock struct{}
+}
+
+// MocktxManagerMockRecorder is the mock recorder for MocktxManager.
+type MocktxManagerMockRecorder struct {
+	mock *MocktxManager
+}
+
+// NewMocktxManager creates a new mock instance.
+func NewMocktxManager(ctrl *gomock.Controller) *MocktxManager {
+	mock := &MocktxManager{ctrl: ctrl}
+	mock.recorder = &MocktxManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocktxManager) EXPECT() *MocktxManagerMockRecorder {
+	return m.recorder
+}
+
+// Do mocks base method.
+func (m *MocktxManager) Do(ctx context.Context, fn func(context.Context) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Do", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Do indicates an expected call of Do.
+func (mr *MocktxManagerMockRecorder) Do(ctx, fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Do", reflect.TypeOf((*MocktxManager)(nil).Do), ctx, fn)
+}
+
+// MockeventStore is a mock of eventStore interface.
+type MockeventStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockeventStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockeventStoreMockRecorder is the mock recorder for MockeventStore.
+type MockeventStoreMockRecorder struct {
+	mock *MockeventStore
+}
+
+// NewMockeventStore creates a new mock instance.
+func NewMockeventStore(ctrl *gomock.Controller) *MockeventStore {
+	mock := &MockeventStore{ctrl: ctrl}
+	mock.recorder = &MockeventStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockeventStore) EXPECT() *MockeventStoreMockRecorder {
+	return m.recorder
+}
+
+// AppendToChat mocks base method.
+func (m *MockeventStore) AppendToChat(ctx context.Context, chatID uuid.UUID, eventType models.EventType, payload any) ([]models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AppendToChat", ctx, chatID, eventType, payload)
+	ret0, _ := ret[0].([]models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AppendToChat indicates an expected call of AppendToChat.
+func (mr *MockeventStoreMockRecorder) AppendToChat(ctx, chatID, eventType, payload any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AppendToChat", reflect.TypeOf((*MockeventStore)(nil).AppendToChat), ctx, chatID, eventType, payload)
+}
+
+// CountUserEvents mocks base method.
+func (m *MockeventStore) CountUserEvents(ctx context.Context, userID uuid.UUID, after models.EventPosition, limit int32) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserEvents", ctx, userID, after, limit)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserEvents indicates an expected call of CountUserEvents.
+func (mr *MockeventStoreMockRecorder) CountUserEvents(ctx, userID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserEvents", reflect.TypeOf((*MockeventStore)(nil).CountUserEvents), ctx, userID, after, limit)
+}
+
+// GetEventPosition mocks base method.
+func (m *MockeventStore) GetEventPosition(ctx context.Context, userID, eventID uuid.UUID) (models.EventPosition, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEventPosition", ctx, userID, eventID)
+	ret0, _ := ret[0].(models.EventPosition)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEventPosition indicates an expected call of GetEventPosition.
+func (mr *MockeventStoreMockRecorder) GetEventPosition(ctx, userID, eventID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEventPosition", reflect.TypeOf((*MockeventStore)(nil).GetEventPosition), ctx, userID, eventID)
+}
+
+// GetUserEvents mocks base method.
+func (m *MockeventStore) GetUserEvents(ctx context.Context, userID uuid.UUID, after models.EventPosition, limit int32) ([]models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserEvents", ctx, userID, after, limit)
+	ret0, _ := ret[0].([]models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserEvents indicates an expected call of GetUserEvents.
+func (mr *MockeventStoreMockRecorder) GetUserEvents(ctx, userID, after, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserEvents", reflect.TypeOf((*MockeventStore)(nil).GetUserEvents), ctx, userID, after, limit)
+}
+
+// UserHead mocks base method.
+func (m *MockeventStore) UserHead(ctx context.Context, userID uuid.UUID) (models.EventPosition, uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UserHead", ctx, userID)
+	ret0, _ := ret[0].(models.EventPosition)
+	ret1, _ := ret[1].(uuid.UUID)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// UserHead indicates an expected call of UserHead.
+func (mr *MockeventStoreMockRecorder) UserHead(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserHead", reflect.TypeOf((*MockeventStore)(nil).UserHead), ctx, userID)
+}
+
+// MockmessageRepository is a mock of messageRepository interface.
+type MockmessageRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockmessageRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockmessageRepositoryMockRecorder is the mock recorder for MockmessageRepository.
+type MockmessageRepositoryMockRecorder struct {
+	mock *MockmessageRepository
+}
+
+// NewMockmessageRepository creates a new mock instance.
+func NewMockmessageRepository(ctrl *gomock.Controller) *MockmessageRepository {
+	mock := &MockmessageRepository{ctrl: ctrl}
+	mock.recorder = &MockmessageRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmessageRepository) EXPECT() *MockmessageRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddThreadReply mocks base method.
+func (m *MockmessageRepository) AddThreadReply(ctx context.Context, rootID uuid.UUID, repliedAt time.Time) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddThreadReply", ctx, rootID, repliedAt)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddThreadReply indicates an expected call of AddThreadReply.
+func (mr *MockmessageRepositoryMockRecorder) AddThreadReply(ctx, rootID, repliedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddThreadReply", reflect.TypeOf((*MockmessageRepository)(nil).AddThreadReply), ctx, rootID, repliedAt)
+}
+
+// Create mocks base method.
+func (m *MockmessageRepository) Create(ctx context.Context, message models.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockmessageRepositoryMockRecorder) Create(ctx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockmessageRepository)(nil).Create), ctx, message)
+}
+
+// Delete mocks base method.
+func (m *MockmessageRepository) Delete(ctx context.Context, id uuid.UUID, deletedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id, deletedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockmessageRepositoryMockRecorder) Delete(ctx, id, deletedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockmessageRepository)(nil).Delete), ctx, id, deletedAt)
+}
+
+// Get mocks base method.
+func (m *MockmessageRepository) Get(ctx context.Context, id uuid.UUID) (models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockmessageRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockmessageRepository)(nil).Get), ctx, id)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockmessageRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockmessageRepositoryMockRecorder) GetForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockmessageRepository)(nil).GetForUpdate), ctx, id)
+}
+
+// ListBefore mocks base method.
+func (m *MockmessageRepository) ListBefore(ctx context.Context, chatID, beforeID uuid.UUID, limit int32) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBefore", ctx, chatID, beforeID, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBefore indicates an expected call of ListBefore.
+func (mr *MockmessageRepositoryMockRecorder) ListBefore(ctx, chatID, beforeID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBefore", reflect.TypeOf((*MockmessageRepository)(nil).ListBefore), ctx, chatID, beforeID, limit)
+}
+
+// ListThreadBefore mocks base method.
+func (m *MockmessageRepository) ListThreadBefore(ctx context.Context, rootID, beforeID uuid.UUID, limit int32) ([]models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListThreadBefore", ctx, rootID, beforeID, limit)
+	ret0, _ := ret[0].([]models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListThreadBefore indicates an expected call of ListThreadBefore.
+func (mr *MockmessageRepositoryMockRecorder) ListThreadBefore(ctx, rootID, beforeID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListThreadBefore", reflect.TypeOf((*MockmessageRepository)(nil).ListThreadBefore), ctx, rootID, beforeID, limit)
+}
+
+// UpdateContent mocks base method.
+func (m *MockmessageRepository) UpdateContent(ctx context.Context, id uuid.UUID, content models.MessageContent, updatedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateContent", ctx, id, content, updatedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateContent indicates an expected call of UpdateContent.
+func (mr *MockmessageRepositoryMockRecorder) UpdateContent(ctx, id, content, updatedAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateContent", reflect.TypeOf((*MockmessageRepository)(nil).UpdateContent), ctx, id, content, updatedAt)
+}
+
+// MockreactionRepository is a mock of reactionRepository interface.
+type MockreactionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockreactionRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockreactionRepositoryMockRecorder is the mock recorder for MockreactionRepository.
+type MockreactionRepositoryMockRecorder struct {
+	mock *MockreactionRepository
+}
+
+// NewMockreactionRepository creates a new mock instance.
+func NewMockreactionRepository(ctrl *gomock.Controller) *MockreactionRepository {
+	mock := &MockreactionRepository{ctrl: ctrl}
+	mock.recorder = &MockreactionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockreactionRepository) EXPECT() *MockreactionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockreactionRepository) Add(ctx context.Context, reaction models.Reaction) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Add", ctx, reaction)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockreactionRepositoryMockRecorder) Add(ctx, reaction any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockreactionRepository)(nil).Add), ctx, reaction)
+}
+
+// Count mocks base method.
+func (m *MockreactionRepository) Count(ctx context.Context, messageID uuid.UUID, emoji string) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Count", ctx, messageID, emoji)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Count indicates an expected call of Count.
+func (mr *MockreactionRepositoryMockRecorder) Count(ctx, messageID, emoji any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockreactionRepository)(nil).Count), ctx, messageID, emoji)
+}
+
+// CountEmoji mocks base method.
+func (m *MockreactionRepository) CountEmoji(ctx context.Context, messageID uuid.UUID) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountEmoji", ctx, messageID)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountEmoji indicates an expected call of CountEmoji.
+func (mr *MockreactionRepositoryMockRecorder) CountEmoji(ctx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountEmoji", reflect.TypeOf((*MockreactionRepository)(nil).CountEmoji), ctx, messageID)
+}
+
+// Remove mocks base method.
+func (m *MockreactionRepository) Remove(ctx context.Context, messageID, userID uuid.UUID, emoji string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, messageID, userID, emoji)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockreactionRepositoryMockRecorder) Remove(ctx, messageID, userID, emoji any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockreactionRepository)(nil).Remove), ctx, messageID, userID, emoji)
+}
+
+// Summaries mocks base method.
+func (m *MockreactionRepository) Summaries(ctx context.Context, viewerID uuid.UUID, messageIDs []uuid.UUID) (map[uuid.UUID][]models.ReactionSummary, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Summaries", ctx, viewerID, messageIDs)
+	ret0, _ := ret[0].(map[uuid.UUID][]models.ReactionSummary)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summaries indicates an expected call of Summaries.
+func (mr *MockreactionRepositoryMockRecorder) Summaries(ctx, viewerID, messageIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summaries", reflect.TypeOf((*MockreactionRepository)(nil).Summaries), ctx, viewerID, messageIDs)
+}
+
+// MockattachmentRepository is a mock of attachmentRepository interface.
+type MockattachmentRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockattachmentRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockattachmentRepositoryMockRecorder is the mock recorder for MockattachmentRepository.
+type MockattachmentRepositoryMockRecorder struct {
+	mock *MockattachmentRepository
+}
+
+// NewMockattachmentRepository creates a new mock instance.
+func NewMockattachmentRepository(ctrl *gomock.Controller) *MockattachmentRepository {
+	mock := &MockattachmentRepository{ctrl: ctrl}
+	mock.recorder = &MockattachmentRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockattachmentRepository) EXPECT() *MockattachmentRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockattachmentRepository) Create(ctx context.Context, attachment models.Attachment, quota int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, attachment, quota)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockattachmentRepositoryMockRecorder) Create(ctx, attachment, quota any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockattachmentRepository)(nil).Create), ctx, attachment, quota)
+}
+
+// Get mocks base method.
+func (m *MockattachmentRepository) Get(ctx context.Context, id uuid.UUID) (models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockattachmentRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockattachmentRepository)(nil).Get), ctx, id)
+}
+
+// GetForShare mocks base method.
+func (m *MockattachmentRepository) GetForShare(ctx context.Context, id uuid.UUID) (models.Attachment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForShare", ctx, id)
+	ret0, _ := ret[0].(models.Attachment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForShare indicates an expected call of GetForShare.
+func (mr *MockattachmentRepositoryMockRecorder) GetForShare(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForShare", reflect.TypeOf((*MockattachmentRepository)(nil).GetForShare), ctx, id)
+}
+
+// IsReferenced mocks base method.
+func (m *MockattachmentRepository) IsReferenced(ctx context.Context, id uuid.UUID) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsReferenced", ctx, id)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsReferenced indicates an expected call of IsReferenced.
+func (mr *MockattachmentRepositoryMockRecorder) IsReferenced(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReferenced", reflect.TypeOf((*MockattachmentRepository)(nil).IsReferenced), ctx, id)
+}
+
+// Usage mocks base method.
+func (m *MockattachmentRepository) Usage(ctx context.Context, userID uuid.UUID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Usage", ctx, userID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Usage indicates an expected call of Usage.
+func (mr *MockattachmentRepositoryMockRecorder) Usage(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Usage", reflect.TypeOf((*MockattachmentRepository)(nil).Usage), ctx, userID)
+}
+
+// MockblobStore is a mock of blobStore interface.
+type MockblobStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockblobStoreMockRecorder
+	isgomock struct{}
+}
+
+// MockblobStoreMockRecorder is the mock recorder for MockblobStore.
+type MockblobStoreMockRecorder struct {
+	mock *MockblobStore
+}
+
+// NewMockblobStore creates a new mock instance.
+func NewMockblobStore(ctrl *gomock.Controller) *MockblobStore {
+	mock := &MockblobStore{ctrl: ctrl}
+	mock.recorder = &MockblobStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockblobStore) EXPECT() *MockblobStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockblobStore) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockblobStoreMockRecorder) Delete(ctx, key any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockblobStore)(nil).Delete), ctx, key)
+}
+
+// Open mocks base method.
+func (m *MockblobStore) Open(ctx context.Context, key string, offset, length int64) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Open", ctx, key, offset, length)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Open indicates an expected call of Open.
+func (mr *MockblobStoreMockRecorder) Open(ctx, key, offset, length any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Open", reflect.TypeOf((*MockblobStore)(nil).Open), ctx, key, offset, length)
+}
+
+// Put mocks base method.
+func (m *MockblobStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Put", ctx, key, r)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Put indicates an expected call of Put.
+func (mr *MockblobStoreMockRecorder) Put(ctx, key, r any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Put", reflect.TypeOf((*MockblobStore)(nil).Put), ctx, key, r)
+}
+
+// MockchatRepository is a mock of chatRepository interface.
+type MockchatRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockchatRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockchatRepositoryMockRecorder is the mock recorder for MockchatRepository.
+type MockchatRepositoryMockRecorder struct {
+	mock *MockchatRepository
+}
+
+// NewMockchatRepository creates a new mock instance.
+func NewMockchatRepository(ctrl *gomock.Controller) *MockchatRepository {
+	mock := &MockchatRepository{ctrl: ctrl}
+	mock.recorder = &MockchatRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockchatRepository) EXPECT() *MockchatRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockchatRepository) Create(ctx context.Context, chat models.Chat) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, chat)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockchatRepositoryMockRecorder) Create(ctx, chat any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockchatRepository)(nil).Create), ctx, chat)
+}
+
+// Get mocks base method.
+func (m *MockchatRepository) Get(ctx context.Context, id uuid.UUID) (models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, id)
+	ret0, _ := ret[0].(models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockchatRepositoryMockRecorder) Get(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockchatRepository)(nil).Get), ctx, id)
+}
+
+// GetDirect mocks base method.
+func (m *MockchatRepository) GetDirect(ctx context.Context, a, b uuid.UUID) (models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDirect", ctx, a, b)
+	ret0, _ := ret[0].(models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDirect indicates an expected call of GetDirect.
+func (mr *MockchatRepositoryMockRecorder) GetDirect(ctx, a, b any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDirect", reflect.TypeOf((*MockchatRepository)(nil).GetDirect), ctx, a, b)
+}
+
+// GetForUpdate mocks base method.
+func (m *MockchatRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (models.Chat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetForUpdate", ctx, id)
+	ret0, _ := ret[0].(models.Chat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetForUpdate indicates an expected call of GetForUpdate.
+func (mr *MockchatRepositoryMockRecorder) GetForUpdate(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetForUpdate", reflect.TypeOf((*MockchatRepository)(nil).GetForUpdate), ctx, id)
+}
+
+// MockuserDirectory is a mock of userDirectory interface.
+type MockuserDirectory struct {
+	ctrl     *gomock.Controller
+	recorder *MockuserDirectoryMockRecorder
+	isgomock struct{}
+}
+
+// MockuserDirectoryMockRecorder is the mock recorder for MockuserDirectory.
+type MockuserDirectoryMockRecorder struct {
+	mock *MockuserDirectory
+}
+
+// NewMockuserDirectory creates a new mock instance.
+func NewMockuserDirectory(ctrl *gomock.Controller) *MockuserDirectory {
+	mock := &MockuserDirectory{ctrl: ctrl}
+	mock.recorder = &MockuserDirectoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockuserDirectory) EXPECT() *MockuserDirectoryMockRecorder {
+	return m.recorder
+}
+
+// MissingUsers mocks base method.
+func (m *MockuserDirectory) MissingUsers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MissingUsers", ctx, ids)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MissingUsers indicates an expected call of MissingUsers.
+func (mr *MockuserDirectoryMockRecorder) MissingUsers(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MissingUsers", reflect.TypeOf((*MockuserDirectory)(nil).MissingUsers), ctx, ids)
+}
+
+// MockmemberRepository is a mock of memberRepository interface.
+type MockmemberRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockmemberRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockmemberRepositoryMockRecorder is the mock recorder for MockmemberRepository.
+type MockmemberRepositoryMockRecorder struct {
+	mock *MockmemberRepository
+}
+
+// NewMockmemberRepository creates a new mock instance.
+func NewMockmemberRepository(ctrl *gomock.Controller) *MockmemberRepository {
+	mock := &MockmemberRepository{ctrl: ctrl}
+	mock.recorder = &MockmemberRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockmemberRepository) EXPECT() *MockmemberRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Add mocks base method.
+func (m *MockmemberRepository) Add(ctx context.Context, members ...models.ChatMember) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range members {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Add", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Add indicates an expected call of Add.
+func (mr *MockmemberRepositoryMockRecorder) Add(ctx any, members ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, members...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Add", reflect.TypeOf((*MockmemberRepository)(nil).Add), varargs...)
+}
+
+// AdvanceReadCursor mocks base method.
+func (m *MockmemberRepository) AdvanceReadCursor(ctx context.Context, chatID, userID, messageID uuid.UUID, readAt time.Time) (models.ReadState, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdvanceReadCursor", ctx, chatID, userID, messageID, readAt)
+	ret0, _ := ret[0].(models.ReadState)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// AdvanceReadCursor indicates an expected call of AdvanceReadCursor.
+func (mr *MockmemberRepositoryMockRecorder) AdvanceReadCursor(ctx, chatID, userID, messageID, readAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdvanceReadCursor", reflect.TypeOf((*MockmemberRepository)(nil).AdvanceReadCursor), ctx, chatID, userID, messageID, readAt)
+}
+
+// Get mocks base method.
+func (m *MockmemberRepository) Get(ctx context.Context, chatID, userID uuid.UUID) (models.ChatMember, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, chatID, userID)
+	ret0, _ := ret[0].(models.ChatMember)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockmemberRepositoryMockRecorder) Get(ctx, chatID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockmemberRepository)(nil).Get), ctx, chatID, userID)
+}
+
+// ListReadStates mocks base method.
+func (m *MockmemberRepository) ListReadStates(ctx context.Context, chatID uuid.UUID) ([]models.ReadState, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReadStates", ctx, chatID)
+	ret0, _ := ret[0].([]models.ReadState)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListReadStates indicates an expected call of ListReadStates.
+func (mr *MockmemberRepositoryMockRecorder) ListReadStates(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReadStates", reflect.TypeOf((*MockmemberRepository)(nil).ListReadStates), ctx, chatID)
+}
+
+// ListUserIDs mocks base method.
+func (m *MockmemberRepository) ListUserIDs(ctx context.Context, chatID uuid.UUID) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserIDs", ctx, chatID)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserIDs indicates an expected call of ListUserIDs.
+func (mr *MockmemberRepositoryMockRecorder) ListUserIDs(ctx, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserIDs", reflect.TypeOf((*MockmemberRepository)(nil).ListUserIDs), ctx, chatID)
+}
+
+// Remove mocks base method.
+func (m *MockmemberRepository) Remove(ctx context.Context, chatID, userID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, chatID, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockmemberRepositoryMockRecorder) Remove(ctx, chatID, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockmemberRepository)(nil).Remove), ctx, chatID, userID)
+}
+
+// UpdateRole mocks base method.
+func (m *MockmemberRepository) UpdateRole(ctx context.Context, chatID, userID uuid.UUID, role models.MemberRole) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRole", ctx, chatID, userID, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateRole indicates an expected call of UpdateRole.
+func (mr *MockmemberRepositoryMockRecorder) UpdateRole(ctx, chatID, userID, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRole", reflect.TypeOf((*MockmemberRepository)(nil).UpdateRole), ctx, chatID, userID, role)
+}
+
+// MockidempotencyRepository is a mock of idempotencyRepository interface.
+type MockidempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockidempotencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockidempotencyRepositoryMockRecorder is the mock recorder for MockidempotencyRepository.
+type MockidempotencyRepositoryMockRecorder struct {
+	mock *MockidempotencyRepository
+}
+
+// NewMockidempotencyRepository creates a new mock instance.
+func NewMockidempotencyRepository(ctrl *gomock.Controller) *MockidempotencyRepository {
+	mock := &MockidempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockidempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockidempotencyRepository) EXPECT() *MockidempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockidempotencyRepository) Claim(ctx context.Context, senderID, chatID uuid.UUID, key string, result models.IdempotencyResult, notBefore time.Time) (models.IdempotencyResult, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, senderID, chatID, key, result, notBefore)
+	ret0, _ := ret[0].(models.IdempotencyResult)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockidempotencyRepositoryMockRecorder) Claim(ctx, senderID, chatID, key, result, notBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockidempotencyRepository)(nil).Claim), ctx, senderID, chatID, key, result, notBefore)
+}
+
+// MockcursorCodec is a mock of cursorCodec interface.
+type MockcursorCodec struct {
+	ctrl     *gomock.Controller
+	recorder *MockcursorCodecMockRecorder
+	isgomock struct{}
+}
+
+// MockcursorCodecMockRecorder is the mock recorder for MockcursorCodec.
+type MockcursorCodecMockRecorder struct {
+	mock *MockcursorCodec
+}
+
+// NewMockcursorCodec creates a new mock instance.
+func NewMockcursorCodec(ctrl *gomock.Controller) *MockcursorCodec {
+	mock := &MockcursorCodec{ctrl: ctrl}
+	mock.recorder = &MockcursorCodecMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockcursorCodec) EXPECT() *MockcursorCodecMockRecorder {
+	return m.recorder
+}
+
+// Decode mocks base method.
+func (m *MockcursorCodec) Decode(encoded string, scope uuid.UUID) (cursor.Cursor, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decode", encoded, scope)
+	ret0, _ := ret[0].(cursor.Cursor)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Decode indicates an expected call of Decode.
+func (mr *MockcursorCodecMockRecorder) Decode(encoded, scope any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decode", reflect.TypeOf((*MockcursorCodec)(nil).Decode), encoded, scope)
+}
+
+// Encode mocks base method.
+func (m *MockcursorCodec) Encode(cur cursor.Cursor) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Encode", cur)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Encode indicates an expected call of Encode.
+func (mr *MockcursorCodecMockRecorder) Encode(cur any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Encode", reflect.TypeOf((*MockcursorCodec)(nil).Encode), cur)
+}
+
+// Mocksnapshotter is a mock of snapshotter interface.
+type Mocksnapshotter struct {
+	ctrl     *gomock.Controller
+	recorder *MocksnapshotterMockRecorder
+	isgomock struct{}
+}
+
+// MocksnapshotterMockRecorder is the mock recorder for Mocksnapshotter.
+type MocksnapshotterMockRecorder struct {
+	mock *Mocksnapshotter
+}
+
+// NewMocksnapshotter creates a new mock instance.
+func NewMocksnapshotter(ctrl *gomock.Controller) *Mocksnapshotter {
+	mock := &Mocksnapshotter{ctrl: ctrl}
+	mock.recorder = &MocksnapshotterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *Mocksnapshotter) EXPECT() *MocksnapshotterMockRecorder {
+	return m.recorder
+}
+
+// Latest mocks base method.
+func (m *Mocksnapshotter) Latest(ctx context.Context, userID uuid.UUID) (models.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Latest", ctx, userID)
+	ret0, _ := ret[0].(models.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Latest indicates an expected call of Latest.
+func (mr *MocksnapshotterMockRecorder) Latest(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Latest", reflect.TypeOf((*Mocksnapshotter)(nil).Latest), ctx, userID)
+}
+
+// MockretentionHorizon is a mock of retentionHorizon interface.
+type MockretentionHorizon struct {
+	ctrl     *gomock.Controller
+	recorder *MockretentionHorizonMockRecorder
+	isgomock struct{}
+}
+
+// MockretentionHorizonMockRecorder is the mock recorder for MockretentionHorizon.
+type MockretentionHorizonMockRecorder struct {
+	mock *MockretentionHorizon
+}
+
+// NewMockretentionHorizon creates a new mock instance.
+func NewMockretentionHorizon(ctrl *gomock.Controller) *MockretentionHorizon {
+	mock := &MockretentionHorizon{ctrl: ctrl}
+	mock.recorder = &MockretentionHorizonMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockretentionHorizon) EXPECT() *MockretentionHorizonMockRecorder {
+	return m.recorder
+}
+
+// OldestRetainedEventID mocks base method.
+func (m *MockretentionHorizon) OldestRetainedEventID() uuid.UUID {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OldestRetainedEventID")
+	ret0, _ := ret[0].(uuid.UUID)
+	return ret0
+}
+
+// OldestRetainedEventID indicates an expected call of OldestRetainedEventID.
+func (mr *MockretentionHorizonMockRecorder) OldestRetainedEventID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OldestRetainedEventID", reflect.TypeOf((*MockretentionHorizon)(nil).OldestRetainedEventID))
+}
+
+// MockeventPublisher is a mock of eventPublisher interface.
+type MockeventPublisher struct {
+	ctrl     *gomock.Controller
+	recorder *MockeventPublisherMockRecorder
+	isgomock struct{}
+}
+
+// MockeventPublisherMockRecorder is the mock recorder for MockeventPublisher.
+type MockeventPublisherMockRecorder struct {
+	mock *MockeventPublisher
+}
+
+// NewMockeventPublisher creates a new mock instance.
+func NewMockeventPublisher(ctrl *gomock.Controller) *MockeventPublisher {
+	mock := &MockeventPublisher{ctrl: ctrl}
+	mock.recorder = &MockeventPublisherMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockeventPublisher) EXPECT() *MockeventPublisherMockRecorder {
+	return m.recorder
+}
+
+// Publish mocks base method.
+func (m *MockeventPublisher) Publish(ctx context.Context, events ...models.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Publish", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockeventPublisherMockRecorder) Publish(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockeventPublisher)(nil).Publish), varargs...)
+}
+
+// Subscribe mocks base method.
+func (m *MockeventPublisher) Subscribe(ctx context.Context, handle models.SubscriptionHandle) (<-chan models.Event, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", ctx, handle)
+	ret0, _ := ret[0].(<-chan models.Event)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockeventPublisherMockRecorder) Subscribe(ctx, handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockeventPublisher)(nil).Subscribe), ctx, handle)
+}
+
+// Unsubscribe mocks base method.
+func (m *MockeventPublisher) Unsubscribe(handle models.SubscriptionHandle) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsubscribe", handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsubscribe indicates an expected call of Unsubscribe.
+func (mr *MockeventPublisherMockRecorder) Unsubscribe(handle any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsubscribe", reflect.TypeOf((*MockeventPublisher)(nil).Unsubscribe), handle)
+}
+
+// MockreadModelRepos is a mock of readModelRepos interface.
+type MockreadModelRepos struct {
+	ctrl     *gomock.Controller
+	recorder *MockreadModelReposMockRecorder
+	isgomock struct{}
+}
+
+// MockreadModelReposMockRecorder is the mock recorder for MockreadModelRepos.
+type MockreadModelReposMockRecorder struct {
+	mock *MockreadModelRepos
+}
+
+// NewMockreadModelRepos creates a new mock instance.
+func NewMockreadModelRepos(ctrl *gomock.Controller) *MockreadModelRepos {
+	mock := &MockreadModelRepos{ctrl: ctrl}
+	mock.recorder = &MockreadModelReposMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockreadModelRepos) EXPECT() *MockreadModelReposMockRecorder {
+	return m.recorder
+}
+
+// Apply mocks base method.
+func (m *MockreadModelRepos) Apply(ctx context.Context, events ...models.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "Apply", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Apply indicates an expected call of Apply.
+func (mr *MockreadModelReposMockRecorder) Apply(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Apply", reflect.TypeOf((*MockreadModelRepos)(nil).Apply), varargs...)
+}
+
+// ListChats mocks base method.
+func (m *MockreadModelRepos) ListChats(ctx context.Context, userID uuid.UUID, activeAfter, beforeActivity time.Time, beforeChatID uuid.UUID, limit int32) ([]models.ChatPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChats", ctx, userID, activeAfter, beforeActivity, beforeChatID, limit)
+	ret0, _ := ret[0].([]models.ChatPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChats indicates an expected call of ListChats.
+func (mr *MockreadModelReposMockRecorder) ListChats(ctx, userID, activeAfter, beforeActivity, beforeChatID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChats", reflect.TypeOf((*MockreadModelRepos)(nil).ListChats), ctx, userID, activeAfter, beforeActivity, beforeChatID, limit)
+}
+
+// RemoveChat mocks base method.
+func (m *MockreadModelRepos) RemoveChat(ctx context.Context, userID, chatID uuid.UUID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveChat", ctx, userID, chatID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveChat indicates an expected call of RemoveChat.
+func (mr *MockreadModelReposMockRecorder) RemoveChat(ctx, userID, chatID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveChat", reflect.TypeOf((*MockreadModelRepos)(nil).RemoveChat), ctx, userID, chatID)
+}
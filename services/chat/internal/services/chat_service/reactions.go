@@ -0,0 +1,128 @@
+package chatservice
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+const maxReactionEmoji = 20
+
+// AddReaction adds the caller's reaction with the emoji to the message and
+// sends every member a REACTION_UPDATED. Reacting again with the same emoji
+// changes nothing. A message has at most maxReactionEmoji distinct emoji.
+func (s *ChatService) AddReaction(
+	ctx context.Context,
+	req models.AddReactionRequest,
+) (models.AddReactionResponse, error) {
+	now := s.now().UTC().Truncate(time.Microsecond)
+
+	summary, err := s.updateReaction(ctx, now, req.MessageID, req.UserID, req.Emoji, true, func(ctx context.Context) (bool, error) {
+		added, err := s.reactions.Add(ctx, models.Reaction{
+			MessageID: req.MessageID,
+			UserID:    req.UserID,
+			Emoji:     req.Emoji,
+			CreatedAt: now,
+		})
+		if err != nil || !added {
+			return false, err
+		}
+
+		distinct, err := s.reactions.CountEmoji(ctx, req.MessageID)
+		if err != nil {
+			return false, err
+		}
+		if distinct > maxReactionEmoji {
+			return false, fmt.Errorf("%w: a message has at most %d distinct reactions", ErrTooManyReactions, maxReactionEmoji)
+		}
+
+		return true, nil
+	})
+	if err != nil {
+		return models.AddReactionResponse{}, err
+	}
+
+	return models.AddReactionResponse{Reaction: summary}, nil
+}
+
+// RemoveReaction removes the caller's reaction with the emoji from the
+// message and sends every member a REACTION_UPDATED. Removing a reaction
+// the caller does not have changes nothing.
+func (s *ChatService) RemoveReaction(
+	ctx context.Context,
+	req models.RemoveReactionRequest,
+) (models.RemoveReactionResponse, error) {
+	now := s.now().UTC().Truncate(time.Microsecond)
+
+	summary, err := s.updateReaction(ctx, now, req.MessageID, req.UserID, req.Emoji, false, func(ctx context.Context) (bool, error) {
+		return s.reactions.Remove(ctx, req.MessageID, req.UserID, req.Emoji)
+	})
+	if err != nil {
+		return models.RemoveReactionResponse{}, err
+	}
+
+	return models.RemoveReactionResponse{Reaction: summary}, nil
+}
+
+// updateReaction applies change to a message the user can see and, when it
+// changed anything, appends the REACTION_UPDATED event in the same
+// transaction. The message stays locked meanwhile, so concurrent reactions
+// cannot both pass the emoji limit.
+func (s *ChatService) updateReaction(
+	ctx context.Context,
+	now time.Time,
+	messageID, userID uuid.UUID,
+	emoji string,
+	added bool,
+	change func(ctx context.Context) (bool, error),
+) (models.ReactionSummary, error) {
+	var (
+		summary = models.ReactionSummary{Emoji: emoji, ReactedByMe: added}
+		events  []models.Event
+	)
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		message, err := s.messages.GetForUpdate(ctx, messageID)
+		if err != nil {
+			return err
+		}
+		if message.DeletedAt != nil {
+			return repository.ErrMessageNotFound
+		}
+		if err := s.requireMember(ctx, message.ChatID, userID); err != nil {
+			return err
+		}
+
+		changed, err := change(ctx)
+		if err != nil {
+			return err
+		}
+
+		summary.Count, err = s.reactions.Count(ctx, messageID, emoji)
+		if err != nil || !changed {
+			return err
+		}
+
+		events, err = s.appendToChat(ctx, message.ChatID, models.EventTypeReactionUpdated, models.ReactionUpdatedPayload{
+			MessageID: messageID,
+			ChatID:    message.ChatID,
+			UserID:    userID,
+			Emoji:     emoji,
+			Added:     added,
+			Count:     summary.Count,
+			UpdatedAt: now,
+		})
+		return err
+	})
+	if err != nil {
+		return models.ReactionSummary{}, err
+	}
+
+	s.publish(ctx, events)
+
+	return summary, nil
+}
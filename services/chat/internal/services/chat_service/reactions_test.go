@@ -0,0 +1,139 @@
+package chatservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+func TestChatService_AddReaction(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+
+	m.messages.EXPECT().GetForUpdate(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{}, nil)
+	m.reactions.EXPECT().
+		Add(ctx, models.Reaction{MessageID: message.ID, UserID: testMemberID, Emoji: "👍", CreatedAt: testNow}).
+		Return(true, nil)
+	m.reactions.EXPECT().CountEmoji(ctx, message.ID).Return(int32(1), nil)
+	m.reactions.EXPECT().Count(ctx, message.ID, "👍").Return(int32(2), nil)
+
+	events := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeReactionUpdated}}
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeReactionUpdated, models.ReactionUpdatedPayload{
+			MessageID: message.ID,
+			ChatID:    testChatID,
+			UserID:    testMemberID,
+			Emoji:     "👍",
+			Added:     true,
+			Count:     2,
+			UpdatedAt: testNow,
+		}).
+		Return(events, nil)
+	m.readModel.EXPECT().Apply(ctx, events[0]).Return(nil)
+	m.publisher.EXPECT().Publish(ctx, events[0]).Return(nil)
+
+	resp, err := service.AddReaction(ctx, models.AddReactionRequest{MessageID: message.ID, UserID: testMemberID, Emoji: "👍"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ReactionSummary{Emoji: "👍", Count: 2, ReactedByMe: true}, resp.Reaction)
+}
+
+func TestChatService_AddReactionTwice(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+
+	m.messages.EXPECT().GetForUpdate(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{}, nil)
+	m.reactions.EXPECT().Add(ctx, gomock.Any()).Return(false, nil)
+	m.reactions.EXPECT().Count(ctx, message.ID, "👍").Return(int32(1), nil)
+
+	resp, err := service.AddReaction(ctx, models.AddReactionRequest{MessageID: message.ID, UserID: testMemberID, Emoji: "👍"})
+
+	require.NoError(t, err, "reacting again changes nothing and sends no event")
+	assert.Equal(t, models.ReactionSummary{Emoji: "👍", Count: 1, ReactedByMe: true}, resp.Reaction)
+}
+
+func TestChatService_AddReactionLimit(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+
+	m.messages.EXPECT().GetForUpdate(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{}, nil)
+	m.reactions.EXPECT().Add(ctx, gomock.Any()).Return(true, nil)
+	m.reactions.EXPECT().CountEmoji(ctx, message.ID).Return(int32(maxReactionEmoji+1), nil)
+
+	_, err := service.AddReaction(ctx, models.AddReactionRequest{MessageID: message.ID, UserID: testMemberID, Emoji: "🦄"})
+
+	assert.ErrorIs(t, err, ErrTooManyReactions)
+}
+
+func TestChatService_AddReactionRejects(t *testing.T) {
+	deleted := testSentMessage()
+	deleted.DeletedAt = &testNow
+
+	tests := map[string]struct {
+		message models.Message
+		member  error
+		err     error
+	}{
+		"deleted message": {message: deleted, err: repository.ErrMessageNotFound},
+		"not a member":    {message: testSentMessage(), member: repository.ErrMemberNotFound, err: ErrNotMember},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			service, m := newTestChatService(t)
+			ctx := context.Background()
+
+			m.messages.EXPECT().GetForUpdate(ctx, tt.message.ID).Return(tt.message, nil)
+			if tt.message.DeletedAt == nil {
+				m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{}, tt.member)
+			}
+
+			_, err := service.AddReaction(ctx, models.AddReactionRequest{MessageID: tt.message.ID, UserID: testMemberID, Emoji: "👍"})
+
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestChatService_RemoveReaction(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+
+	m.messages.EXPECT().GetForUpdate(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{}, nil)
+	m.reactions.EXPECT().Remove(ctx, message.ID, testMemberID, "👍").Return(true, nil)
+	m.reactions.EXPECT().Count(ctx, message.ID, "👍").Return(int32(0), nil)
+
+	events := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeReactionUpdated}}
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeReactionUpdated, models.ReactionUpdatedPayload{
+			MessageID: message.ID,
+			ChatID:    testChatID,
+			UserID:    testMemberID,
+			Emoji:     "👍",
+			Count:     0,
+			UpdatedAt: testNow,
+		}).
+		Return(events, nil)
+	m.readModel.EXPECT().Apply(ctx, events[0]).Return(nil)
+	m.publisher.EXPECT().Publish(ctx, events[0]).Return(nil)
+
+	resp, err := service.RemoveReaction(ctx, models.RemoveReactionRequest{MessageID: message.ID, UserID: testMemberID, Emoji: "👍"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.ReactionSummary{Emoji: "👍"}, resp.Reaction)
+}
@@ -0,0 +1,73 @@
+package chatservice
+
+import (
+	"context"
+	"time"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+// MarkRead moves the caller's read cursor in the chat up to the message.
+// The cursor never moves backwards: marking an older message returns the
+// current state without an event. When the cursor moves, every member gets
+// a READ_RECEIPT, the reader too so that their other devices catch up.
+func (s *ChatService) MarkRead(ctx context.Context, req models.MarkReadRequest) (models.MarkReadResponse, error) {
+	now := s.now().UTC().Truncate(time.Microsecond)
+
+	var (
+		state  models.ReadState
+		events []models.Event
+	)
+	err := s.tx.Do(ctx, func(ctx context.Context) error {
+		if err := s.requireMember(ctx, req.ChatID, req.UserID); err != nil {
+			return err
+		}
+
+		message, err := s.messages.Get(ctx, req.MessageID)
+		if err != nil {
+			return err
+		}
+		if message.ChatID != req.ChatID {
+			return repository.ErrMessageNotFound
+		}
+
+		var advanced bool
+		state, advanced, err = s.members.AdvanceReadCursor(ctx, req.ChatID, req.UserID, req.MessageID, now)
+		if err != nil || !advanced {
+			return err
+		}
+
+		events, err = s.appendToChat(ctx, req.ChatID, models.EventTypeReadReceipt, models.ReadReceiptPayload{
+			ChatID:    req.ChatID,
+			UserID:    req.UserID,
+			MessageID: req.MessageID,
+			ReadAt:    now,
+		})
+		return err
+	})
+	if err != nil {
+		return models.MarkReadResponse{}, err
+	}
+
+	s.publish(ctx, events)
+
+	return models.MarkReadResponse{State: state}, nil
+}
+
+// GetReadState returns the read cursor of every member of the chat.
+func (s *ChatService) GetReadState(
+	ctx context.Context,
+	req models.GetReadStateRequest,
+) (models.GetReadStateResponse, error) {
+	if err := s.requireMember(ctx, req.ChatID, req.UserID); err != nil {
+		return models.GetReadStateResponse{}, err
+	}
+
+	states, err := s.members.ListReadStates(ctx, req.ChatID)
+	if err != nil {
+		return models.GetReadStateResponse{}, err
+	}
+
+	return models.GetReadStateResponse{Members: states}, nil
+}
@@ -0,0 +1,99 @@
+package chatservice
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/repository"
+)
+
+func TestChatService_MarkRead(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+	state := models.ReadState{UserID: testMemberID, LastReadMessageID: message.ID, ReadAt: testNow}
+
+	m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{}, nil)
+	m.messages.EXPECT().Get(ctx, message.ID).Return(message, nil)
+	m.members.EXPECT().AdvanceReadCursor(ctx, testChatID, testMemberID, message.ID, testNow).Return(state, true, nil)
+
+	events := []models.Event{{ID: uuid.Must(uuid.NewV7()), Type: models.EventTypeReadReceipt}}
+	m.events.EXPECT().
+		AppendToChat(ctx, testChatID, models.EventTypeReadReceipt, models.ReadReceiptPayload{
+			ChatID:    testChatID,
+			UserID:    testMemberID,
+			MessageID: message.ID,
+			ReadAt:    testNow,
+		}).
+		Return(events, nil)
+	m.readModel.EXPECT().Apply(ctx, events[0]).Return(nil)
+	m.publisher.EXPECT().Publish(ctx, events[0]).Return(nil)
+
+	resp, err := service.MarkRead(ctx, models.MarkReadRequest{ChatID: testChatID, UserID: testMemberID, MessageID: message.ID})
+
+	require.NoError(t, err)
+	assert.Equal(t, state, resp.State)
+}
+
+func TestChatService_MarkReadOlderMessage(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	older := testSentMessage()
+	current := models.ReadState{UserID: testMemberID, LastReadMessageID: uuid.Must(uuid.NewV7()), ReadAt: testNow}
+
+	m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{}, nil)
+	m.messages.EXPECT().Get(ctx, older.ID).Return(older, nil)
+	m.members.EXPECT().AdvanceReadCursor(ctx, testChatID, testMemberID, older.ID, testNow).Return(current, false, nil)
+
+	resp, err := service.MarkRead(ctx, models.MarkReadRequest{ChatID: testChatID, UserID: testMemberID, MessageID: older.ID})
+
+	require.NoError(t, err)
+	assert.Equal(t, current, resp.State, "the cursor does not move backwards and no receipt is sent")
+}
+
+func TestChatService_MarkReadMessageOfOtherChat(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	message := testSentMessage()
+	message.ChatID = uuid.New()
+
+	m.members.EXPECT().Get(ctx, testChatID, testMemberID).Return(models.ChatMember{}, nil)
+	m.messages.EXPECT().Get(ctx, message.ID).Return(message, nil)
+
+	_, err := service.MarkRead(ctx, models.MarkReadRequest{ChatID: testChatID, UserID: testMemberID, MessageID: message.ID})
+
+	assert.ErrorIs(t, err, repository.ErrMessageNotFound)
+}
+
+func TestChatService_GetReadState(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	states := []models.ReadState{
+		{UserID: testUserID, LastReadMessageID: uuid.Must(uuid.NewV7()), ReadAt: testNow},
+		{UserID: testMemberID},
+	}
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, nil)
+	m.members.EXPECT().ListReadStates(ctx, testChatID).Return(states, nil)
+
+	resp, err := service.GetReadState(ctx, models.GetReadStateRequest{ChatID: testChatID, UserID: testUserID})
+
+	require.NoError(t, err)
+	assert.Equal(t, states, resp.Members)
+}
+
+func TestChatService_GetReadStateNotMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.members.EXPECT().Get(ctx, testChatID, testUserID).Return(models.ChatMember{}, repository.ErrMemberNotFound)
+
+	_, err := service.GetReadState(ctx, models.GetReadStateRequest{ChatID: testChatID, UserID: testUserID})
+
+	assert.ErrorIs(t, err, ErrNotMember)
+}
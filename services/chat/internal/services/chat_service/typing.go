@@ -0,0 +1,185 @@
+package chatservice
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+// SetTyping tells the other members of the chat that the user started or
+// stopped typing. Typing events are ephemeral: they go to the members that
+// are online and are never stored. Repeated starts within the throttle
+// interval are not sent again, and a user who stays silent for the expiry
+// interval is reported as stopped.
+func (s *ChatService) SetTyping(ctx context.Context, req models.SetTypingRequest) error {
+	memberIDs, err := s.members.ListUserIDs(ctx, req.ChatID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(memberIDs, req.UserID) {
+		return ErrNotMember
+	}
+
+	key := typingKey{chatID: req.ChatID, userID: req.UserID}
+	if s.typing.throttled(key, req.IsTyping, s.now()) {
+		return nil
+	}
+
+	recipients := make([]uuid.UUID, 0, len(memberIDs)-1)
+	for _, id := range memberIDs {
+		if id != req.UserID {
+			recipients = append(recipients, id)
+		}
+	}
+	if req.IsTyping {
+		s.typing.start(key, recipients, s.now())
+	} else {
+		s.typing.stop(key)
+	}
+
+	s.publishTyping(ctx, key, req.IsTyping, recipients)
+
+	return nil
+}
+
+// publishTyping sends a typing event to every recipient. A lost typing
+// event is not worth retrying, so a failure is only logged. The event ids
+// only tag the events inside the service: the stream sends typing events
+// without one, as they are not in the log to resume from.
+func (s *ChatService) publishTyping(ctx context.Context, key typingKey, isTyping bool, recipients []uuid.UUID) {
+	if len(recipients) == 0 {
+		return
+	}
+
+	now := s.now().UTC()
+	payload := models.TypingIndicatorPayload{ChatID: key.chatID, UserID: key.userID, IsTyping: isTyping}
+	events := make([]models.Event, 0, len(recipients))
+	for _, userID := range recipients {
+		id, err := uuid.NewV7()
+		if err != nil {
+			s.logger.Error("generate typing event id", slog.String("error", err.Error()))
+			return
+		}
+		events = append(events, models.Event{
+			ID:        id,
+			UserID:    userID,
+			ChatID:    &key.chatID,
+			Type:      models.EventTypeTyping,
+			Payload:   payload,
+			CreatedAt: now,
+		})
+	}
+
+	s.publish(ctx, events)
+}
+
+type typingKey struct {
+	chatID uuid.UUID
+	userID uuid.UUID
+}
+
+type typingState struct {
+	sentAt     time.Time
+	recipients []uuid.UUID
+	timer      *time.Timer
+	generation int
+}
+
+// typingTracker remembers who is typing where, so that SetTyping can
+// throttle repeated starts and report a stop once a user goes silent.
+type typingTracker struct {
+	throttle time.Duration
+	expiry   time.Duration
+	expired  func(key typingKey, recipients []uuid.UUID)
+
+	mu     sync.Mutex
+	active map[typingKey]*typingState
+}
+
+func newTypingTracker(
+	throttle, expiry time.Duration,
+	expired func(key typingKey, recipients []uuid.UUID),
+) *typingTracker {
+	return &typingTracker{
+		throttle: throttle,
+		expiry:   expiry,
+		expired:  expired,
+		active:   make(map[typingKey]*typingState),
+	}
+}
+
+// throttled reports whether the update changes nothing the other members
+// need to hear about: a start shortly after the previous one, or a stop of
+// a user who is not typing. A throttled start still postpones the expiry.
+func (t *typingTracker) throttled(key typingKey, isTyping bool, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.active[key]
+	if !isTyping {
+		return !ok
+	}
+	if !ok || now.Sub(state.sentAt) >= t.throttle {
+		return false
+	}
+
+	t.arm(key, state)
+
+	return true
+}
+
+func (t *typingTracker) start(key typingKey, recipients []uuid.UUID, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.active[key]
+	if !ok {
+		state = &typingState{}
+		t.active[key] = state
+	}
+	state.sentAt = now
+	state.recipients = recipients
+	t.arm(key, state)
+}
+
+func (t *typingTracker) stop(key typingKey) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if state, ok := t.active[key]; ok {
+		state.timer.Stop()
+		delete(t.active, key)
+	}
+}
+
+// arm restarts the expiry timer of the state. The generation tells a timer
+// that fires concurrently with a restart that it is stale.
+func (t *typingTracker) arm(key typingKey, state *typingState) {
+	if state.timer != nil {
+		state.timer.Stop()
+	}
+
+	state.generation++
+	generation := state.generation
+	state.timer = time.AfterFunc(t.expiry, func() {
+		t.expire(key, state, generation)
+	})
+}
+
+func (t *typingTracker) expire(key typingKey, state *typingState, generation int) {
+	t.mu.Lock()
+	if t.active[key] != state || state.generation != generation {
+		t.mu.Unlock()
+		return
+	}
+	delete(t.active, key)
+	t.mu.Unlock()
+
+	t.expired(key, state.recipients)
+}
@@ -0,0 +1,116 @@
+package chatservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+func typingReq(isTyping bool) models.SetTypingRequest {
+	return models.SetTypingRequest{ChatID: testChatID, UserID: testUserID, IsTyping: isTyping}
+}
+
+// expectTyping expects one typing event for every other member of the chat.
+func expectTyping(t *testing.T, m chatServiceMocks, isTyping bool) *gomock.Call {
+	return m.publisher.EXPECT().
+		Publish(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, events ...models.Event) error {
+			require.Len(t, events, 2)
+			assert.ElementsMatch(t, []uuid.UUID{testAdminID, testMemberID}, []uuid.UUID{events[0].UserID, events[1].UserID})
+			for _, e := range events {
+				assert.Equal(t, models.EventTypeTyping, e.Type)
+				assert.Equal(t, testChatID, *e.ChatID)
+				assert.Equal(t, models.TypingIndicatorPayload{
+					ChatID:   testChatID,
+					UserID:   testUserID,
+					IsTyping: isTyping,
+				}, e.Payload)
+			}
+			return nil
+		})
+}
+
+func TestChatService_SetTyping(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	members := []uuid.UUID{testUserID, testAdminID, testMemberID}
+
+	m.members.EXPECT().ListUserIDs(ctx, testChatID).Return(members, nil)
+	expectTyping(t, m, true)
+
+	require.NoError(t, service.SetTyping(ctx, typingReq(true)))
+
+	m.members.EXPECT().ListUserIDs(ctx, testChatID).Return(members, nil).Times(2)
+	expectTyping(t, m, false)
+
+	require.NoError(t, service.SetTyping(ctx, typingReq(false)))
+	require.NoError(t, service.SetTyping(ctx, typingReq(false)), "a second stop is not sent")
+}
+
+func TestChatService_SetTypingThrottle(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+	members := []uuid.UUID{testUserID, testAdminID, testMemberID}
+
+	m.members.EXPECT().ListUserIDs(ctx, testChatID).Return(members, nil).Times(3)
+	expectTyping(t, m, true).Times(2)
+
+	require.NoError(t, service.SetTyping(ctx, typingReq(true)))
+	require.NoError(t, service.SetTyping(ctx, typingReq(true)), "throttled")
+
+	service.now = func() time.Time { return testNow.Add(3 * time.Second) }
+	require.NoError(t, service.SetTyping(ctx, typingReq(true)))
+}
+
+func TestChatService_SetTypingExpires(t *testing.T) {
+	service, m := newTestChatService(t)
+	service.typing.expiry = 10 * time.Millisecond
+	ctx := context.Background()
+
+	m.members.EXPECT().ListUserIDs(ctx, testChatID).Return([]uuid.UUID{testUserID, testAdminID, testMemberID}, nil)
+	expectTyping(t, m, true)
+
+	stopped := make(chan struct{})
+	expectTyping(t, m, false).Do(func(context.Context, ...models.Event) { close(stopped) })
+
+	require.NoError(t, service.SetTyping(ctx, typingReq(true)))
+
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("typing did not expire")
+	}
+}
+
+func TestChatService_SetTypingNotMember(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.members.EXPECT().ListUserIDs(ctx, testChatID).Return([]uuid.UUID{testAdminID}, nil)
+
+	err := service.SetTyping(ctx, typingReq(true))
+
+	assert.ErrorIs(t, err, ErrNotMember)
+}
+
+func TestChatService_SetTypingThrottledAfterLeaving(t *testing.T) {
+	service, m := newTestChatService(t)
+	ctx := context.Background()
+
+	m.members.EXPECT().ListUserIDs(ctx, testChatID).Return([]uuid.UUID{testUserID, testAdminID, testMemberID}, nil)
+	expectTyping(t, m, true)
+	require.NoError(t, service.SetTyping(ctx, typingReq(true)))
+
+	m.members.EXPECT().ListUserIDs(ctx, testChatID).Return([]uuid.UUID{testAdminID, testMemberID}, nil)
+
+	err := service.SetTyping(ctx, typingReq(true))
+
+	assert.ErrorIs(t, err, ErrNotMember, "membership is checked before the throttle")
+}
@@ -0,0 +1,127 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: worker.go
+//
+// Generated by this command:
+//
+//	mockgen -source=worker.go -destination=mocks/mock_worker.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	models "github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	uuid "github.com/google/uuid"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MocksnapshotStore is a mock of snapshotStore interface.
+type MocksnapshotStore struct {
+	ctrl     *gomock.Controller
+	recorder *MocksnapshotStoreMockRecorder
+	isgomock struct{}
+}
+
+// MocksnapshotStoreMockRecorder is the mock recorder for MocksnapshotStore.
+type MocksnapshotStoreMockRecorder struct {
+	mock *MocksnapshotStore
+}
+
+// NewMocksnapshotStore creates a new mock instance.
+func NewMocksnapshotStore(ctrl *gomock.Controller) *MocksnapshotStore {
+	mock := &MocksnapshotStore{ctrl: ctrl}
+	mock.recorder = &MocksnapshotStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MocksnapshotStore) EXPECT() *MocksnapshotStoreMockRecorder {
+	return m.recorder
+}
+
+// Build mocks base method.
+func (m *MocksnapshotStore) Build(ctx context.Context, userID uuid.UUID) (models.Snapshot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Build", ctx, userID)
+	ret0, _ := ret[0].(models.Snapshot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Build indicates an expected call of Build.
+func (mr *MocksnapshotStoreMockRecorder) Build(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MocksnapshotStore)(nil).Build), ctx, userID)
+}
+
+// ListStale mocks base method.
+func (m *MocksnapshotStore) ListStale(ctx context.Context, minEvents, limit int32) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStale", ctx, minEvents, limit)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStale indicates an expected call of ListStale.
+func (mr *MocksnapshotStoreMockRecorder) ListStale(ctx, minEvents, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStale", reflect.TypeOf((*MocksnapshotStore)(nil).ListStale), ctx, minEvents, limit)
+}
+
+// Save mocks base method.
+func (m *MocksnapshotStore) Save(ctx context.Context, snapshot models.Snapshot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, snapshot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MocksnapshotStoreMockRecorder) Save(ctx, snapshot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MocksnapshotStore)(nil).Save), ctx, snapshot)
+}
+
+// MockeventLog is a mock of eventLog interface.
+type MockeventLog struct {
+	ctrl     *gomock.Controller
+	recorder *MockeventLogMockRecorder
+	isgomock struct{}
+}
+
+// MockeventLogMockRecorder is the mock recorder for MockeventLog.
+type MockeventLogMockRecorder struct {
+	mock *MockeventLog
+}
+
+// NewMockeventLog creates a new mock instance.
+func NewMockeventLog(ctrl *gomock.Controller) *MockeventLog {
+	mock := &MockeventLog{ctrl: ctrl}
+	mock.recorder = &MockeventLogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockeventLog) EXPECT() *MockeventLogMockRecorder {
+	return m.recorder
+}
+
+// CompactUserEvents mocks base method.
+func (m *MockeventLog) CompactUserEvents(ctx context.Context, userID uuid.UUID, through models.EventPosition, before time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompactUserEvents", ctx, userID, through, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompactUserEvents indicates an expected call of CompactUserEvents.
+func (mr *MockeventLogMockRecorder) CompactUserEvents(ctx, userID, through, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompactUserEvents", reflect.TypeOf((*MockeventLog)(nil).CompactUserEvents), ctx, userID, through, before)
+}
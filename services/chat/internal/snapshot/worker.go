@@ -0,0 +1,141 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+)
+
+//go:generate mockgen -source=worker.go -destination=mocks/mock_worker.go -package=mocks
+
+type (
+	snapshotStore interface {
+		ListStale(ctx context.Context, minEvents, limit int32) ([]uuid.UUID, error)
+		Build(ctx context.Context, userID uuid.UUID) (models.Snapshot, error)
+		Save(ctx context.Context, snapshot models.Snapshot) error
+	}
+
+	eventLog interface {
+		CompactUserEvents(
+			ctx context.Context,
+			userID uuid.UUID,
+			through models.EventPosition,
+			before time.Time,
+		) (int64, error)
+	}
+)
+
+type Config struct {
+	// Interval is how often the worker looks for users to snapshot.
+	Interval time.Duration
+	// MinEvents is how many events a user gathers after the last snapshot
+	// before a new one is taken.
+	MinEvents int32
+	// BatchSize bounds the users snapshotted in one round.
+	BatchSize int32
+	// CompactAfter is the age from which events a snapshot covers are
+	// deleted from the log. Zero keeps them.
+	CompactAfter time.Duration
+}
+
+// Worker periodically snapshots the users whose logs grew since their last
+// snapshot, and compacts the events the snapshots cover.
+type Worker struct {
+	snapshots snapshotStore
+	events    eventLog
+	config    Config
+	logger    *slog.Logger
+	now       func() time.Time
+}
+
+func NewWorker(snapshots snapshotStore, events eventLog, config Config, logger *slog.Logger) *Worker {
+	return &Worker{
+		snapshots: snapshots,
+		events:    events,
+		config:    config,
+		logger:    logger.With("layer", "snapshot worker"),
+		now:       time.Now,
+	}
+}
+
+// Run snapshots stale users every interval until ctx is done. A failed
+// round is logged and retried on the next tick.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				w.logger.Error("snapshot round", slog.String("error", err.Error()))
+			}
+		}
+	}
+}
+
+// RunOnce snapshots one batch of stale users and returns how many it
+// snapshotted. It goes on with the next user when one fails.
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	users, err := w.snapshots.ListStale(ctx, w.config.MinEvents, w.config.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var done int
+	for _, userID := range users {
+		if err := w.Snapshot(ctx, userID); err != nil {
+			if ctx.Err() != nil {
+				return done, ctx.Err()
+			}
+			w.logger.Error("snapshot user",
+				slog.String("user_id", userID.String()),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		done++
+	}
+
+	return done, nil
+}
+
+// Snapshot takes a snapshot of the user now and compacts the events it
+// covers that are old enough.
+func (w *Worker) Snapshot(ctx context.Context, userID uuid.UUID) error {
+	snapshot, err := w.snapshots.Build(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if snapshot.EventID == uuid.Nil {
+		return nil
+	}
+
+	if err := w.snapshots.Save(ctx, snapshot); err != nil {
+		return err
+	}
+
+	if w.config.CompactAfter <= 0 {
+		return nil
+	}
+
+	compacted, err := w.events.CompactUserEvents(ctx, userID, snapshot.Position, w.now().Add(-w.config.CompactAfter))
+	if err != nil {
+		return fmt.Errorf("compact events: %w", err)
+	}
+	if compacted > 0 {
+		w.logger.Debug("compacted events",
+			slog.String("user_id", userID.String()),
+			slog.Int64("count", compacted),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/models"
+	"github.com/BeInBloom/grpc-chat/services/chat/internal/snapshot/mocks"
+)
+
+var testNow = time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestWorker(t *testing.T, config Config) (*Worker, *mocks.MocksnapshotStore, *mocks.MockeventLog) {
+	t.Helper()
+
+	ctrl := gomock.NewController(t)
+	store := mocks.NewMocksnapshotStore(ctrl)
+	events := mocks.NewMockeventLog(ctrl)
+
+	worker := NewWorker(store, events, config, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	worker.now = func() time.Time { return testNow }
+
+	return worker, store, events
+}
+
+func TestWorker_RunOnceSnapshotsAndCompacts(t *testing.T) {
+	worker, store, events := newTestWorker(t, Config{MinEvents: 10, BatchSize: 5, CompactAfter: time.Hour})
+	ctx := context.Background()
+	userID := uuid.New()
+	snapshot := models.Snapshot{
+		UserID:   userID,
+		EventID:  uuid.Must(uuid.NewV7()),
+		Position: models.EventPosition{TxID: 7, Seq: 42},
+	}
+
+	store.EXPECT().ListStale(ctx, int32(10), int32(5)).Return([]uuid.UUID{userID}, nil)
+	store.EXPECT().Build(ctx, userID).Return(snapshot, nil)
+	store.EXPECT().Save(ctx, snapshot).Return(nil)
+	events.EXPECT().CompactUserEvents(ctx, userID, snapshot.Position, testNow.Add(-time.Hour)).Return(int64(3), nil)
+
+	done, err := worker.RunOnce(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, done)
+}
+
+func TestWorker_RunOnceKeepsEventsWithoutCompactAfter(t *testing.T) {
+	worker, store, _ := newTestWorker(t, Config{})
+	ctx := context.Background()
+	userID := uuid.New()
+	snapshot := models.Snapshot{UserID: userID, EventID: uuid.Must(uuid.NewV7())}
+
+	store.EXPECT().ListStale(ctx, int32(0), int32(0)).Return([]uuid.UUID{userID}, nil)
+	store.EXPECT().Build(ctx, userID).Return(snapshot, nil)
+	store.EXPECT().Save(ctx, snapshot).Return(nil)
+
+	done, err := worker.RunOnce(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, done)
+}
+
+func TestWorker_RunOnceSkipsUsersWithoutEvents(t *testing.T) {
+	worker, store, _ := newTestWorker(t, Config{CompactAfter: time.Hour})
+	ctx := context.Background()
+	userID := uuid.New()
+
+	store.EXPECT().ListStale(ctx, gomock.Any(), gomock.Any()).Return([]uuid.UUID{userID}, nil)
+	store.EXPECT().Build(ctx, userID).Return(models.Snapshot{UserID: userID}, nil)
+
+	done, err := worker.RunOnce(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, done)
+}
+
+func TestWorker_RunOnceGoesOnAfterFailure(t *testing.T) {
+	worker, store, _ := newTestWorker(t, Config{})
+	ctx := context.Background()
+	failing, next := uuid.New(), uuid.New()
+	snapshot := models.Snapshot{UserID: next, EventID: uuid.Must(uuid.NewV7())}
+
+	store.EXPECT().ListStale(ctx, gomock.Any(), gomock.Any()).Return([]uuid.UUID{failing, next}, nil)
+	store.EXPECT().Build(ctx, failing).Return(models.Snapshot{}, errors.New("db down"))
+	store.EXPECT().Build(ctx, next).Return(snapshot, nil)
+	store.EXPECT().Save(ctx, snapshot).Return(nil)
+
+	done, err := worker.RunOnce(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, done)
+}
+
+func TestWorker_RunOnceListFails(t *testing.T) {
+	worker, store, _ := newTestWorker(t, Config{})
+	ctx := context.Background()
+
+	store.EXPECT().ListStale(ctx, gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+	_, err := worker.RunOnce(ctx)
+
+	assert.ErrorIs(t, err, assert.AnError)
+}